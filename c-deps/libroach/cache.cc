@@ -24,3 +24,5 @@ DBCache* DBRefCache(DBCache* cache) {
 }
 
 void DBReleaseCache(DBCache* cache) { delete cache; }
+
+void DBSetCacheCapacity(DBCache* cache, uint64_t size) { cache->rep->SetCapacity(size); }
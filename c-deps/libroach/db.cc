@@ -26,6 +26,7 @@
 #include "env_manager.h"
 #include "eventlistener.h"
 #include "fmt.h"
+#include "gc_compaction_filter.h"
 #include "getter.h"
 #include "godefs.h"
 #include "incremental_iterator.h"
@@ -239,6 +240,11 @@ DBStatus DBOpen(DBEngine** db, DBSlice dir, DBOptions db_opts) {
   std::shared_ptr<DBEventListener> event_listener(new DBEventListener);
   options.listeners.emplace_back(event_listener);
 
+  // Install the compaction filter dropping the MVCC versions below the GC
+  // thresholds. It is a no-op until GC thresholds are set.
+  std::shared_ptr<GCThresholds> gc_thresholds(new GCThresholds);
+  options.compaction_filter_factory.reset(new GCCompactionFilterFactory(gc_thresholds));
+
   // Point rocksdb to the env to use.
   options.env = env_mgr->db_env;
 
@@ -254,7 +260,8 @@ DBStatus DBOpen(DBEngine** db, DBSlice dir, DBOptions db_opts) {
     return ToDBStatus(status);
   }
   *db = new DBImpl(db_ptr, std::move(env_mgr),
-                   db_opts.cache != nullptr ? db_opts.cache->rep : nullptr, event_listener);
+                   db_opts.cache != nullptr ? db_opts.cache->rep : nullptr, event_listener,
+                   gc_thresholds);
   return kSuccess;
 }
 
@@ -320,13 +327,16 @@ DBStatus DBCompact(DBEngine* db) {
 
 DBStatus DBCompactRange(DBEngine* db, DBSlice start, DBSlice end, bool force_bottommost) {
   rocksdb::CompactRangeOptions options;
-  // By default, RocksDB doesn't recompact the bottom level (unless
-  // there is a compaction filter, which we don't use). However,
-  // recompacting the bottom layer is necessary to pick up changes to
-  // settings like bloom filter configurations, and to fully reclaim
-  // space after dropping, truncating, or migrating tables.
+  // By default, RocksDB recompacts the bottom level only if there is a
+  // compaction filter, which is always the case as the GC compaction filter
+  // is installed, so it is made explicit. Recompacting the bottom layer is
+  // necessary to pick up changes to settings like bloom filter
+  // configurations, and to fully reclaim space after dropping, truncating,
+  // or migrating tables.
   if (force_bottommost) {
     options.bottommost_level_compaction = rocksdb::BottommostLevelCompaction::kForce;
+  } else {
+    options.bottommost_level_compaction = rocksdb::BottommostLevelCompaction::kSkip;
   }
   // By default, RocksDB treats manual compaction requests as
   // operating exclusively, preventing normal automatic compactions
@@ -440,6 +450,10 @@ DBStatus DBEnableAutoCompaction(DBEngine* db) {
   return ToDBStatus(status);
 }
 
+DBStatus DBSetGCThresholds(DBEngine* db, DBGCThreshold* thresholds, size_t len) {
+  return db->SetGCThresholds(thresholds, len);
+}
+
 DBStatus DBApproximateDiskBytes(DBEngine* db, DBKey start, DBKey end, uint64_t* size) {
   const std::string start_key(EncodeKey(start));
   const std::string end_key(EncodeKey(end));
@@ -24,6 +24,10 @@ DBEngine::~DBEngine() {}
 
 DBStatus DBEngine::AssertPreClose() { return kSuccess; }
 
+DBStatus DBEngine::SetGCThresholds(DBGCThreshold* thresholds, size_t len) {
+  return FmtStatus("unsupported");
+}
+
 DBSSTable* DBEngine::GetSSTables(int* n) {
   std::vector<rocksdb::LiveFileMetaData> metadata;
   rep->GetLiveFilesMetaData(&metadata);
@@ -110,12 +114,14 @@ DBString DBEngine::GetUserProperties() {
 namespace cockroach {
 
 DBImpl::DBImpl(rocksdb::DB* r, std::unique_ptr<EnvManager> e, std::shared_ptr<rocksdb::Cache> bc,
-               std::shared_ptr<DBEventListener> event_listener)
+               std::shared_ptr<DBEventListener> event_listener,
+               std::shared_ptr<GCThresholds> gc_thresholds)
     : DBEngine(r, &iters_count),
       env_mgr(std::move(e)),
       rep_deleter(r),
       block_cache(bc),
       event_listener(event_listener),
+      gc_thresholds(gc_thresholds),
       iters_count(0) {}
 
 DBImpl::~DBImpl() {
@@ -221,6 +227,7 @@ DBStatus DBImpl::GetStats(DBStatsResult* stats) {
   stats->table_readers_mem_estimate = table_readers_mem_estimate;
   stats->pending_compaction_bytes_estimate = pending_compaction_bytes_estimate;
   stats->l0_file_count = std::atoi(l0_file_count_str.c_str());
+  stats->write_stalls = (int64_t)event_listener->GetWriteStalls();
   return kSuccess;
 }
 
@@ -518,4 +525,17 @@ DBStatus DBImpl::EnvListDir(DBSlice name, std::vector<std::string>* result) {
   return ToDBStatus(this->rep->GetEnv()->GetChildren(ToString(name), result));
 }
 
+// SetGCThresholds replaces the GC thresholds used by the subsequent
+// compactions.
+DBStatus DBImpl::SetGCThresholds(DBGCThreshold* thresholds, size_t len) {
+  std::vector<GCThresholdSpan> spans;
+  spans.reserve(len);
+  for (size_t i = 0; i < len; i++) {
+    spans.push_back(GCThresholdSpan{ToString(thresholds[i].start), ToString(thresholds[i].end),
+                                    thresholds[i].threshold});
+  }
+  gc_thresholds->Set(rep, std::move(spans));
+  return kSuccess;
+}
+
 }  // namespace cockroach
@@ -17,6 +17,7 @@
 #include <rocksdb/env.h>
 #include <rocksdb/statistics.h>
 #include "eventlistener.h"
+#include "gc_compaction_filter.h"
 
 struct DBEngine {
   rocksdb::DB* const rep;
@@ -61,6 +62,7 @@ struct DBEngine {
   virtual DBStatus EnvCreateDir(DBSlice name) = 0;
   virtual DBStatus EnvDeleteDir(DBSlice name) = 0;
   virtual DBStatus EnvListDir(DBSlice name, std::vector<std::string>* result) = 0;
+  virtual DBStatus SetGCThresholds(DBGCThreshold* thresholds, size_t len);
   
   DBSSTable* GetSSTables(int* n);
   DBStatus GetSortedWALFiles(DBWALFile** out_files, int* n);
@@ -76,13 +78,15 @@ struct DBImpl : public DBEngine {
   std::unique_ptr<rocksdb::DB> rep_deleter;
   std::shared_ptr<rocksdb::Cache> block_cache;
   std::shared_ptr<DBEventListener> event_listener;
+  std::shared_ptr<GCThresholds> gc_thresholds;
   std::atomic<int64_t> iters_count;
 
   // Construct a new DBImpl from the specified DB.
   // The DB and passed Envs will be deleted when the DBImpl is deleted.
   // Either env can be NULL.
   DBImpl(rocksdb::DB* r, std::unique_ptr<EnvManager> e, std::shared_ptr<rocksdb::Cache> bc,
-         std::shared_ptr<DBEventListener> event_listener);
+         std::shared_ptr<DBEventListener> event_listener,
+         std::shared_ptr<GCThresholds> gc_thresholds);
   virtual ~DBImpl();
 
   virtual DBStatus AssertPreClose();
@@ -121,6 +125,7 @@ struct DBImpl : public DBEngine {
   virtual DBStatus EnvCreateDir(DBSlice name);
   virtual DBStatus EnvDeleteDir(DBSlice name);
   virtual DBStatus EnvListDir(DBSlice name, std::vector<std::string>* result);
+  virtual DBStatus SetGCThresholds(DBGCThreshold* thresholds, size_t len);
 };
 
 }  // namespace cockroach
@@ -13,7 +13,7 @@
 
 static const bool kDebug = false;
 
-DBEventListener::DBEventListener() : flushes_(0), compactions_(0) {}
+DBEventListener::DBEventListener() : flushes_(0), compactions_(0), write_stalls_(0) {}
 
 void DBEventListener::OnFlushCompleted(rocksdb::DB* db,
                                        const rocksdb::FlushJobInfo& flush_job_info) {
@@ -45,6 +45,17 @@ void DBEventListener::OnCompactionCompleted(rocksdb::DB* db, const rocksdb::Comp
   }
 }
 
+void DBEventListener::OnStallConditionsChanged(const rocksdb::WriteStallInfo& info) {
+  // Count the transitions out of the normal condition, whether writes are
+  // delayed or stopped.
+  if (info.condition.prev == rocksdb::WriteStallCondition::kNormal &&
+      info.condition.cur != rocksdb::WriteStallCondition::kNormal) {
+    ++write_stalls_;
+  }
+}
+
 uint64_t DBEventListener::GetFlushes() const { return flushes_.load(); }
 
 uint64_t DBEventListener::GetCompactions() const { return compactions_.load(); }
+
+uint64_t DBEventListener::GetWriteStalls() const { return write_stalls_.load(); }
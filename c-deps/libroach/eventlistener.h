@@ -24,14 +24,17 @@ class DBEventListener : public rocksdb::EventListener {
 
   uint64_t GetFlushes() const;
   uint64_t GetCompactions() const;
+  uint64_t GetWriteStalls() const;
 
   // EventListener methods.
   virtual void OnFlushCompleted(rocksdb::DB* db,
                                 const rocksdb::FlushJobInfo& flush_job_info) override;
   virtual void OnCompactionCompleted(rocksdb::DB* db,
                                      const rocksdb::CompactionJobInfo& ci) override;
+  virtual void OnStallConditionsChanged(const rocksdb::WriteStallInfo& info) override;
 
  private:
   std::atomic<uint64_t> flushes_;
   std::atomic<uint64_t> compactions_;
+  std::atomic<uint64_t> write_stalls_;
 };
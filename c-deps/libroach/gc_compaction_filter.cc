@@ -0,0 +1,153 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+#include "gc_compaction_filter.h"
+#include <algorithm>
+#include "encoding.h"
+#include "protos/storage/enginepb/mvcc.pb.h"
+
+namespace cockroach {
+
+namespace {
+
+bool TimestampLessOrEqual(DBTimestamp a, DBTimestamp b) {
+  return a.wall_time < b.wall_time || (a.wall_time == b.wall_time && a.logical <= b.logical);
+}
+
+// GCCompactionFilter is created for a single compaction (or subcompaction),
+// which is run by a single thread and passes the keys to the filter in
+// order. This allows the filter to keep track of the versions of the
+// current key.
+class GCCompactionFilter : public rocksdb::CompactionFilter {
+ public:
+  explicit GCCompactionFilter(std::shared_ptr<const GCThresholdSpans> spans)
+      : spans_(spans), candidate_(false), shadowed_(false) {}
+
+  virtual bool Filter(int level, const rocksdb::Slice& key, const rocksdb::Slice& existing_value,
+                      std::string* new_value, bool* value_changed) const override {
+    rocksdb::Slice user_key;
+    DBTimestamp ts;
+    if (!DecodeKey(key, &user_key, &ts)) {
+      return false;
+    }
+    if (user_key != rocksdb::Slice(cur_key_)) {
+      cur_key_.assign(user_key.data(), user_key.size());
+      candidate_ = false;
+      shadowed_ = false;
+    }
+    if (EmptyTimestamp(ts)) {
+      // Intents and inline values are never dropped.
+      return false;
+    }
+    if (shadowed_) {
+      return true;
+    }
+    if (candidate_) {
+      // The verification is deferred until an older version is found, so
+      // that the keys with a single version don't incur the reads.
+      if (IsCommitted(candidate_ts_)) {
+        shadowed_ = true;
+        return true;
+      }
+      candidate_ = false;
+    }
+    const GCThresholdSpan* span = FindSpan(user_key);
+    if (span != nullptr && TimestampLessOrEqual(ts, span->threshold)) {
+      candidate_ = true;
+      candidate_ts_ = ts;
+    }
+    return false;
+  }
+
+  // The versions visible to an open snapshot must be left in place, so that
+  // the snapshot reads the same data from the compacted files.
+  virtual bool IgnoreSnapshots() const override { return false; }
+
+  virtual const char* Name() const override { return "cockroach_gc_compaction_filter"; }
+
+ private:
+  // FindSpan returns the span containing key, or nullptr if there is none.
+  const GCThresholdSpan* FindSpan(const rocksdb::Slice& key) const {
+    const std::vector<GCThresholdSpan>& spans = spans_->spans;
+    auto it = std::upper_bound(spans.begin(), spans.end(), key,
+                               [](const rocksdb::Slice& k, const GCThresholdSpan& s) {
+                                 return k.compare(s.start) < 0;
+                               });
+    if (it == spans.begin()) {
+      return nullptr;
+    }
+    --it;
+    if (key.compare(it->end) >= 0) {
+      return nullptr;
+    }
+    return &*it;
+  }
+
+  // IsCommitted returns whether the version of the current key at the given
+  // timestamp is visible and isn't the provisional value of an intent. The
+  // intent is read first: a version which isn't provisional at that point
+  // can't become so, as intents are only written above the existing versions.
+  bool IsCommitted(DBTimestamp ts) const {
+    rocksdb::ReadOptions read_opts;
+    std::string value;
+    rocksdb::Status status =
+        spans_->db->Get(read_opts, EncodeKey(rocksdb::Slice(cur_key_), 0, 0), &value);
+    if (status.ok()) {
+      cockroach::storage::enginepb::MVCCMetadata meta;
+      if (!meta.ParseFromArray(value.data(), value.size())) {
+        return false;
+      }
+      if (meta.has_txn() && meta.timestamp().wall_time() == ts.wall_time &&
+          meta.timestamp().logical() == ts.logical) {
+        return false;
+      }
+    } else if (!status.IsNotFound()) {
+      return false;
+    }
+    const std::string version_key = EncodeKey(rocksdb::Slice(cur_key_), ts.wall_time, ts.logical);
+    return spans_->db->Get(read_opts, version_key, &value).ok();
+  }
+
+  const std::shared_ptr<const GCThresholdSpans> spans_;
+  mutable std::string cur_key_;
+  // The timestamp of the newest version of the current key at or below the
+  // GC threshold, which hasn't been verified to be committed yet.
+  mutable bool candidate_;
+  mutable DBTimestamp candidate_ts_;
+  // Whether the older versions of the current key are to be dropped.
+  mutable bool shadowed_;
+};
+
+}  // namespace
+
+void GCThresholds::Set(rocksdb::DB* db, std::vector<GCThresholdSpan> spans) {
+  std::sort(spans.begin(), spans.end(), [](const GCThresholdSpan& a, const GCThresholdSpan& b) {
+    return a.start < b.start;
+  });
+  std::shared_ptr<const GCThresholdSpans> s(new GCThresholdSpans{db, std::move(spans)});
+  std::lock_guard<std::mutex> guard(mu_);
+  spans_ = s;
+}
+
+std::shared_ptr<const GCThresholdSpans> GCThresholds::Get() const {
+  std::lock_guard<std::mutex> guard(mu_);
+  return spans_;
+}
+
+std::unique_ptr<rocksdb::CompactionFilter> GCCompactionFilterFactory::CreateCompactionFilter(
+    const rocksdb::CompactionFilter::Context& context) {
+  auto spans = thresholds_->Get();
+  if (spans == nullptr || spans->spans.empty()) {
+    return nullptr;
+  }
+  return std::unique_ptr<rocksdb::CompactionFilter>(new GCCompactionFilter(spans));
+}
+
+}  // namespace cockroach
@@ -0,0 +1,76 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+#pragma once
+
+#include <libroach.h>
+#include <memory>
+#include <mutex>
+#include <rocksdb/compaction_filter.h>
+#include <rocksdb/db.h>
+#include <string>
+#include <vector>
+
+namespace cockroach {
+
+// GCThresholdSpan is the GC threshold of a span [start, end) of the user
+// keys stored in an engine.
+struct GCThresholdSpan {
+  std::string start;
+  std::string end;
+  DBTimestamp threshold;
+};
+
+// GCThresholdSpans is a set of non-overlapping spans sorted by start key,
+// along with the DB they apply to.
+struct GCThresholdSpans {
+  rocksdb::DB* db;
+  std::vector<GCThresholdSpan> spans;
+};
+
+// GCThresholds holds the GC thresholds used by the compactions of an engine.
+class GCThresholds {
+ public:
+  void Set(rocksdb::DB* db, std::vector<GCThresholdSpan> spans);
+  std::shared_ptr<const GCThresholdSpans> Get() const;
+
+ private:
+  mutable std::mutex mu_;
+  std::shared_ptr<const GCThresholdSpans> spans_;
+};
+
+// GCCompactionFilterFactory creates the compaction filters which drop the
+// MVCC versions that are no longer visible at or above the GC threshold of
+// their key. A version is dropped when the compaction sees a newer version
+// of the same key at or below the GC threshold which is committed. As the
+// compaction may see versions which were removed by a newer deletion, such
+// as the provisional values moved by the resolution of an intent, the newer
+// version is verified to be visible and not to be the provisional value of
+// an intent by reading it from the DB.
+//
+// The filters don't ignore snapshots, so RocksDB doesn't pass them the
+// versions written before the latest open engine snapshot, which are thus
+// never dropped while it may still read them. The range's MVCC stats aren't
+// updated, and are instead marked as containing estimates by the GC requests
+// while the filter is enabled (see storage.CompactionGCer).
+class GCCompactionFilterFactory : public rocksdb::CompactionFilterFactory {
+ public:
+  explicit GCCompactionFilterFactory(std::shared_ptr<GCThresholds> thresholds)
+      : thresholds_(thresholds) {}
+
+  virtual std::unique_ptr<rocksdb::CompactionFilter>
+  CreateCompactionFilter(const rocksdb::CompactionFilter::Context& context) override;
+  virtual const char* Name() const override { return "cockroach_gc_compaction_filter_factory"; }
+
+ private:
+  std::shared_ptr<GCThresholds> thresholds_;
+};
+
+}  // namespace cockroach
@@ -83,11 +83,22 @@ typedef void* DBWritableFile;
 typedef void* DBReadableFile;
 typedef void* DBDirectory;
 
+// Values of DBOptions.compression.
+#define DB_COMPRESSION_DEFAULT 0
+#define DB_COMPRESSION_NONE 1
+#define DB_COMPRESSION_SNAPPY 2
+
 // DBOptions contains local database options.
 typedef struct {
   DBCache* cache;
   int num_cpu;
   int max_open_files;
+  // RocksDB tuning overrides, where zero selects the default. The
+  // compression is one of the DB_COMPRESSION_* constants.
+  uint64_t write_buffer_size;
+  int max_write_buffer_number;
+  int bloom_bits_per_key;
+  int compression;
   bool use_file_registry;
   bool must_exist;
   bool read_only;
@@ -107,6 +118,11 @@ DBCache* DBRefCache(DBCache* cache);
 // all of the references have been released.
 void DBReleaseCache(DBCache* cache);
 
+// Set the capacity of a cache, which is shared by all of its
+// references. Shrinking the cache evicts entries until its usage fits,
+// except for the entries pinned by open iterators.
+void DBSetCacheCapacity(DBCache* cache, uint64_t size);
+
 // Opens the database located in "dir", creating it if it doesn't
 // exist.
 DBStatus DBOpen(DBEngine** db, DBSlice dir, DBOptions options);
@@ -154,6 +170,19 @@ DBStatus DBCompactRange(DBEngine* db, DBSlice start, DBSlice end, bool force_bot
 DBStatus DBDisableAutoCompaction(DBEngine* db);
 DBStatus DBEnableAutoCompaction(DBEngine* db);
 
+// DBGCThreshold is the GC threshold of the span [start, end) of user keys.
+typedef struct {
+  DBSlice start;
+  DBSlice end;
+  DBTimestamp threshold;
+} DBGCThreshold;
+
+// Replaces the GC thresholds used by the compactions of the engine, which
+// drop the MVCC versions no longer visible at or above the GC threshold of
+// their key. The spans must not overlap. Passing no thresholds disables the
+// dropping of versions.
+DBStatus DBSetGCThresholds(DBEngine* db, DBGCThreshold* thresholds, size_t len);
+
 // Stores the approximate on-disk size of the given key range into the
 // supplied uint64.
 DBStatus DBApproximateDiskBytes(DBEngine* db, DBKey start, DBKey end, uint64_t* size);
@@ -388,6 +417,7 @@ typedef struct {
   int64_t table_readers_mem_estimate;
   int64_t pending_compaction_bytes_estimate;
   int64_t l0_file_count;
+  int64_t write_stalls;
 } DBStatsResult;
 
 typedef struct {
@@ -74,6 +74,51 @@ void SerializeTimeSeriesToValue(std::string* val,
   SetTag(val, cockroach::roachpb::TIMESERIES);
 }
 
+// IsIntData returns true if the given protobuffer Value contains an integer.
+bool IsIntData(const std::string& val) { return GetTag(val) == cockroach::roachpb::INT; }
+
+// ParseIntFromValue decodes the integer in the given Value, which is encoded
+// as a zig-zag varint (see binary.PutVarint in Go). Returns true if the
+// integer could be decoded.
+WARN_UNUSED_RESULT bool ParseIntFromValue(const std::string& val, int64_t* i) {
+  const rocksdb::Slice d = ValueDataBytes(val);
+  uint64_t v = 0;
+  for (size_t n = 0; n < d.size() && n < 10; n++) {
+    const uint8_t b = d[n];
+    v |= uint64_t(b & 0x7f) << (7 * n);
+    if (b < 0x80) {
+      *i = int64_t(v >> 1) ^ -int64_t(v & 1);
+      return true;
+    }
+  }
+  return false;
+}
+
+void SerializeIntToValue(std::string* val, int64_t i) {
+  val->resize(kHeaderSize);
+  std::fill(val->begin(), val->end(), 0);
+  SetTag(val, cockroach::roachpb::INT);
+  uint64_t v = (uint64_t(i) << 1) ^ uint64_t(i >> 63);
+  for (; v >= 0x80; v >>= 7) {
+    val->push_back(char(v | 0x80));
+  }
+  val->push_back(char(v));
+}
+
+// MergeIntValues sums two Values which contain integers. Returns true if the
+// merge is successful.
+WARN_UNUSED_RESULT bool MergeIntValues(std::string* left, const std::string& right,
+                                       rocksdb::Logger* logger) {
+  int64_t left_i, right_i;
+  if (!ParseIntFromValue(*left, &left_i) || !ParseIntFromValue(right, &right_i)) {
+    rocksdb::Warn(logger, "integer could not be parsed from bytes.");
+    return false;
+  }
+  // Overflow wraps around, consistently with the Go implementation.
+  SerializeIntToValue(left, int64_t(uint64_t(left_i) + uint64_t(right_i)));
+  return true;
+}
+
 // MergeTimeSeriesValues attempts to merge two Values which contain
 // InternalTimeSeriesData messages. The messages cannot be merged if they have
 // different start timestamps or sample durations. Returns true if the merge is
@@ -460,6 +505,7 @@ WARN_UNUSED_RESULT bool MergeValues(cockroach::storage::enginepb::MVCCMetadata*
     // for merging values to be "replayed". Currently, the only actual use of
     // the merge system is for time series data, which is safe against replay;
     // however, this property is not general for all potential mergeable types.
+    // In particular, a replayed integer merge is counted twice.
     // If a future need arises to merge another type of data, replay protection
     // will likely need to be a consideration.
 
@@ -472,6 +518,14 @@ WARN_UNUSED_RESULT bool MergeValues(cockroach::storage::enginepb::MVCCMetadata*
       }
       return MergeTimeSeriesValues(left->mutable_raw_bytes(), right.raw_bytes(), full_merge,
                                    logger);
+    } else if (IsIntData(left->raw_bytes()) || IsIntData(right.raw_bytes())) {
+      // The right operand must also be an integer.
+      if (!IsIntData(left->raw_bytes()) || !IsIntData(right.raw_bytes())) {
+        rocksdb::Warn(logger, "inconsistent value types for merging integers "
+                              "(type(left) != type(right))");
+        return false;
+      }
+      return MergeIntValues(left->mutable_raw_bytes(), right.raw_bytes(), logger);
     } else {
       const rocksdb::Slice rdata = ValueDataBytes(right.raw_bytes());
       left->mutable_raw_bytes()->append(rdata.data(), rdata.size());
@@ -241,6 +241,22 @@ rocksdb::Options DBMakeOptions(DBOptions db_opts) {
   // to be somewhat larger than than typical range size so that
   // deletion of a range worth of keys does not cause write stalls.
   options.max_write_buffer_number = 4;
+  if (db_opts.write_buffer_size > 0) {
+    options.write_buffer_size = db_opts.write_buffer_size;
+  }
+  if (db_opts.max_write_buffer_number > 0) {
+    options.max_write_buffer_number = db_opts.max_write_buffer_number;
+  }
+  switch (db_opts.compression) {
+  case DB_COMPRESSION_NONE:
+    options.compression = rocksdb::kNoCompression;
+    break;
+  case DB_COMPRESSION_SNAPPY:
+    options.compression = rocksdb::kSnappyCompression;
+    break;
+  default:
+    break;
+  }
   // Number of files to trigger L0 compaction. We set this low so that
   // we quickly move files out of L0 as each L0 file increases read
   // amplification.
@@ -339,7 +355,9 @@ rocksdb::Options DBMakeOptions(DBOptions db_opts) {
   // filter can be consulted before going to the index which saves an
   // index lookup. The cost is an 4-bytes per key in memory during
   // compactions, which seems a small price to pay.
-  table_options.filter_policy.reset(rocksdb::NewBloomFilterPolicy(10, false /* !block_based */));
+  const int bloom_bits_per_key = db_opts.bloom_bits_per_key > 0 ? db_opts.bloom_bits_per_key : 10;
+  table_options.filter_policy.reset(
+      rocksdb::NewBloomFilterPolicy(bloom_bits_per_key, false /* !block_based */));
   table_options.format_version = 2;
 
   // Increasing block_size decreases memory usage at the cost of
@@ -13,6 +13,7 @@ package base
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net/http"
 	"net/url"
 	"sync"
@@ -149,6 +150,13 @@ type Config struct {
 	SSLCAKey string
 	// SSLCertsDir is the path to the certificate/key directory.
 	SSLCertsDir string
+	// TLSSettings overrides the TLS version and cipher suites used by all
+	// TLS configs built from the certificates in SSLCertsDir.
+	TLSSettings security.TLSSettings
+	// CertificateAssets, if set, replaces the filesystem as the source of
+	// the certificates and keys in SSLCertsDir, e.g. to read them from a
+	// security.SecretStore.
+	CertificateAssets *security.AssetLoader
 
 	// User running this process. It could be the user under which
 	// the server is running or the user passed in client calls.
@@ -243,6 +251,8 @@ func (cfg *Config) InitDefaults() {
 	cfg.SQLAddr = defaultSQLAddr
 	cfg.SQLAdvertiseAddr = cfg.SQLAddr
 	cfg.SSLCertsDir = DefaultCertsDirectory
+	cfg.TLSSettings = security.TLSSettings{}
+	cfg.CertificateAssets = nil
 	cfg.certificateManager = lazyCertificateManager{}
 	cfg.RPCHeartbeatInterval = defaultRPCHeartbeatInterval
 	cfg.ClusterName = ""
@@ -348,8 +358,15 @@ func (cfg *Config) PGURL(user *url.Userinfo) (*url.URL, error) {
 // on the first call.
 func (cfg *Config) GetCertificateManager() (*security.CertificateManager, error) {
 	cfg.certificateManager.once.Do(func() {
+		var opts []security.Option
+		if cfg.CertificateAssets != nil {
+			opts = append(opts, security.WithAssetLoader(*cfg.CertificateAssets))
+		}
 		cfg.certificateManager.cm, cfg.certificateManager.err =
-			security.NewCertificateManager(cfg.SSLCertsDir)
+			security.NewCertificateManager(cfg.SSLCertsDir, opts...)
+		if cfg.certificateManager.err == nil {
+			cfg.certificateManager.cm.SetTLSSettings(cfg.TLSSettings)
+		}
 	})
 	return cfg.certificateManager.cm, cfg.certificateManager.err
 }
@@ -679,6 +696,52 @@ type StorageConfig struct {
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
+	// WALSync specifies when writes to the write-ahead log are synced to disk.
+	WALSync WALSyncMode
+}
+
+// WALSyncMode specifies when the writes to the write-ahead log of a storage
+// engine are synced to disk.
+type WALSyncMode int
+
+const (
+	// WALSyncAlways syncs the write-ahead log before acknowledging every
+	// commit which requests a sync. This is the default, and the only mode
+	// which guarantees that acknowledged writes survive a machine crash.
+	WALSyncAlways WALSyncMode = iota
+	// WALSyncPeriodic acknowledges commits as soon as they are written to
+	// the write-ahead log, which is synced in the background at a fixed
+	// interval. Writes acknowledged within the interval preceding a machine
+	// crash may be lost.
+	WALSyncPeriodic
+	// WALSyncNever never explicitly syncs the write-ahead log, leaving it to
+	// the operating system. It is only suitable for benchmarks and ephemeral
+	// clusters.
+	WALSyncNever
+)
+
+var walSyncModeNames = map[WALSyncMode]string{
+	WALSyncAlways:   "always",
+	WALSyncPeriodic: "periodic",
+	WALSyncNever:    "none",
+}
+
+// String implements the fmt.Stringer interface.
+func (m WALSyncMode) String() string {
+	if name, ok := walSyncModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("WALSyncMode(%d)", int(m))
+}
+
+// ParseWALSyncMode parses the name of a WALSyncMode.
+func ParseWALSyncMode(name string) (WALSyncMode, error) {
+	for m, n := range walSyncModeNames {
+		if n == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a valid WAL sync mode, expected one of always, periodic, none", name)
 }
 
 const (
@@ -176,11 +176,45 @@ type StoreSpec struct {
 	// RocksDBOptions contains RocksDB specific options using a semicolon
 	// separated key-value syntax ("key1=value1; key2=value2").
 	RocksDBOptions string
+	// RocksDBTuning overrides the default RocksDB tuning of the store.
+	RocksDBTuning RocksDBTuning
+	// WALSync specifies when the writes to the store's write-ahead log are
+	// synced to disk.
+	WALSync WALSyncMode
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
 }
 
+// RocksDBTuning contains the commonly tuned RocksDB parameters of a
+// store. Zero values select the built-in defaults.
+type RocksDBTuning struct {
+	// BlockCacheSize, if non-zero, gives the store a block cache of its
+	// own of the given size in bytes instead of sharing the node-wide
+	// cache sized by --cache.
+	BlockCacheSize int64
+	// WriteBufferSize is the size in bytes of each memtable.
+	WriteBufferSize int64
+	// MaxWriteBufferNumber is the maximum number of memtables, including
+	// those being flushed, which are kept in memory.
+	MaxWriteBufferNumber int
+	// BloomFilterBitsPerKey is the number of bits per key used by the
+	// sstable bloom filters.
+	BloomFilterBitsPerKey int
+	// Compression is the sstable block compression algorithm, one of
+	// RocksDBCompressionTypes.
+	Compression string
+}
+
+// RocksDBCompressionTypes lists the valid values of
+// RocksDBTuning.Compression.
+var RocksDBCompressionTypes = []string{"none", "snappy"}
+
+// IsEmpty returns whether all of the tuning parameters are defaulted.
+func (t RocksDBTuning) IsEmpty() bool {
+	return t == RocksDBTuning{}
+}
+
 // String returns a fully parsable version of the store spec.
 func (ss StoreSpec) String() string {
 	var buffer bytes.Buffer
@@ -206,6 +240,24 @@ func (ss StoreSpec) String() string {
 		}
 		fmt.Fprintf(&buffer, ",")
 	}
+	if t := ss.RocksDBTuning; t.BlockCacheSize > 0 {
+		fmt.Fprintf(&buffer, "block-cache-size=%s,", humanizeutil.IBytes(t.BlockCacheSize))
+	}
+	if t := ss.RocksDBTuning; t.WriteBufferSize > 0 {
+		fmt.Fprintf(&buffer, "write-buffer-size=%s,", humanizeutil.IBytes(t.WriteBufferSize))
+	}
+	if t := ss.RocksDBTuning; t.MaxWriteBufferNumber > 0 {
+		fmt.Fprintf(&buffer, "write-buffers=%d,", t.MaxWriteBufferNumber)
+	}
+	if t := ss.RocksDBTuning; t.BloomFilterBitsPerKey > 0 {
+		fmt.Fprintf(&buffer, "bloom-bits=%d,", t.BloomFilterBitsPerKey)
+	}
+	if t := ss.RocksDBTuning; t.Compression != "" {
+		fmt.Fprintf(&buffer, "compression=%s,", t.Compression)
+	}
+	if ss.WALSync != WALSyncAlways {
+		fmt.Fprintf(&buffer, "wal-sync=%s,", ss.WALSync)
+	}
 	// Trim the extra comma from the end if it exists.
 	if l := buffer.Len(); l > 0 {
 		buffer.Truncate(l - 1)
@@ -243,6 +295,11 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - block-cache-size, write-buffer-size, write-buffers, bloom-bits and
+//   compression override the RocksDB tuning of the store (see
+//   RocksDBTuning).
+// - wal-sync=always|periodic|none specifies when the writes to the
+//   write-ahead log are synced to disk (see WALSyncMode). Defaults to always.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
@@ -318,6 +375,49 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 			}
 		case "rocksdb":
 			ss.RocksDBOptions = value
+		case "block-cache-size", "write-buffer-size":
+			size, err := humanizeutil.ParseBytes(value)
+			if err != nil {
+				return StoreSpec{}, fmt.Errorf("could not parse %s: %v", field, err)
+			}
+			if size <= 0 {
+				return StoreSpec{}, fmt.Errorf("%s must be positive", field)
+			}
+			if field == "block-cache-size" {
+				ss.RocksDBTuning.BlockCacheSize = size
+			} else {
+				ss.RocksDBTuning.WriteBufferSize = size
+			}
+		case "write-buffers", "bloom-bits":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return StoreSpec{}, fmt.Errorf("could not parse %s: %v", field, err)
+			}
+			if n <= 0 {
+				return StoreSpec{}, fmt.Errorf("%s must be positive", field)
+			}
+			if field == "write-buffers" {
+				ss.RocksDBTuning.MaxWriteBufferNumber = n
+			} else {
+				ss.RocksDBTuning.BloomFilterBitsPerKey = n
+			}
+		case "compression":
+			value = strings.ToLower(value)
+			valid := false
+			for _, c := range RocksDBCompressionTypes {
+				valid = valid || c == value
+			}
+			if !valid {
+				return StoreSpec{}, fmt.Errorf("%s is not a valid compression type, expected one of %s",
+					value, strings.Join(RocksDBCompressionTypes, ", "))
+			}
+			ss.RocksDBTuning.Compression = value
+		case "wal-sync":
+			mode, err := ParseWALSyncMode(strings.ToLower(value))
+			if err != nil {
+				return StoreSpec{}, err
+			}
+			ss.WALSync = mode
 		default:
 			return StoreSpec{}, fmt.Errorf("%s is not a valid store field", field)
 		}
@@ -430,6 +530,15 @@ func (ssl *StoreSpecList) Set(value string) error {
 		ssl.Specs = []StoreSpec{spec}
 		ssl.updated = true
 	} else {
+		// Each on-disk store needs its own directory, which its engine locks
+		// when it's opened.
+		if !spec.InMemory {
+			for _, existing := range ssl.Specs {
+				if !existing.InMemory && existing.Path == spec.Path {
+					return fmt.Errorf("store path %s is used by more than one store", spec.Path)
+				}
+			}
+		}
 		ssl.Specs = append(ssl.Specs, spec)
 	}
 	return nil
@@ -472,6 +581,12 @@ func (jls *JoinListType) Set(value string) error {
 			// --join=a,,b  equivalent to --join=a,b
 			continue
 		}
+		if strings.Index(v, "://") > 0 {
+			// A peer discovery address such as k8s://<service>, which is
+			// validated when the resolvers are created.
+			*jls = append(*jls, v)
+			continue
+		}
 		// Try splitting the address. This validates the format
 		// of the address and tolerates a missing delimiter colon
 		// between the address and port number.
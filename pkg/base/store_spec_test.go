@@ -121,6 +121,25 @@ func TestNewStoreSpec(t *testing.T) {
 
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
+		{"path=/,block-cache-size=1GiB,write-buffer-size=64MiB,write-buffers=4,bloom-bits=16,compression=none", "", StoreSpec{
+			Path: "/",
+			RocksDBTuning: base.RocksDBTuning{
+				BlockCacheSize:        1 << 30,
+				WriteBufferSize:       64 << 20,
+				MaxWriteBufferNumber:  4,
+				BloomFilterBitsPerKey: 16,
+				Compression:           "none",
+			},
+		}},
+		{"path=/,compression=Snappy", "", StoreSpec{Path: "/", RocksDBTuning: base.RocksDBTuning{Compression: "snappy"}}},
+		{"path=/,compression=lz4", "lz4 is not a valid compression type, expected one of none, snappy", StoreSpec{}},
+		{"path=/,write-buffers=0", "write-buffers must be positive", StoreSpec{}},
+		{"path=/,bloom-bits=abc", `could not parse bloom-bits: strconv.Atoi: parsing "abc": invalid syntax`, StoreSpec{}},
+		{"path=/,block-cache-size=0", "block-cache-size must be positive", StoreSpec{}},
+		{"path=/,wal-sync=always", "", StoreSpec{Path: "/"}},
+		{"path=/,wal-sync=periodic", "", StoreSpec{Path: "/", WALSync: base.WALSyncPeriodic}},
+		{"path=/,wal-sync=None", "", StoreSpec{Path: "/", WALSync: base.WALSyncNever}},
+		{"path=/,wal-sync=sometimes", "sometimes is not a valid WAL sync mode, expected one of always, periodic, none", StoreSpec{}},
 
 		// all together
 		{"path=/mnt/hda1,attrs=hdd:ssd,size=20GiB", "", StoreSpec{
@@ -203,6 +222,8 @@ func TestJoinListType(t *testing.T) {
 		{"a:123,b", "--join=a:123 --join=b:", ""},
 		{"[::1]:123,b", "--join=[::1]:123 --join=b:", ""},
 		{"[::1,b", "", `address \[::1: missing ']' in address`},
+		{"k8s://crdb.default.svc,b", "--join=k8s://crdb.default.svc --join=b:", ""},
+		{"gce://crdb?zone=us-east1-b&port=26257", "--join=gce://crdb?zone=us-east1-b&port=26257", ""},
 	}
 
 	for _, test := range testData {
@@ -223,6 +244,24 @@ func TestJoinListType(t *testing.T) {
 	}
 }
 
+func TestStoreSpecListSet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var ssl base.StoreSpecList
+	require.NoError(t, ssl.Set("path=/mnt/hda1,attrs=hdd"))
+	require.NoError(t, ssl.Set("path=/mnt/ssd1,attrs=ssd"))
+	require.NoError(t, ssl.Set("type=mem,size=1GiB"))
+	require.NoError(t, ssl.Set("type=mem,size=1GiB"))
+	require.Len(t, ssl.Specs, 4)
+	require.Equal(t, []string{"hdd"}, ssl.Specs[0].Attributes.Attrs)
+	require.Equal(t, []string{"ssd"}, ssl.Specs[1].Attributes.Attrs)
+
+	// Two on-disk stores can't share a directory.
+	err := ssl.Set("path=/mnt/hda1,attrs=ssd")
+	require.EqualError(t, err, "store path /mnt/hda1 is used by more than one store")
+	require.Len(t, ssl.Specs, 4)
+}
+
 func TestStoreSpecListPreventedStartupMessage(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -136,6 +136,20 @@ type TestClusterArgs struct {
 	// A copy of an entry from this map will be copied to each individual server
 	// and potentially adjusted according to ReplicationMode.
 	ServerArgsPerNode map[int]TestServerArgs
+
+	// If true, the in-memory stores of the servers outlive them, so that
+	// stopped servers can be started again with TestCluster.RestartServer.
+	RestartableServers bool
+	// If true, the HLC of every server is driven by its own manual clock,
+	// which tests advance through TestCluster.ManualClock.
+	ManualClocks bool
+	// If true, network faults such as partitions can be injected between
+	// the servers through TestCluster.FaultInjector.
+	InjectNetworkFaults bool
+	// NetworkFaultSeed seeds the random decisions of the fault injector. If
+	// zero, a seed is picked at random and logged, so that a failing run can
+	// be replayed by setting it here.
+	NetworkFaultSeed int64
 }
 
 var (
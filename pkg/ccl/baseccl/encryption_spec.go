@@ -122,6 +122,11 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 			if err != nil {
 				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not parse rotation-duration value: %s", value)
 			}
+			// The rotation period is stored with a granularity of seconds, and a
+			// period of zero would rotate the data key on every file creation.
+			if es.RotationPeriod < time.Second {
+				return StoreEncryptionSpec{}, errors.Errorf("rotation-period must be at least 1s: %s", value)
+			}
 		default:
 			return StoreEncryptionSpec{}, fmt.Errorf("%s is not a valid enterprise-encryption field", field)
 		}
@@ -44,6 +44,9 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 		{"path=data,key=new.key,old-key=old.key,rotation-period=", "no value specified for rotation-period", StoreEncryptionSpec{}},
 		{"path=data,key=new.key,old-key=old.key,rotation-period=1", "could not parse rotation-duration value: 1: time: missing unit in duration 1", StoreEncryptionSpec{}},
 		{"path=data,key=new.key,old-key=old.key,rotation-period=1d", "could not parse rotation-duration value: 1d: time: unknown unit d in duration 1d", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=old.key,rotation-period=0s", "rotation-period must be at least 1s: 0s", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=old.key,rotation-period=500ms", "rotation-period must be at least 1s: 500ms", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=old.key,rotation-period=-1h", "rotation-period must be at least 1s: -1h", StoreEncryptionSpec{}},
 
 		// Good values.
 		{"path=/data,key=/new.key,old-key=/old.key", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod}},
@@ -313,11 +313,14 @@ func exportUsingGoIterator(
 		io.MaxTimestampHint = endTime
 		io.MinTimestampHint = startTime.Next()
 	}
-	iter := storage.NewMVCCIncrementalIterator(reader, storage.MVCCIncrementalIterOptions{
+	iter, err := storage.NewMVCCIncrementalIterator(reader, storage.MVCCIncrementalIterOptions{
 		IterOptions: io,
 		StartTime:   startTime,
 		EndTime:     endTime,
 	})
+	if err != nil {
+		return nil, err
+	}
 	defer iter.Close()
 	for iter.SeekGE(storage.MakeMVCCMetadataKey(startKey)); ; iterFn(iter) {
 		ok, err := iter.Valid()
@@ -12,6 +12,7 @@ package cli
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -35,6 +36,7 @@ var certificateLifetime time.Duration
 var allowCAKeyReuse bool
 var overwriteFiles bool
 var generatePKCS8Key bool
+var listCertsEnvPrefix string
 
 // A createCACert command generates a CA certificate and stores it
 // in the cert directory.
@@ -115,6 +117,8 @@ Generate a node certificate "<certs-dir>/node.crt" and key "<certs-dir>/node.key
 If --overwrite is true, any existing files are overwritten.
 
 At least one host should be passed in (either IP address or dns name).
+Hosts must not include a port, e.g. pass "node1.example.com" rather than
+"node1.example.com:26257".
 
 Requires a CA cert in "<certs-dir>/ca.crt" and matching key in "--ca-key".
 If "ca.crt" contains more than one certificate, the first is used.
@@ -124,11 +128,26 @@ Creation fails if the CA expiration time is before the desired certificate expir
 		if len(args) == 0 {
 			return errors.Errorf("create-node requires at least one host name or address, none was specified")
 		}
-		return nil
+		return validateNodeCertHosts(args)
 	},
 	RunE: MaybeDecorateGRPCError(runCreateNodeCert),
 }
 
+// validateNodeCertHosts checks that each host can be used as a subject
+// alternative name. A common mistake is to pass the --advertise-addr value
+// as-is, which yields a certificate that no client will ever match against.
+func validateNodeCertHosts(hosts []string) error {
+	for _, h := range hosts {
+		if net.ParseIP(h) != nil {
+			continue
+		}
+		if h == "" || strings.ContainsAny(h, ":/ ") {
+			return errors.Errorf("invalid host %q: expected a host name or IP address without a port or scheme", h)
+		}
+	}
+	return nil
+}
+
 // runCreateNodeCert generates key pair and CA certificate and writes them
 // to their corresponding files.
 // TODO(marc): there is currently no way to specify which CA cert to use if more
@@ -203,6 +222,13 @@ List certificates and keys found in the certificate directory.
 
 // runListCerts loads and lists all certs.
 func runListCerts(cmd *cobra.Command, args []string) error {
+	if listCertsEnvPrefix != "" {
+		// List the certificates the way a node started with the same
+		// --certs-env-prefix would load them.
+		assets := security.SecretStoreAssetLoader(
+			security.EnvSecretStore{Prefix: listCertsEnvPrefix}, security.GetAssetLoader())
+		baseCfg.CertificateAssets = &assets
+	}
 	cm, err := baseCfg.GetCertificateManager()
 	if err != nil {
 		return errors.Wrap(err, "could not get certificate manager")
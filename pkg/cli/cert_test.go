@@ -0,0 +1,40 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestValidateNodeCertHosts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		hosts    []string
+		expected string
+	}{
+		{[]string{"localhost", "127.0.0.1", "::1", "node1.example.com"}, ""},
+		{[]string{"*.example.com"}, ""},
+		{[]string{"localhost:26257"}, `invalid host "localhost:26257"`},
+		{[]string{"[::1]:26257"}, `invalid host "\[::1\]:26257"`},
+		{[]string{"https://node1"}, `invalid host "https://node1"`},
+		{[]string{"localhost", ""}, `invalid host ""`},
+	}
+	for _, tc := range testCases {
+		err := validateNodeCertHosts(tc.hosts)
+		if !testutils.IsError(err, tc.expected) {
+			t.Errorf("%v: expected %q, got %v", tc.hosts, tc.expected, err)
+		}
+	}
+}
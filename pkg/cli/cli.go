@@ -196,9 +196,13 @@ func init() {
 
 		sqlShellCmd,
 		authCmd,
+		userCmd,
 		nodeCmd,
 		dumpCmd,
 		nodeLocalCmd,
+		zoneCmd,
+		kvCmd,
+		loadCmd,
 
 		// Miscellaneous commands.
 		// TODO(pmattis): stats
@@ -292,7 +292,7 @@ func isSQLCommand(args []string) bool {
 		return false
 	}
 	switch args[0] {
-	case "sql", "dump", "workload", "nodelocal":
+	case "sql", "dump", "workload", "nodelocal", "zone":
 		return true
 	case "node":
 		if len(args) == 0 {
@@ -1391,10 +1391,14 @@ Available Commands:
 
   sql               open a sql shell
   auth-session      log in and out of HTTP sessions
+  user              get, set, list and remove users
   node              list, inspect, drain or remove nodes
   dump              dump sql tables
 
   nodelocal         upload and delete nodelocal files
+  zone              list, get, set and remove zone configurations
+  kv                get, put, delete and scan raw keys
+  load              generate load through the key-value API
   demo              open a demo sql shell
   gen               generate auxiliary files
   version           output version information
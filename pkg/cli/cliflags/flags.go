@@ -366,6 +366,16 @@ or both forms can be used together, for example:
 
   --join=localhost:1234,localhost:2345 --join=localhost:3456
 
+</PRE>
+Instead of a fixed list, the peers can be discovered from a Kubernetes
+headless service, the EC2 instances carrying a tag, or a GCE instance
+group, for example:
+<PRE>
+
+  --join=k8s://cockroachdb.default.svc.cluster.local
+  --join=ec2://cockroachdb-cluster=prod?region=us-east-1
+  --join=gce://cockroachdb?zone=us-east1-b&port=26257
+
 </PRE>`,
 	}
 
@@ -553,6 +563,36 @@ After the CockroachDB node has started up successfully, it will
 write its process ID to the specified file.`,
 	}
 
+	ConfigFile = FlagInfo{
+		Name: "config-file",
+		Description: `
+Read server flags from the specified YAML file. Each top-level key
+names a flag without its leading dashes, for example:
+<PRE>
+
+  store: [/mnt/ssd01, /mnt/ssd02]
+  join: [host1:26257, host2:26257]
+  cache: .25
+
+</PRE>
+Flags specified on the command line or via environment variables
+take precedence over values in the file. Flags that may be repeated,
+like --store, accept a list of values.
+<PRE>
+
+</PRE>
+The file is read again when the process receives SIGHUP, or when the
+ReloadConfig admin RPC is called (POST /_admin/v1/reload_config), and
+changes to --vmodule, --logtostderr, --log-file-verbosity, --cache and
+--rpc-heartbeat-interval take effect immediately. Each change is
+recorded in the log. The --cache change is refused, and takes effect
+at the next restart, if a store uses a block cache of its own or an
+engine whose cache can't be resized. Changes to other flags, including
+--max-sql-memory, are logged and ignored until the next restart. Rate
+limits, like kv.snapshot_rebalance.max_rate, are cluster settings and
+are changed with SET CLUSTER SETTING instead.`,
+	}
+
 	Socket = FlagInfo{
 		Name:   "socket",
 		EnvVar: "COCKROACH_SOCKET",
@@ -650,6 +690,17 @@ Instead, require the user to always specify access keys.`,
 		Description: CertsDir.Description,
 	}
 
+	CertsEnvPrefix = FlagInfo{
+		Name: "certs-env-prefix",
+		Description: `
+If set, certificates and keys are also read from the environment variables
+whose name starts with this prefix, followed by the name of the file they
+replace, e.g. COCKROACH_CERT_node.key. This avoids storing keys on disk in
+containerized deployments. Variables take precedence over the files in the
+certificates directory.
+`,
+	}
+
 	CertPrincipalMap = FlagInfo{
 		Name: "cert-principal-map",
 		Description: `
@@ -667,6 +718,24 @@ fields.
 `,
 	}
 
+	TLSMinVersion = FlagInfo{
+		Name: "tls-min-version",
+		Description: `
+The minimum TLS version accepted for RPC, SQL and HTTP connections, either 1.2
+or 1.3. Defaults to 1.2.
+`,
+	}
+
+	TLSCipherSuites = FlagInfo{
+		Name: "tls-cipher-suites",
+		Description: `
+A comma separated list of the cipher suites to allow for TLS 1.2 connections,
+using their IANA names, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Only
+suites which are enabled by default can be listed. TLS 1.3 connections always
+use the TLS 1.3 cipher suites. Defaults to all suites enabled by default.
+`,
+	}
+
 	CAKey = FlagInfo{
 		Name:        "ca-key",
 		EnvVar:      "COCKROACH_CA_KEY",
@@ -702,6 +771,15 @@ change it, all nodes in the cluster must be stopped simultaneously and restarted
 with the new value.`,
 	}
 
+	RPCHeartbeatInterval = FlagInfo{
+		Name: "rpc-heartbeat-interval",
+		Description: `
+How often the node heartbeats each of its RPC connections, to check their
+health and measure the clock offsets of the remote nodes. The heartbeats
+time out after twice the initial interval. The interval can be changed while
+the node is running through --config-file.`,
+	}
+
 	Store = FlagInfo{
 		Name:      "store",
 		Shorthand: "s",
@@ -759,6 +837,29 @@ memory that the store may consume, for example:
   --store=type=mem,size=20GiB
   --store=type=mem,size=90%
 
+</PRE>
+The RocksDB tuning of each store can be overridden using the
+"block-cache-size", "write-buffer-size", "write-buffers" (the maximum number
+of write buffers), "bloom-bits" (bloom filter bits per key) and
+"compression" (none or snappy) fields. A store with a
+"block-cache-size" uses a block cache of its own instead of sharing the
+cache sized by --cache, for example:
+<PRE>
+
+  --store=path=/mnt/ssd01,block-cache-size=4GiB,write-buffer-size=128MiB,write-buffers=6
+  --store=path=/mnt/hda1,bloom-bits=16,compression=none
+
+</PRE>
+The "wal-sync" field specifies when the writes to the store's write-ahead log
+are synced to disk: "always" (the default) syncs before acknowledging each
+write, "periodic" syncs in the background once per second, and "none" leaves
+syncing to the operating system. Only "always" guarantees that acknowledged
+writes survive a machine crash; the other modes are intended for benchmarks
+and ephemeral clusters, for example:
+<PRE>
+
+  --store=path=/mnt/ssd01,wal-sync=none
+
 </PRE>
 Commas are forbidden in all values, since they are used to separate fields.
 Also, if you use equal signs in the file path to a store, you must use the
@@ -883,6 +984,14 @@ is the prefix for range local keys. The hex format takes an encoded MVCCKey.`}
 		Description: "Restrict scan to replicated data.",
 	}
 
+	ZoneConfig = FlagInfo{
+		Name:      "file",
+		Shorthand: "f",
+		Description: `
+File to read the zone configuration from, in YAML format. Use "-" to
+read from standard input.`,
+	}
+
 	GossipInputFile = FlagInfo{
 		Name:      "file",
 		Shorthand: "f",
@@ -1132,4 +1241,55 @@ Addresses for network benchmark.`,
 		Description: `
 Latency or throughput mode.`,
 	}
+
+	LoadMix = FlagInfo{
+		Name: "mix",
+		Description: `
+Mix of operations to issue: read-heavy (95% reads, 5% writes, like YCSB B),
+write-heavy (50% reads, 50% writes, like YCSB A) or scan (95% scans, 5% writes,
+like YCSB E).`,
+	}
+
+	LoadDistribution = FlagInfo{
+		Name: "key-distribution",
+		Description: `
+Distribution of the keys operated on: uniform, zipfian or sequential.`,
+	}
+
+	LoadKeys = FlagInfo{
+		Name:        "keys",
+		Description: `Number of distinct keys operated on.`,
+	}
+
+	LoadValueSize = FlagInfo{
+		Name:        "value-size",
+		Description: `Size of the values written.`,
+	}
+
+	LoadScanLength = FlagInfo{
+		Name:        "scan-length",
+		Description: `Maximum number of rows read by each scan.`,
+	}
+
+	LoadKeyPrefix = FlagInfo{
+		Name:        "key-prefix",
+		Description: `Prefix of all the keys operated on.`,
+	}
+
+	LoadSeed = FlagInfo{
+		Name:        "seed",
+		Description: `Random seed of the load.`,
+	}
+
+	LoadInit = FlagInfo{
+		Name:        "init",
+		Description: `Write every key once before starting the load.`,
+	}
+
+	LoadHistograms = FlagInfo{
+		Name: "histograms",
+		Description: `
+File to write the latency histograms of every second to, as JSON. The format
+is that of the --histograms flag of cockroach workload run.`,
+	}
 )
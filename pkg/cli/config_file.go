@@ -0,0 +1,293 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/cliflags"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logflags"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// reloadableFlags are the flags which can be changed in the config file
+// while the server is running. The logging flags take effect as soon as
+// they are set, since the logging settings are safe to update
+// concurrently. The other flags are applied to the running server by the
+// apply function of the reload. Other tunables like rate limits are
+// cluster settings, which can be changed at any time with SET CLUSTER
+// SETTING.
+var reloadableFlags = map[string]bool{
+	logflags.VModuleName:               true,
+	logflags.LogToStderrName:           true,
+	cliflags.LogFileVerbosity.Name:     true,
+	cliflags.Cache.Name:                true,
+	cliflags.RPCHeartbeatInterval.Name: true,
+}
+
+// applyConfigFile reads the YAML file at the given path and uses it to set
+// the flags in f which were not already specified on the command line or
+// through an environment variable. The file must contain a single mapping
+// from flag names to values; list values are applied one at a time, for
+// flags that can be repeated. An empty path is a no-op.
+//
+// The flags set from the file are returned along with their values, for
+// use by reloadConfigFile.
+func applyConfigFile(f *pflag.FlagSet, path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading config file")
+	}
+	return applyConfig(f, path, contents)
+}
+
+// applyConfig is the part of applyConfigFile that processes the file
+// contents. The path is only used in error messages.
+func applyConfig(f *pflag.FlagSet, path string, contents []byte) (map[string]string, error) {
+	cfg, names, err := parseConfig(f, path, contents)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]string)
+	for _, name := range names {
+		if f.Lookup(name).Changed {
+			// The command line takes precedence.
+			continue
+		}
+		values, err := configValues(path, name, cfg[name])
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if err := f.Set(name, v); err != nil {
+				return nil, errors.Wrapf(err, "config file %s: invalid value for field %q", path, name)
+			}
+		}
+		applied[name] = fmt.Sprint(values)
+	}
+	return applied, nil
+}
+
+// parseConfig parses the contents of a config file and checks that every
+// field names a flag in f. The field names are returned in sorted order.
+func parseConfig(
+	f *pflag.FlagSet, path string, contents []byte,
+) (map[string]interface{}, []string, error) {
+	var cfg map[string]interface{}
+	if err := yaml.UnmarshalStrict(contents, &cfg); err != nil {
+		return nil, nil, errors.Wrapf(err, "parsing config file %s", path)
+	}
+
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		if name == cliflags.ConfigFile.Name {
+			return nil, nil, errors.Newf("config file %s: field %q cannot be used in a config file", path, name)
+		}
+		if f.Lookup(name) == nil {
+			return nil, nil, errors.Newf("config file %s: unknown field %q", path, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return cfg, names, nil
+}
+
+// configValues converts the value of a config file field to the flag
+// values it stands for.
+func configValues(path, name string, value interface{}) ([]string, error) {
+	var values []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		values = v
+	default:
+		values = []interface{}{v}
+	}
+	res := make([]string, len(values))
+	for i, v := range values {
+		switch v.(type) {
+		case nil, []interface{}, map[interface{}]interface{}:
+			return nil, errors.Newf("config file %s: field %q: unsupported value %v", path, name, v)
+		}
+		res[i] = fmt.Sprint(v)
+	}
+	return res, nil
+}
+
+// reloadConfigFile re-reads the config file at the given path and applies
+// the changes made to reloadable flags since it was last read, as recorded
+// in applied, which is updated accordingly. After setting a flag, apply is
+// called with its name, if set, to apply the new value to the running
+// server. Every change is logged, and the names of the changed flags are
+// returned. Changes to other flags are logged and ignored, as are flags
+// that were specified on the command line. Removing a field from the file
+// does not revert the flag to its default.
+func reloadConfigFile(
+	ctx context.Context,
+	f *pflag.FlagSet,
+	path string,
+	applied map[string]string,
+	apply func(ctx context.Context, name string) error,
+) ([]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading config file")
+	}
+	return reloadConfig(ctx, f, path, contents, applied, apply)
+}
+
+// reloadConfig is the part of reloadConfigFile that processes the file
+// contents.
+//
+// If apply fails, the flag keeps its new value, which takes effect at the
+// next restart, and the remaining changes are still made. The errors are
+// returned along with the names of all the changed flags.
+func reloadConfig(
+	ctx context.Context,
+	f *pflag.FlagSet,
+	path string,
+	contents []byte,
+	applied map[string]string,
+	apply func(ctx context.Context, name string) error,
+) ([]string, error) {
+	cfg, names, err := parseConfig(f, path, contents)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	var applyErr error
+	for _, name := range names {
+		flag := f.Lookup(name)
+		prev, fromFile := applied[name]
+		if !fromFile && flag.Changed {
+			continue
+		}
+		values, err := configValues(path, name, cfg[name])
+		if err != nil {
+			return changed, errors.CombineErrors(err, applyErr)
+		}
+		if fromFile && prev == fmt.Sprint(values) {
+			continue
+		}
+		if !reloadableFlags[name] {
+			log.Warningf(ctx, "config file %s: ignoring new value of --%s, which requires a restart", path, name)
+			continue
+		}
+		if len(values) != 1 {
+			return changed, errors.CombineErrors(
+				errors.Newf("config file %s: field %q: unsupported value %v", path, name, cfg[name]), applyErr)
+		}
+		old := flag.Value.String()
+		if err := f.Set(name, values[0]); err != nil {
+			return changed, errors.CombineErrors(
+				errors.Wrapf(err, "config file %s: invalid value for field %q", path, name), applyErr)
+		}
+		applied[name] = fmt.Sprint(values)
+		changed = append(changed, name)
+		log.Infof(ctx, "config file %s: changed --%s from %q to %q", path, name, old, flag.Value.String())
+		if apply != nil {
+			if err := apply(ctx, name); err != nil {
+				applyErr = errors.CombineErrors(applyErr, errors.Wrapf(err,
+					"config file %s: the new value of --%s takes effect at the next restart", path, name))
+			}
+		}
+	}
+	return changed, applyErr
+}
+
+// applyReloadedFlag applies the new value of a reloadable flag, as set in
+// serverCfg, to the running server. The logging flags need not be applied.
+func applyReloadedFlag(s *server.Server, name string) error {
+	switch name {
+	case cliflags.Cache.Name:
+		return s.SetCacheSize(serverCfg.CacheSize)
+	case cliflags.RPCHeartbeatInterval.Name:
+		if serverCfg.RPCHeartbeatInterval <= 0 {
+			return errors.Newf("invalid --%s %s: must be positive",
+				name, serverCfg.RPCHeartbeatInterval)
+		}
+		s.SetRPCHeartbeatInterval(serverCfg.RPCHeartbeatInterval)
+	}
+	return nil
+}
+
+// configReloader reloads the config file of a running server. The reloads
+// triggered by SIGHUP and by the ReloadConfig admin RPC are serialized.
+type configReloader struct {
+	f    *pflag.FlagSet
+	path string
+	// apply applies the new value of a reloadable flag to the running
+	// server. See reloadConfigFile.
+	apply func(ctx context.Context, name string) error
+
+	mu struct {
+		syncutil.Mutex
+		// applied records the flags set from the file, as returned by
+		// applyConfigFile.
+		applied map[string]string
+	}
+}
+
+func newConfigReloader(
+	f *pflag.FlagSet,
+	path string,
+	applied map[string]string,
+	apply func(ctx context.Context, name string) error,
+) *configReloader {
+	r := &configReloader{f: f, path: path, apply: apply}
+	if applied == nil {
+		applied = make(map[string]string)
+	}
+	r.mu.applied = applied
+	return r
+}
+
+// reload calls reloadConfigFile.
+func (r *configReloader) reload(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return reloadConfigFile(ctx, r.f, r.path, r.mu.applied, r.apply)
+}
+
+// reloadOnSignal starts a goroutine which reloads the config file whenever
+// a signal is received on the given channel, as returned by
+// sysutil.RefreshSignaledChan, until the stopper is stopped. The channel
+// should be obtained before the server starts, so that the signals
+// received in the meantime are not lost.
+func (r *configReloader) reloadOnSignal(
+	ctx context.Context, stopper *stop.Stopper, ch <-chan os.Signal,
+) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-stopper.ShouldStop():
+				return
+			case sig := <-ch:
+				log.Infof(ctx, "received signal %q, reloading config file %s", sig, r.path)
+				if _, err := r.reload(ctx); err != nil {
+					log.Warningf(ctx, "could not reload config file: %v", err)
+				}
+			}
+		}
+	})
+}
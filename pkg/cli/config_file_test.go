@@ -0,0 +1,241 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/cliflags"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logflags"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var (
+		attrs     string
+		pidFile   string
+		insecure  bool
+		drainWait time.Duration
+		principal []string
+	)
+	makeFlags := func() *pflag.FlagSet {
+		attrs, pidFile, insecure, drainWait, principal = "", "", false, 0, nil
+		f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		StringFlag(f, &attrs, cliflags.Attrs, "")
+		StringFlag(f, &pidFile, cliflags.PIDFile, "")
+		BoolFlag(f, &insecure, cliflags.ServerInsecure, false)
+		DurationFlag(f, &drainWait, cliflags.DrainWait, 0)
+		StringSlice(f, &principal, cliflags.CertPrincipalMap, nil)
+		return f
+	}
+
+	const cfg = `
+attrs: ssd
+pid-file: /tmp/pid
+insecure: true
+drain-wait: 1m
+cert-principal-map: [a:b, c:d]
+`
+	f := makeFlags()
+	values, err := applyConfig(f, "test.yaml", []byte(cfg))
+	require.NoError(t, err)
+	require.Equal(t, "ssd", attrs)
+	require.Equal(t, "/tmp/pid", pidFile)
+	require.True(t, insecure)
+	require.Equal(t, time.Minute, drainWait)
+	require.Equal(t, []string{"a:b", "c:d"}, principal)
+	require.Equal(t, "[ssd]", values["attrs"])
+	require.Equal(t, "[a:b c:d]", values["cert-principal-map"])
+
+	// Flags specified on the command line take precedence.
+	f = makeFlags()
+	require.NoError(t, f.Parse([]string{"--attrs=hdd"}))
+	values, err = applyConfig(f, "test.yaml", []byte(cfg))
+	require.NoError(t, err)
+	require.Equal(t, "hdd", attrs)
+	require.Equal(t, "/tmp/pid", pidFile)
+	require.NotContains(t, values, "attrs")
+
+	testCases := []struct {
+		cfg string
+		err string
+	}{
+		{`bogus: 1`, `config file test.yaml: unknown field "bogus"`},
+		{`drain-wait: forever`, `config file test.yaml: invalid value for field "drain-wait"`},
+		{`insecure: {a: b}`, `config file test.yaml: field "insecure": unsupported value`},
+		{`config-file: other.yaml`, `field "config-file" cannot be used in a config file`},
+		{`[attrs]`, `parsing config file test.yaml`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.cfg, func(t *testing.T) {
+			_, err := applyConfig(makeFlags(), "test.yaml", []byte(tc.cfg))
+			require.True(t, testutils.IsError(err, tc.err), "expected %q, got %v", tc.err, err)
+		})
+	}
+
+	values, err = applyConfigFile(makeFlags(), "")
+	require.NoError(t, err)
+	require.Nil(t, values)
+}
+
+func TestReloadConfigFile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	var attrs, vmodule, verbosity, stderr string
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	StringFlag(f, &attrs, cliflags.Attrs, "")
+	// Use plain strings for the logging flags so the test doesn't change
+	// the logging configuration of the process.
+	f.StringVar(&vmodule, logflags.VModuleName, "", "")
+	f.StringVar(&verbosity, cliflags.LogFileVerbosity.Name, "", "")
+	f.StringVar(&stderr, logflags.LogToStderrName, "", "")
+
+	require.NoError(t, f.Parse([]string{"--logtostderr=ERROR"}))
+	values, err := applyConfig(f, "test.yaml", []byte(`
+attrs: ssd
+vmodule: raft=1
+`))
+	require.NoError(t, err)
+
+	// Reloadable flags are updated, other changes are ignored, and flags
+	// specified on the command line keep their value.
+	changed, err := reloadConfig(ctx, f, "test.yaml", []byte(`
+attrs: hdd
+vmodule: raft=2
+log-file-verbosity: WARNING
+logtostderr: INFO
+`), values, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{cliflags.LogFileVerbosity.Name, logflags.VModuleName}, changed)
+	require.Equal(t, "ssd", attrs)
+	require.Equal(t, "raft=2", vmodule)
+	require.Equal(t, "WARNING", verbosity)
+	require.Equal(t, "ERROR", stderr)
+	require.Equal(t, "[raft=2]", values[logflags.VModuleName])
+
+	// Removing a field leaves the flag unchanged.
+	changed, err = reloadConfig(ctx, f, "test.yaml", []byte(`vmodule: raft=2`), values, nil)
+	require.NoError(t, err)
+	require.Empty(t, changed)
+	require.Equal(t, "WARNING", verbosity)
+
+	for _, cfg := range []string{`bogus: 1`, `vmodule: [a=1, b=2]`} {
+		_, err := reloadConfig(ctx, f, "test.yaml", []byte(cfg), values, nil)
+		require.Error(t, err, cfg)
+	}
+	require.Equal(t, "raft=2", vmodule)
+}
+
+func TestReloadConfigFileApply(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	var cache string
+	var heartbeat time.Duration
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.StringVar(&cache, cliflags.Cache.Name, "", "")
+	DurationFlag(f, &heartbeat, cliflags.RPCHeartbeatInterval, 3*time.Second)
+
+	// The flags are applied to the server once they are set. A flag whose
+	// new value can't be applied keeps it, and the other changes are still
+	// made.
+	var appliedNames []string
+	var appliedHeartbeat time.Duration
+	apply := func(_ context.Context, name string) error {
+		appliedNames = append(appliedNames, name)
+		switch name {
+		case cliflags.Cache.Name:
+			return errors.New("cannot be resized")
+		case cliflags.RPCHeartbeatInterval.Name:
+			appliedHeartbeat = heartbeat
+		}
+		return nil
+	}
+	values := make(map[string]string)
+	changed, err := reloadConfig(ctx, f, "test.yaml", []byte(`
+cache: 1GiB
+rpc-heartbeat-interval: 1s
+`), values, apply)
+	require.True(t, testutils.IsError(err,
+		"the new value of --cache takes effect at the next restart: cannot be resized"), "%v", err)
+	require.Equal(t, []string{cliflags.Cache.Name, cliflags.RPCHeartbeatInterval.Name}, changed)
+	require.Equal(t, changed, appliedNames)
+	require.Equal(t, "1GiB", cache)
+	require.Equal(t, time.Second, appliedHeartbeat)
+
+	// The failed change is recorded, and isn't retried by the next reload.
+	appliedNames = nil
+	changed, err = reloadConfig(ctx, f, "test.yaml", []byte(`
+cache: 1GiB
+rpc-heartbeat-interval: 2s
+`), values, apply)
+	require.NoError(t, err)
+	require.Equal(t, []string{cliflags.RPCHeartbeatInterval.Name}, changed)
+	require.Equal(t, changed, appliedNames)
+	require.Equal(t, 2*time.Second, appliedHeartbeat)
+}
+
+func TestConfigReloader(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "test.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`rpc-heartbeat-interval: 1s`), 0644))
+
+	var heartbeat time.Duration
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	DurationFlag(f, &heartbeat, cliflags.RPCHeartbeatInterval, 3*time.Second)
+	values, err := applyConfigFile(f, path)
+	require.NoError(t, err)
+
+	appliedCh := make(chan time.Duration, 1)
+	r := newConfigReloader(f, path, values, func(_ context.Context, name string) error {
+		appliedCh <- heartbeat
+		return nil
+	})
+
+	// A signal received before the reloads are started is handled once they
+	// are.
+	sigCh := make(chan os.Signal, 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(`rpc-heartbeat-interval: 2s`), 0644))
+	sigCh <- syscall.SIGHUP
+	r.reloadOnSignal(ctx, stopper, sigCh)
+	require.Equal(t, 2*time.Second, <-appliedCh)
+
+	// Reloads triggered directly, as by the admin RPC, report the changes.
+	require.NoError(t, ioutil.WriteFile(path, []byte(`rpc-heartbeat-interval: 5s`), 0644))
+	changed, err := r.reload(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{cliflags.RPCHeartbeatInterval.Name}, changed)
+	require.Equal(t, 5*time.Second, <-appliedCh)
+	changed, err = r.reload(ctx)
+	require.NoError(t, err)
+	require.Empty(t, changed)
+}
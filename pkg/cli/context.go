@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cli/kvload"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -108,6 +109,7 @@ func initCLIDefaults() {
 	serverCfg.HeapProfileDirName = ""
 	serverCfg.ReadyFn = nil
 	serverCfg.DelayedBootstrapFn = nil
+	serverCfg.ReloadConfigFn = nil
 	serverCfg.SocketFile = ""
 	serverCfg.JoinList = nil
 	serverCfg.DefaultZoneConfig = zonepb.DefaultZoneConfig()
@@ -119,13 +121,18 @@ func initCLIDefaults() {
 
 	startCtx.serverInsecure = baseCfg.Insecure
 	startCtx.serverSSLCertsDir = base.DefaultCertsDirectory
+	startCtx.serverCertsEnvPrefix = ""
 	startCtx.serverCertPrincipalMap = nil
+	startCtx.serverTLSMinVersion = ""
+	startCtx.serverTLSCipherSuites = nil
 	startCtx.serverListenAddr = ""
 	startCtx.unencryptedLocalhostHTTP = false
 	startCtx.tempDir = ""
 	startCtx.externalIODir = ""
 	startCtx.listeningURLFile = ""
 	startCtx.pidFile = ""
+	startCtx.configFile = ""
+	startCtx.configFileValues = nil
 	startCtx.inBackground = false
 
 	quitCtx.serverDecommission = false
@@ -137,6 +144,8 @@ func initCLIDefaults() {
 	nodeCtx.statusShowAll = false
 	nodeCtx.statusShowDecommission = false
 
+	zoneCtx.zoneConfig = ""
+
 	cfg := tree.DefaultPrettyCfg()
 	sqlfmtCtx.len = cfg.LineWidth
 	sqlfmtCtx.useSpaces = !cfg.UseTabs
@@ -155,6 +164,18 @@ func initCLIDefaults() {
 	networkBenchCtx.port = 8081
 	networkBenchCtx.addresses = []string{"localhost:8081"}
 
+	loadCtx = kvload.Options{
+		Mix:          "read-heavy",
+		Distribution: kvload.Zipfian,
+		Concurrency:  8,
+		Duration:     60 * time.Second,
+		Keys:         100000,
+		ValueSize:    100,
+		ScanLength:   100,
+		Prefix:       "load/",
+		Seed:         1,
+	}
+
 	demoCtx.nodes = 1
 	demoCtx.sqlPoolMemorySize = 128 << 20 // 128MB, chosen to fit 9 nodes on 2GB machine.
 	demoCtx.cacheSize = 64 << 20          // 64MB, chosen to fit 9 nodes on 2GB machine.
@@ -170,6 +191,8 @@ func initCLIDefaults() {
 
 	authCtx.validityPeriod = 1 * time.Hour
 
+	userCtx.promptPassword = false
+
 	initPreFlagsDefaults()
 
 	// Clear the "Changed" state of all the registered command-line flags.
@@ -297,7 +320,10 @@ var startCtx struct {
 	// server-specific values of some flags.
 	serverInsecure         bool
 	serverSSLCertsDir      string
+	serverCertsEnvPrefix   string
 	serverCertPrincipalMap []string
+	serverTLSMinVersion    string
+	serverTLSCipherSuites  []string
 	serverListenAddr       string
 
 	// if specified, this forces the HTTP listen addr to localhost
@@ -323,6 +349,15 @@ var startCtx struct {
 	// when it is ready.
 	pidFile string
 
+	// configFile is the YAML file from which additional flag values
+	// are read.
+	configFile string
+
+	// configFileValues records the flags which were set from the config
+	// file, and the values they were set to, so that the file can be
+	// reloaded without overriding the command line.
+	configFileValues map[string]string
+
 	// logging settings specific to file logging.
 	logDir log.DirName
 }
@@ -349,6 +384,14 @@ var nodeCtx struct {
 	statusShowAll          bool
 }
 
+// zoneCtx captures the command-line parameters of the `zone` command.
+// Defaults set by InitCLIDefaults() above.
+var zoneCtx struct {
+	// zoneConfig is the file from which `zone set` reads the zone
+	// configuration, or "-" for standard input.
+	zoneConfig string
+}
+
 // systemBenchCtx captures the command-line parameters of the `systembench` command.
 // Defaults set by InitCLIDefaults() above.
 var systemBenchCtx struct {
@@ -366,6 +409,10 @@ var networkBenchCtx struct {
 	latency   bool
 }
 
+// loadCtx captures the command-line parameters of the `load` command.
+// Defaults set by InitCLIDefaults() above.
+var loadCtx kvload.Options
+
 // sqlfmtCtx captures the command-line parameters of the `sqlfmt` command.
 // Defaults set by InitCLIDefaults() above.
 var sqlfmtCtx struct {
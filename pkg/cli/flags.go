@@ -107,6 +107,20 @@ func IntFlag(f *pflag.FlagSet, valPtr *int, flagInfo cliflags.FlagInfo, defaultV
 	registerEnvVarDefault(f, flagInfo)
 }
 
+// Int64Flag creates an int64 flag and registers it with the FlagSet.
+func Int64Flag(f *pflag.FlagSet, valPtr *int64, flagInfo cliflags.FlagInfo, defaultVal int64) {
+	f.Int64VarP(valPtr, flagInfo.Name, flagInfo.Shorthand, defaultVal, flagInfo.Usage())
+
+	registerEnvVarDefault(f, flagInfo)
+}
+
+// Uint64Flag creates a uint64 flag and registers it with the FlagSet.
+func Uint64Flag(f *pflag.FlagSet, valPtr *uint64, flagInfo cliflags.FlagInfo, defaultVal uint64) {
+	f.Uint64VarP(valPtr, flagInfo.Name, flagInfo.Shorthand, defaultVal, flagInfo.Usage())
+
+	registerEnvVarDefault(f, flagInfo)
+}
+
 // BoolFlag creates a bool flag and registers it with the FlagSet.
 func BoolFlag(f *pflag.FlagSet, valPtr *bool, flagInfo cliflags.FlagInfo, defaultVal bool) {
 	f.BoolVarP(valPtr, flagInfo.Name, flagInfo.Shorthand, defaultVal, flagInfo.Usage())
@@ -247,6 +261,13 @@ func init() {
 	// Add a pre-run command for `start` and `start-single-node`.
 	for _, cmd := range StartCmds {
 		AddPersistentPreRunE(cmd, func(cmd *cobra.Command, _ []string) error {
+			// Fill in the flags not specified on the command line from
+			// the configuration file, if any.
+			values, err := applyConfigFile(flagSetForCmd(cmd), startCtx.configFile)
+			if err != nil {
+				return err
+			}
+			startCtx.configFileValues = values
 			// Finalize the configuration of network and logging settings.
 			if err := extraServerFlagInit(cmd); err != nil {
 				return err
@@ -358,12 +379,16 @@ func init() {
 		VarFlag(f, &serverCfg.Stores, cliflags.Store)
 		VarFlag(f, &serverCfg.StorageEngine, cliflags.StorageEngine)
 		VarFlag(f, &serverCfg.MaxOffset, cliflags.MaxOffset)
+		DurationFlag(f, &serverCfg.RPCHeartbeatInterval, cliflags.RPCHeartbeatInterval,
+			serverCfg.RPCHeartbeatInterval)
 		StringFlag(f, &serverCfg.ClockDevicePath, cliflags.ClockDevice, "")
 
 		StringFlag(f, &startCtx.listeningURLFile, cliflags.ListeningURLFile, startCtx.listeningURLFile)
 
 		StringFlag(f, &startCtx.pidFile, cliflags.PIDFile, startCtx.pidFile)
 
+		StringFlag(f, &startCtx.configFile, cliflags.ConfigFile, startCtx.configFile)
+
 		// Use a separate variable to store the value of ServerInsecure.
 		// We share the default with the ClientInsecure flag.
 		BoolFlag(f, &startCtx.serverInsecure, cliflags.ServerInsecure, startCtx.serverInsecure)
@@ -378,11 +403,17 @@ func init() {
 		// Certificates directory. Use a server-specific flag and value to ignore environment
 		// variables, but share the same default.
 		StringFlag(f, &startCtx.serverSSLCertsDir, cliflags.ServerCertsDir, startCtx.serverSSLCertsDir)
+		StringFlag(f, &startCtx.serverCertsEnvPrefix, cliflags.CertsEnvPrefix, startCtx.serverCertsEnvPrefix)
 
 		// Certificate principal map.
 		StringSlice(f, &startCtx.serverCertPrincipalMap,
 			cliflags.CertPrincipalMap, startCtx.serverCertPrincipalMap)
 
+		// TLS protocol settings.
+		StringFlag(f, &startCtx.serverTLSMinVersion, cliflags.TLSMinVersion, startCtx.serverTLSMinVersion)
+		StringSlice(f, &startCtx.serverTLSCipherSuites,
+			cliflags.TLSCipherSuites, startCtx.serverTLSCipherSuites)
+
 		// Cluster joining flags. We need to enable this both for 'start'
 		// and 'start-single-node' although the latter does not support
 		// --join, because it delegates its logic to that of 'start', and
@@ -463,6 +494,8 @@ func init() {
 	}
 	// PKCS8 key format is only available for the client cert command.
 	BoolFlag(createClientCertCmd.Flags(), &generatePKCS8Key, cliflags.GeneratePKCS8Key, false)
+	// Listing certificates can include those read from the environment.
+	StringFlag(listCertsCmd.Flags(), &listCertsEnvPrefix, cliflags.CertsEnvPrefix, "")
 
 	clientCmds := []*cobra.Command{
 		debugGossipValuesCmd,
@@ -475,10 +508,14 @@ func init() {
 		/* StartCmds are covered above */
 	}
 	clientCmds = append(clientCmds, authCmds...)
+	clientCmds = append(clientCmds, userCmds...)
 	clientCmds = append(clientCmds, nodeCmds...)
 	clientCmds = append(clientCmds, systemBenchCmds...)
 	clientCmds = append(clientCmds, initCmd)
 	clientCmds = append(clientCmds, nodeLocalCmds...)
+	clientCmds = append(clientCmds, zoneCmds...)
+	clientCmds = append(clientCmds, kvCmds...)
+	clientCmds = append(clientCmds, loadCmd)
 	for _, cmd := range clientCmds {
 		f := cmd.PersistentFlags()
 		VarFlag(f, addrSetter{&cliCtx.clientConnHost, &cliCtx.clientConnPort}, cliflags.ClientHost)
@@ -491,6 +528,31 @@ func init() {
 		StringFlag(f, &baseCfg.SSLCertsDir, cliflags.CertsDir, baseCfg.SSLCertsDir)
 	}
 
+	// KV commands authenticate with the client certificate of --user.
+	for _, cmd := range append(kvCmds, loadCmd) {
+		f := cmd.Flags()
+		StringFlag(f, &baseCfg.User, cliflags.User, baseCfg.User)
+	}
+
+	// Load command.
+	{
+		f := loadCmd.Flags()
+		StringFlag(f, &loadCtx.Mix, cliflags.LoadMix, loadCtx.Mix)
+		StringFlag(f, &loadCtx.Distribution, cliflags.LoadDistribution, loadCtx.Distribution)
+		IntFlag(f, &loadCtx.Concurrency, cliflags.BenchConcurrency, loadCtx.Concurrency)
+		DurationFlag(f, &loadCtx.Duration, cliflags.BenchDuration, loadCtx.Duration)
+		Uint64Flag(f, &loadCtx.Keys, cliflags.LoadKeys, loadCtx.Keys)
+		IntFlag(f, &loadCtx.ValueSize, cliflags.LoadValueSize, loadCtx.ValueSize)
+		IntFlag(f, &loadCtx.ScanLength, cliflags.LoadScanLength, loadCtx.ScanLength)
+		StringFlag(f, &loadCtx.Prefix, cliflags.LoadKeyPrefix, loadCtx.Prefix)
+		Int64Flag(f, &loadCtx.Seed, cliflags.LoadSeed, loadCtx.Seed)
+		BoolFlag(f, &loadCtx.Init, cliflags.LoadInit, loadCtx.Init)
+		StringFlag(f, &loadCtx.HistogramsPath, cliflags.LoadHistograms, loadCtx.HistogramsPath)
+	}
+
+	// User commands.
+	BoolFlag(setUserCmd.Flags(), &userCtx.promptPassword, cliflags.Password, userCtx.promptPassword)
+
 	// Auth commands.
 	{
 		f := loginCmd.Flags()
@@ -520,6 +582,9 @@ func init() {
 		BoolFlag(f, &nodeCtx.statusShowDecommission, cliflags.NodeDecommission, nodeCtx.statusShowDecommission)
 	}
 
+	// Zone Set command.
+	StringFlag(zoneSetCmd.Flags(), &zoneCtx.zoneConfig, cliflags.ZoneConfig, zoneCtx.zoneConfig)
+
 	// HDD Bench command.
 	{
 		f := seqWriteBench.Flags()
@@ -582,8 +647,10 @@ func init() {
 	// Commands that establish a SQL connection.
 	sqlCmds := []*cobra.Command{sqlShellCmd, dumpCmd, demoCmd}
 	sqlCmds = append(sqlCmds, authCmds...)
+	sqlCmds = append(sqlCmds, userCmds...)
 	sqlCmds = append(sqlCmds, demoCmd.Commands()...)
 	sqlCmds = append(sqlCmds, nodeLocalCmds...)
+	sqlCmds = append(sqlCmds, zoneCmds...)
 	for _, cmd := range sqlCmds {
 		f := cmd.Flags()
 		BoolFlag(f, &sqlCtx.echo, cliflags.EchoSQL, sqlCtx.echo)
@@ -628,6 +695,8 @@ func init() {
 		demoCmd.Commands()...)
 	tableOutputCommands = append(tableOutputCommands, nodeCmds...)
 	tableOutputCommands = append(tableOutputCommands, authCmds...)
+	tableOutputCommands = append(tableOutputCommands, userCmds...)
+	tableOutputCommands = append(tableOutputCommands, zoneLsCmd)
 
 	// By default, these commands print their output as pretty-formatted
 	// tables on terminals, and TSV when redirected to a file. The user
@@ -765,6 +834,25 @@ func extraServerFlagInit(cmd *cobra.Command) error {
 	serverCfg.User = security.NodeUser
 	serverCfg.Insecure = startCtx.serverInsecure
 	serverCfg.SSLCertsDir = startCtx.serverSSLCertsDir
+	if startCtx.serverCertsEnvPrefix != "" {
+		assets := security.SecretStoreAssetLoader(
+			security.EnvSecretStore{Prefix: startCtx.serverCertsEnvPrefix}, security.GetAssetLoader())
+		serverCfg.CertificateAssets = &assets
+	}
+	if startCtx.serverTLSMinVersion != "" {
+		v, err := security.ParseTLSVersion(startCtx.serverTLSMinVersion)
+		if err != nil {
+			return errors.Wrapf(err, "invalid --%s", cliflags.TLSMinVersion.Name)
+		}
+		serverCfg.TLSSettings.MinVersion = v
+	}
+	if len(startCtx.serverTLSCipherSuites) > 0 {
+		suites, err := security.ParseCipherSuites(startCtx.serverTLSCipherSuites)
+		if err != nil {
+			return errors.Wrapf(err, "invalid --%s", cliflags.TLSCipherSuites.Name)
+		}
+		serverCfg.TLSSettings.CipherSuites = suites
+	}
 
 	// Construct the main RPC listen address.
 	serverCfg.Addr = net.JoinHostPort(startCtx.serverListenAddr, serverListenPort)
@@ -0,0 +1,248 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// kvScanPageSize is the number of keys fetched per request by `kv scan`.
+const kvScanPageSize = 1000
+
+// unquoteKey parses a key given on the command line. Keys may be written as
+// Go string literals, e.g. "\x00a", to address keys which are not printable.
+func unquoteKey(arg string) (roachpb.Key, error) {
+	if strings.HasPrefix(arg, `"`) {
+		s, err := strconv.Unquote(arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid quoted key %s", arg)
+		}
+		arg = s
+	}
+	return roachpb.Key(arg), nil
+}
+
+// runKVBatch connects to the node and sends it a single non-transactional
+// batch containing the given requests.
+func runKVBatch(ctx context.Context, reqs ...roachpb.Request) (*roachpb.BatchResponse, error) {
+	conn, _, finish, err := getClientGRPCConn(ctx, serverCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the node")
+	}
+	defer finish()
+
+	return sendKVBatch(ctx, serverpb.NewKVClient(conn), roachpb.Header{}, reqs...)
+}
+
+func sendKVBatch(
+	ctx context.Context, c serverpb.KVClient, h roachpb.Header, reqs ...roachpb.Request,
+) (*roachpb.BatchResponse, error) {
+	ba := roachpb.BatchRequest{Header: h}
+	ba.Add(reqs...)
+	br, err := c.Batch(ctx, &ba)
+	if err != nil {
+		return nil, err
+	}
+	if br.Error != nil {
+		return nil, br.Error.GoError()
+	}
+	return br, nil
+}
+
+var kvGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "get the value of a key",
+	Long: `
+Fetch and display the value of a key. Keys may be given as Go string
+literals, e.g. "\x00a", to address non-printable keys.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runKVGet),
+}
+
+func runKVGet(cmd *cobra.Command, args []string) error {
+	key, err := unquoteKey(args[0])
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	br, err := runKVBatch(ctx, roachpb.NewGet(key))
+	if err != nil {
+		return err
+	}
+	value := br.Responses[0].GetGet().Value
+	if value == nil {
+		return errors.Errorf("key %s not found", key)
+	}
+	fmt.Println(value.PrettyPrint())
+	return nil
+}
+
+var kvPutCmd = &cobra.Command{
+	Use:   "put <key> <value>",
+	Short: "set the value of a key",
+	Long: `
+Write a string value to a key, overwriting any existing value.
+`,
+	Args: cobra.ExactArgs(2),
+	RunE: MaybeDecorateGRPCError(runKVPut),
+}
+
+func runKVPut(cmd *cobra.Command, args []string) error {
+	key, err := unquoteKey(args[0])
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err = runKVBatch(ctx, roachpb.NewPut(key, roachpb.MakeValueFromString(args[1])))
+	return err
+}
+
+var kvDelCmd = &cobra.Command{
+	Use:   "del <key> [<key>...]",
+	Short: "delete keys",
+	Long: `
+Delete the specified keys. Deleting a key which does not exist is not
+an error.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: MaybeDecorateGRPCError(runKVDel),
+}
+
+func runKVDel(cmd *cobra.Command, args []string) error {
+	reqs := make([]roachpb.Request, len(args))
+	for i, arg := range args {
+		key, err := unquoteKey(arg)
+		if err != nil {
+			return err
+		}
+		reqs[i] = roachpb.NewDelete(key)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := runKVBatch(ctx, reqs...)
+	return err
+}
+
+var kvScanCmd = &cobra.Command{
+	Use:   "scan [<start key> [<end key>]]",
+	Short: "scan a range of keys",
+	Long: `
+Display the keys and values in the span [start key, end key). The start key
+defaults to the first key after the system key space, which can't be
+addressed, and the end key to the end of the key space. The system tables,
+which can't be addressed either, are skipped.
+`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: MaybeDecorateGRPCError(runKVScan),
+}
+
+func runKVScan(cmd *cobra.Command, args []string) error {
+	start, end := keys.SystemMax, roachpb.KeyMax
+	var err error
+	if len(args) > 0 {
+		if start, err = unquoteKey(args[0]); err != nil {
+			return err
+		}
+	}
+	if len(args) > 1 {
+		if end, err = unquoteKey(args[1]); err != nil {
+			return err
+		}
+	}
+	if start.Compare(end) >= 0 {
+		return errors.Errorf("start key %s must be less than end key %s", start, end)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, _, finish, err := getClientGRPCConn(ctx, serverCfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the node")
+	}
+	defer finish()
+	c := serverpb.NewKVClient(conn)
+
+	for _, span := range kvScanSpans(start, end) {
+		span := &span
+		for span != nil {
+			br, err := sendKVBatch(ctx, c, roachpb.Header{MaxSpanRequestKeys: kvScanPageSize},
+				roachpb.NewScan(span.Key, span.EndKey, false /* forUpdate */))
+			if err != nil {
+				return err
+			}
+			resp := br.Responses[0].GetScan()
+			for _, kv := range resp.Rows {
+				fmt.Printf("%s\t%s\n", kv.Key, kv.Value.PrettyPrint())
+			}
+			span = resp.ResumeSpan
+		}
+	}
+	return nil
+}
+
+// kvScanSpans returns the parts of [start, end) outside of the system tables,
+// which the KV API does not address.
+func kvScanSpans(start, end roachpb.Key) []roachpb.Span {
+	var spans []roachpb.Span
+	if start.Compare(keys.TableDataMin) < 0 {
+		spans = append(spans, roachpb.Span{Key: start, EndKey: end})
+		if end.Compare(keys.TableDataMin) > 0 {
+			spans[0].EndKey = keys.TableDataMin
+		}
+	}
+	if end.Compare(keys.UserTableDataMin) > 0 {
+		s := roachpb.Span{Key: start, EndKey: end}
+		if start.Compare(keys.UserTableDataMin) < 0 {
+			s.Key = keys.UserTableDataMin
+		}
+		spans = append(spans, s)
+	}
+	return spans
+}
+
+var kvCmds = []*cobra.Command{
+	kvGetCmd,
+	kvPutCmd,
+	kvDelCmd,
+	kvScanCmd,
+}
+
+var kvCmd = &cobra.Command{
+	Use:   "kv [command]",
+	Short: "get, put, delete and scan raw keys",
+	Long: `
+Read and write raw keys through the key-value API of a running node,
+for smoke testing and debugging. This requires a root client certificate,
+or a client certificate for a --user who was granted access to the keys
+through the server.kv_api.user_permissions cluster setting.
+`,
+	RunE: usageAndErr,
+}
+
+func init() {
+	kvCmd.AddCommand(kvCmds...)
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnquoteKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for arg, expected := range map[string]roachpb.Key{
+		`a`:        roachpb.Key("a"),
+		`"a b"`:    roachpb.Key("a b"),
+		`"\x00a"`:  roachpb.Key("\x00a"),
+		`a"b`:      roachpb.Key(`a"b`),
+		`\xff`:     roachpb.Key(`\xff`),
+		`"\xff\n"`: roachpb.Key("\xff\n"),
+	} {
+		key, err := unquoteKey(arg)
+		require.NoError(t, err)
+		require.Equal(t, expected, key, arg)
+	}
+
+	_, err := unquoteKey(`"unterminated`)
+	require.Error(t, err)
+}
+
+func TestKVScanSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	a, b := roachpb.Key("a"), roachpb.Key("b")
+	user := keys.UserTableDataMin
+	userEnd := user.PrefixEnd()
+	for _, tc := range []struct {
+		start, end roachpb.Key
+		expected   []roachpb.Span
+	}{
+		{a, b, []roachpb.Span{{Key: a, EndKey: b}}},
+		{user, userEnd, []roachpb.Span{{Key: user, EndKey: userEnd}}},
+		{keys.SystemMax, roachpb.KeyMax, []roachpb.Span{
+			{Key: keys.SystemMax, EndKey: keys.TableDataMin},
+			{Key: user, EndKey: roachpb.KeyMax},
+		}},
+		{a, userEnd, []roachpb.Span{
+			{Key: a, EndKey: keys.TableDataMin},
+			{Key: user, EndKey: userEnd},
+		}},
+		{keys.TableDataMin, user, nil},
+	} {
+		require.Equal(t, tc.expected, kvScanSpans(tc.start, tc.end), "%s-%s", tc.start, tc.end)
+	}
+}
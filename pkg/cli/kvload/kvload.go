@@ -0,0 +1,403 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package kvload generates load against a cluster through the KV API, with
+// operation mixes modeled after the core YCSB workloads. Since it bypasses
+// SQL entirely, it measures the rpc and storage layers more directly than
+// the SQL workloads do.
+package kvload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvapi"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/workload/histogram"
+	"github.com/cockroachdb/cockroach/pkg/workload/ycsb"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// initBatchSize is the number of keys written per batch by the initial
+	// load.
+	initBatchSize = 1000
+	// maxLatency is the highest latency tracked by the histograms. Slower
+	// operations are recorded as taking this long.
+	maxLatency = 10 * time.Second
+	// zipfTheta is the skew of the zipfian distribution, the YCSB default.
+	zipfTheta = 0.99
+)
+
+// Mix is the percentage of each kind of operation issued by a load.
+type Mix struct {
+	Name  string
+	Read  int
+	Write int
+	Scan  int
+}
+
+// Mixes are the available operation mixes.
+var Mixes = []Mix{
+	// read-heavy is YCSB workload B.
+	{Name: "read-heavy", Read: 95, Write: 5},
+	// write-heavy is YCSB workload A.
+	{Name: "write-heavy", Read: 50, Write: 50},
+	// scan is YCSB workload E, with the inserts replaced by overwrites so that
+	// the key space does not grow.
+	{Name: "scan", Scan: 95, Write: 5},
+}
+
+// Key distributions.
+const (
+	// Uniform picks keys uniformly at random.
+	Uniform = "uniform"
+	// Zipfian picks keys following a zipfian distribution, so that a few keys
+	// get most of the operations.
+	Zipfian = "zipfian"
+	// Sequential cycles through the keys in order.
+	Sequential = "sequential"
+)
+
+// Options configures a load.
+type Options struct {
+	// Mix is the name of the operation mix, see Mixes.
+	Mix string
+	// Distribution is the distribution of the keys operated on.
+	Distribution string
+	// Concurrency is the number of workers issuing operations, each waiting
+	// for its previous operation to finish before issuing the next one.
+	Concurrency int
+	// Duration is how long the load runs for, or zero to run until the
+	// context is canceled.
+	Duration time.Duration
+	// Keys is the number of keys operations pick from.
+	Keys uint64
+	// ValueSize is the size in bytes of the values written.
+	ValueSize int
+	// ScanLength is the maximum number of rows read by a scan. The number of
+	// rows of each scan is uniformly distributed between 1 and ScanLength.
+	ScanLength int
+	// Prefix is prepended to all the keys.
+	Prefix string
+	// Seed seeds the random choices of the workers.
+	Seed int64
+	// Init writes every key once before the load starts, so that reads and
+	// scans find data.
+	Init bool
+	// HistogramsPath, if set, is a file to which the latency histograms of
+	// every second are written as JSON, in the format of the --histograms
+	// flag of `workload run`.
+	HistogramsPath string
+}
+
+func findMix(name string) (Mix, error) {
+	var names []string
+	for _, m := range Mixes {
+		if m.Name == name {
+			return m, nil
+		}
+		names = append(names, m.Name)
+	}
+	return Mix{}, errors.Errorf("unknown mix %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// keyGenerator picks the indexes of the keys to operate on.
+type keyGenerator interface {
+	Uint64() uint64
+}
+
+// sequentialGenerator is a keyGenerator cycling through the keys.
+type sequentialGenerator struct {
+	next *uint64
+	keys uint64
+}
+
+func (g sequentialGenerator) Uint64() uint64 {
+	return (atomic.AddUint64(g.next, 1) - 1) % g.keys
+}
+
+// newKeyGenerators returns a keyGenerator of the key distribution of the
+// load for each worker, each drawing from its own RNG seeded from rng so that
+// the keys picked by a worker only depend on the seed of the load. The
+// zipfian generators share the constant computed over all the keys, which
+// takes too long to repeat for each worker. The sequential generators share a
+// single counter instead, so that together they cycle through the keys in
+// order.
+func newKeyGenerators(opts Options, rng *rand.Rand) ([]keyGenerator, error) {
+	gens := make([]keyGenerator, opts.Concurrency)
+	switch opts.Distribution {
+	case Uniform:
+		for i := range gens {
+			g, err := ycsb.NewUniformGenerator(rand.New(rand.NewSource(rng.Int63())), 0, opts.Keys-1)
+			if err != nil {
+				return nil, err
+			}
+			gens[i] = g
+		}
+	case Zipfian:
+		z, err := ycsb.NewZipfGenerator(rand.New(rand.NewSource(rng.Int63())), 0, opts.Keys-1,
+			zipfTheta, false /* verbose */)
+		if err != nil {
+			return nil, err
+		}
+		gens[0] = z
+		for i := 1; i < len(gens); i++ {
+			gens[i] = z.WithRand(rand.New(rand.NewSource(rng.Int63())))
+		}
+	case Sequential:
+		next := new(uint64)
+		for i := range gens {
+			gens[i] = sequentialGenerator{next: next, keys: opts.Keys}
+		}
+	default:
+		return nil, errors.Errorf("unknown key distribution %q, expected one of: %s, %s, %s",
+			opts.Distribution, Uniform, Zipfian, Sequential)
+	}
+	return gens, nil
+}
+
+func makeKey(prefix roachpb.Key, i uint64) roachpb.Key {
+	return append(prefix[:len(prefix):len(prefix)], fmt.Sprintf("%020d", i)...)
+}
+
+type worker struct {
+	db     *kvapi.DB
+	mix    Mix
+	rng    *rand.Rand
+	keys   keyGenerator
+	hists  *histogram.Histograms
+	prefix roachpb.Key
+	// end is the end of the key space of the load, which bounds scans.
+	end        roachpb.Key
+	value      []byte
+	scanLength int
+}
+
+func (w *worker) run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		key := makeKey(w.prefix, w.keys.Uint64())
+		op := w.rng.Intn(100)
+		start := timeutil.Now()
+		var name string
+		var err error
+		switch {
+		case op < w.mix.Read:
+			name = "read"
+			_, err = w.db.Get(ctx, key)
+		case op < w.mix.Read+w.mix.Scan:
+			name = "scan"
+			_, err = w.db.Scan(ctx, key, w.end, int64(1+w.rng.Intn(w.scanLength)))
+		default:
+			name = "write"
+			_, _ = w.rng.Read(w.value)
+			err = w.db.Put(ctx, key, w.value)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// The load is over.
+				return nil
+			}
+			return errors.Wrapf(err, "%s of key %s", name, key)
+		}
+		w.hists.Get(name).Record(timeutil.Since(start))
+	}
+	return nil
+}
+
+// initKeys writes all the keys of the load, in batches spread over
+// opts.Concurrency workers.
+func initKeys(ctx context.Context, db *kvapi.DB, opts Options, rng *rand.Rand) error {
+	prefix := roachpb.Key(opts.Prefix)
+	var next uint64
+	value := make([]byte, opts.ValueSize)
+	_, _ = rng.Read(value)
+	return ctxgroup.GroupWorkers(ctx, opts.Concurrency, func(ctx context.Context, _ int) error {
+		for {
+			start := atomic.AddUint64(&next, initBatchSize) - initBatchSize
+			if start >= opts.Keys {
+				return nil
+			}
+			b := &kvapi.Batch{}
+			for i := start; i < start+initBatchSize && i < opts.Keys; i++ {
+				b.Put(makeKey(prefix, i), value)
+			}
+			if err := db.Run(ctx, b); err != nil {
+				return errors.Wrap(err, "initializing keys")
+			}
+		}
+	})
+}
+
+func validate(opts Options) error {
+	if opts.Concurrency < 1 {
+		return errors.Errorf("concurrency must be at least 1, got %d", opts.Concurrency)
+	}
+	if opts.Keys < 1 {
+		return errors.Errorf("number of keys must be at least 1, got %d", opts.Keys)
+	}
+	if opts.ScanLength < 1 {
+		return errors.Errorf("scan length must be at least 1, got %d", opts.ScanLength)
+	}
+	if opts.ValueSize < 0 {
+		return errors.Errorf("value size must not be negative, got %d", opts.ValueSize)
+	}
+	return nil
+}
+
+// Run runs a load against the cluster, printing the throughput and latency
+// percentiles of each kind of operation every second, and in total once the
+// load is over. The load stops at the first operation which fails, or
+// cleanly when the context is canceled, even while the keys are written.
+func Run(ctx context.Context, db *kvapi.DB, opts Options, out io.Writer) error {
+	if err := validate(opts); err != nil {
+		return err
+	}
+	mix, err := findMix(opts.Mix)
+	if err != nil {
+		return err
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	keys, err := newKeyGenerators(opts, rng)
+	if err != nil {
+		return err
+	}
+	reg := histogram.NewRegistry(maxLatency)
+	prefix := roachpb.Key(opts.Prefix)
+	workers := make([]*worker, opts.Concurrency)
+	for i := range workers {
+		// Each worker has its own source, seeded from the main one, to avoid
+		// contention.
+		wrng := rand.New(rand.NewSource(rng.Int63()))
+		workers[i] = &worker{
+			db:         db,
+			mix:        mix,
+			rng:        wrng,
+			keys:       keys[i],
+			hists:      reg.GetHandle(),
+			prefix:     prefix,
+			end:        prefix.PrefixEnd(),
+			value:      make([]byte, opts.ValueSize),
+			scanLength: opts.ScanLength,
+		}
+	}
+
+	var jsonEnc *json.Encoder
+	if opts.HistogramsPath != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.HistogramsPath), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(opts.HistogramsPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		jsonEnc = json.NewEncoder(f)
+	}
+
+	if opts.Init {
+		initStart := timeutil.Now()
+		if err := initKeys(ctx, db, opts, rng); err != nil {
+			if ctx.Err() != nil {
+				// The load was interrupted before it started.
+				fmt.Fprintf(out, "interrupted while writing keys after %s\n",
+					timeutil.Since(initStart).Round(time.Millisecond))
+				return nil
+			}
+			return err
+		}
+		fmt.Fprintf(out, "wrote %d keys in %s\n", opts.Keys, timeutil.Since(initStart).Round(time.Millisecond))
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.Duration > 0 {
+		runCtx, cancel = context.WithTimeout(runCtx, opts.Duration)
+		defer cancel()
+	}
+	g := ctxgroup.WithContext(runCtx)
+	for _, w := range workers {
+		w := w
+		g.GoCtx(w.run)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Wait()
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	start := timeutil.Now()
+	numTicks := 0
+	for {
+		select {
+		case <-ticker.C:
+			startElapsed := timeutil.Since(start)
+			reg.Tick(func(t histogram.Tick) {
+				if numTicks%20 == 0 {
+					fmt.Fprintln(out, "_elapsed__ops/sec(inst)___ops/sec(cum)__p50(ms)__p95(ms)__p99(ms)_pMax(ms)")
+				}
+				numTicks++
+				fmt.Fprintf(out, "%7.1fs %14.1f %14.1f %8.1f %8.1f %8.1f %8.1f %s\n",
+					startElapsed.Seconds(),
+					float64(t.Hist.TotalCount())/t.Elapsed.Seconds(),
+					float64(t.Cumulative.TotalCount())/startElapsed.Seconds(),
+					time.Duration(t.Hist.ValueAtQuantile(50)).Seconds()*1000,
+					time.Duration(t.Hist.ValueAtQuantile(95)).Seconds()*1000,
+					time.Duration(t.Hist.ValueAtQuantile(99)).Seconds()*1000,
+					time.Duration(t.Hist.ValueAtQuantile(100)).Seconds()*1000,
+					t.Name,
+				)
+				if jsonEnc != nil {
+					_ = jsonEnc.Encode(t.Snapshot())
+				}
+			})
+
+		case err := <-done:
+			if err != nil {
+				return err
+			}
+			printTotals(out, reg, timeutil.Since(start))
+			return nil
+		}
+	}
+}
+
+// printTotals prints the throughput and latency percentiles of each kind of
+// operation over the whole load.
+func printTotals(out io.Writer, reg *histogram.Registry, startElapsed time.Duration) {
+	fmt.Fprintln(out, "\n_elapsed_____ops(total)___ops/sec(cum)__avg(ms)__p50(ms)__p95(ms)__p99(ms)_pMax(ms)")
+	reg.Tick(func(t histogram.Tick) {
+		if t.Cumulative.TotalCount() == 0 {
+			return
+		}
+		fmt.Fprintf(out, "%7.1fs %14d %14.1f %8.1f %8.1f %8.1f %8.1f %8.1f %s\n",
+			startElapsed.Seconds(),
+			t.Cumulative.TotalCount(),
+			float64(t.Cumulative.TotalCount())/startElapsed.Seconds(),
+			time.Duration(t.Cumulative.Mean()).Seconds()*1000,
+			time.Duration(t.Cumulative.ValueAtQuantile(50)).Seconds()*1000,
+			time.Duration(t.Cumulative.ValueAtQuantile(95)).Seconds()*1000,
+			time.Duration(t.Cumulative.ValueAtQuantile(99)).Seconds()*1000,
+			time.Duration(t.Cumulative.ValueAtQuantile(100)).Seconds()*1000,
+			t.Name,
+		)
+	})
+}
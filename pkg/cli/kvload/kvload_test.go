@@ -0,0 +1,111 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvload_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cli/kvload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvapi"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, _, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	rpcContext := rpc.NewContext(
+		log.AmbientContext{Tracer: s.ClusterSettings().Tracer},
+		testutils.NewTestBaseContext(security.RootUser), s.Clock(), s.Stopper(),
+		s.ClusterSettings(),
+	)
+	db, err := kvapi.NewDB(rpcContext, kvapi.Config{Addrs: []string{s.ServingRPCAddr()}})
+	require.NoError(t, err)
+
+	opts := kvload.Options{
+		Concurrency: 4,
+		Duration:    100 * time.Millisecond,
+		Keys:        100,
+		ValueSize:   16,
+		ScanLength:  10,
+		Init:        true,
+	}
+	// The operations which make up most of each mix, and are sure to be
+	// issued by such a short load.
+	expectedOps := map[string][]string{
+		"read-heavy":  {"read"},
+		"write-heavy": {"read", "write"},
+		"scan":        {"scan"},
+	}
+	for _, mix := range kvload.Mixes {
+		for _, dist := range []string{kvload.Uniform, kvload.Zipfian, kvload.Sequential} {
+			t.Run(fmt.Sprintf("%s/%s", mix.Name, dist), func(t *testing.T) {
+				opts := opts
+				opts.Mix = mix.Name
+				opts.Distribution = dist
+				opts.Prefix = fmt.Sprintf("load/%s/%s/", mix.Name, dist)
+
+				var out bytes.Buffer
+				require.NoError(t, kvload.Run(ctx, db, opts, &out))
+				require.Contains(t, out.String(), "wrote 100 keys")
+				require.Contains(t, out.String(), "ops(total)")
+				for _, op := range expectedOps[mix.Name] {
+					require.Regexp(t, " "+op+"\n", out.String())
+				}
+
+				// All the keys were written, and stayed within the key space.
+				prefix := roachpb.Key(opts.Prefix)
+				rows, err := kvDB.Scan(ctx, prefix, prefix.PrefixEnd(), 0 /* maxRows */)
+				require.NoError(t, err)
+				require.Len(t, rows, int(opts.Keys))
+			})
+		}
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		opts := opts
+		opts.Mix = "read-heavy"
+		opts.Distribution = kvload.Uniform
+		opts.Prefix = "load/canceled/"
+		// Canceling the load while the keys are written stops it cleanly.
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		var out bytes.Buffer
+		require.NoError(t, kvload.Run(canceledCtx, db, opts, &out))
+		require.Contains(t, out.String(), "interrupted while writing keys")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		opts := opts
+		opts.Mix = "read-heavy"
+		opts.Distribution = "gaussian"
+		require.EqualError(t, kvload.Run(ctx, db, opts, &bytes.Buffer{}),
+			`unknown key distribution "gaussian", expected one of: uniform, zipfian, sequential`)
+		opts.Mix = "read-only"
+		require.EqualError(t, kvload.Run(ctx, db, opts, &bytes.Buffer{}),
+			`unknown mix "read-only", expected one of: read-heavy, write-heavy, scan`)
+	})
+}
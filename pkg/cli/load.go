@@ -0,0 +1,69 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/kvload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvapi"
+	"github.com/spf13/cobra"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "generate load through the key-value API",
+	Long: `
+Run a YCSB-like load against a running cluster through its key-value API,
+and report the throughput and latency percentiles of each kind of operation
+every second and once the load is over. Since the load bypasses SQL, it
+measures the performance of the RPC and storage layers. The operations and
+keys are drawn from --seed, so that runs can be compared across builds.
+
+The load stops after --duration, or when interrupted. The keys operated on
+start with --key-prefix; the user of the client certificate must be allowed
+to write them, see the server.kv_api.user_permissions cluster setting.
+`,
+	Args: cobra.NoArgs,
+	RunE: MaybeDecorateGRPCError(runLoad),
+}
+
+func runLoad(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rpcContext, stopper := newClientRPCContext(serverCfg)
+	defer stopper.Stop(ctx)
+	addr, err := addrWithDefaultHost(serverCfg.AdvertiseAddr)
+	if err != nil {
+		return err
+	}
+	db, err := kvapi.NewDB(rpcContext, kvapi.Config{Addrs: []string{addr}})
+	if err != nil {
+		return err
+	}
+
+	// Stop the load, and report its totals, when interrupted.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return kvload.Run(ctx, db, loadCtx, os.Stdout)
+}
@@ -500,12 +500,12 @@ var drainNodeCmd = &cobra.Command{
 Prepare a server for shutting down. This stops accepting client
 connections, stops extant connections, and finally pushes range
 leases onto other nodes, subject to various timeout parameters
-configurable via cluster settings.`,
+configurable via cluster settings and the --drain-wait flag.`,
 	Args: cobra.NoArgs,
 	RunE: MaybeDecorateGRPCError(runDrain),
 }
 
-// runNodeDrain calls the Drain RPC without the flag to stop the
+// runDrain calls the Drain RPC without the flag to stop the
 // server process.
 func runDrain(cmd *cobra.Command, args []string) (err error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -479,6 +479,12 @@ func runStart(cmd *cobra.Command, args []string, disableReplication bool) error
 	// signals later, some startup logging might be lost.
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, drainSignals...)
+	// SIGHUP reloads the config file once the server has started. Subscribe
+	// to it now so that the signals received in the meantime are not lost.
+	var reloadSignalCh <-chan os.Signal
+	if startCtx.configFile != "" {
+		reloadSignalCh = sysutil.RefreshSignaledChan()
+	}
 
 	// Set up a cancellable context for the entire start command.
 	// The context will be canceled at the end.
@@ -677,6 +683,16 @@ If problems persist, please see ` + base.DocsURL("cluster-setup-troubleshooting.
 		started, draining bool
 	}
 	var s *server.Server
+	// Let SIGHUP and the ReloadConfig admin RPC reload the config file. The
+	// reloads only happen once the server has started, so s is set by then.
+	var reloader *configReloader
+	if startCtx.configFile != "" {
+		reloader = newConfigReloader(flagSetForCmd(cmd), startCtx.configFile, startCtx.configFileValues,
+			func(ctx context.Context, name string) error {
+				return applyReloadedFlag(s, name)
+			})
+		serverCfg.ReloadConfigFn = reloader.reload
+	}
 	errChan := make(chan error, 1)
 	go func() {
 		// Ensure that the log files see the startup messages immediately.
@@ -734,6 +750,9 @@ If problems persist, please see ` + base.DocsURL("cluster-setup-troubleshooting.
 
 				return errors.Wrap(err, "cockroach server exited with error")
 			}
+			if reloader != nil {
+				reloader.reloadOnSignal(ctx, stopper, reloadSignalCh)
+			}
 			// Server started, notify the shutdown monitor running concurrently.
 			serverStatusMu.Lock()
 			serverStatusMu.started = true
@@ -1279,14 +1298,9 @@ func addrWithDefaultHost(addr string) (string, error) {
 	return net.JoinHostPort(host, port), nil
 }
 
-// getClientGRPCConn returns a ClientConn, a Clock and a method that blocks
-// until the connection (and its associated goroutines) have terminated.
-func getClientGRPCConn(
-	ctx context.Context, cfg server.Config,
-) (*grpc.ClientConn, *hlc.Clock, func(), error) {
-	if ctx.Done() == nil {
-		return nil, nil, nil, errors.New("context must be cancellable")
-	}
+// newClientRPCContext returns an RPC context for a client of the cluster,
+// and the stopper which must be stopped once the client is done with it.
+func newClientRPCContext(cfg server.Config) (*rpc.Context, *stop.Stopper) {
 	// 0 to disable max offset checks; this RPC context is not a member of the
 	// cluster, so there's no need to enforce that its max offset is the same
 	// as that of nodes in the cluster.
@@ -1299,6 +1313,19 @@ func getClientGRPCConn(
 		stopper,
 		cfg.Settings,
 	)
+	return rpcContext, stopper
+}
+
+// getClientGRPCConn returns a ClientConn, a Clock and a method that blocks
+// until the connection (and its associated goroutines) have terminated.
+func getClientGRPCConn(
+	ctx context.Context, cfg server.Config,
+) (*grpc.ClientConn, *hlc.Clock, func(), error) {
+	if ctx.Done() == nil {
+		return nil, nil, nil, errors.New("context must be cancellable")
+	}
+	rpcContext, stopper := newClientRPCContext(cfg)
+	clock := rpcContext.LocalClock
 	addr, err := addrWithDefaultHost(cfg.AdvertiseAddr)
 	if err != nil {
 		stopper.Stop(ctx)
@@ -0,0 +1,161 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/spf13/cobra"
+)
+
+// userCtx captures the command-line parameters of the `user` commands.
+// Defaults set by InitCLIDefaults() above.
+var userCtx struct {
+	// promptPassword, if set, prompts for the password of the user.
+	promptPassword bool
+}
+
+var getUserCmd = &cobra.Command{
+	Use:   "get [options] <username>",
+	Short: "fetch and display a user",
+	Long: `
+Fetches and displays the user for <username>, and whether it has a password.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runGetUser),
+}
+
+func runGetUser(cmd *cobra.Command, args []string) error {
+	username := tree.Name(args[0]).Normalize()
+
+	conn, err := makeSQLClient("cockroach user", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, rows, err := runQuery(conn,
+		makeQuery(`SELECT count(username) FROM system.users WHERE username = $1 AND NOT "isRole"`, username), false)
+	if err != nil {
+		return err
+	}
+	if rows[0][0] != "1" {
+		return fmt.Errorf("user %q does not exist", username)
+	}
+	return runQueryAndFormatResults(conn, os.Stdout, makeQuery(`
+SELECT username,
+       length("hashedPassword") > 0 AS "has password"
+  FROM system.users
+ WHERE username = $1`, username))
+}
+
+var lsUsersCmd = &cobra.Command{
+	Use:   "ls [options]",
+	Short: "list all users",
+	Long: `
+Lists all the users, excluding roles.
+`,
+	Args: cobra.NoArgs,
+	RunE: MaybeDecorateGRPCError(runLsUsers),
+}
+
+func runLsUsers(cmd *cobra.Command, args []string) error {
+	conn, err := makeSQLClient("cockroach user", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return runQueryAndFormatResults(conn, os.Stdout,
+		makeQuery(`SELECT username FROM system.users WHERE NOT "isRole" ORDER BY username`))
+}
+
+var rmUserCmd = &cobra.Command{
+	Use:   "rm [options] <username>",
+	Short: "remove a user",
+	Long: `
+Removes the user for <username>. The user must not own any privileges.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runRmUser),
+}
+
+func runRmUser(cmd *cobra.Command, args []string) error {
+	username := tree.Name(args[0]).Normalize()
+
+	conn, err := makeSQLClient("cockroach user", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return runQueryAndFormatResults(conn, os.Stdout, makeQuery(`DROP USER $1`, username))
+}
+
+var setUserCmd = &cobra.Command{
+	Use:   "set [options] <username>",
+	Short: "create or update a user",
+	Long: `
+Creates the user for <username> if it does not exist yet. With --password,
+prompts for a password which the user can then use instead of a client
+certificate to log into SQL and the Admin UI. The password is stored hashed
+in the system.users table. Passwords cannot be set on insecure clusters.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runSetUser),
+}
+
+func runSetUser(cmd *cobra.Command, args []string) error {
+	username := tree.Name(args[0]).Normalize()
+
+	var pwd string
+	if userCtx.promptPassword {
+		var err error
+		if pwd, err = security.PromptForPasswordTwice(); err != nil {
+			return err
+		}
+	}
+
+	conn, err := makeSQLClient("cockroach user", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Exec(`CREATE USER IF NOT EXISTS $1`, []driver.Value{username}); err != nil {
+		return err
+	}
+	if userCtx.promptPassword {
+		return conn.Exec(`ALTER USER $1 WITH PASSWORD $2`, []driver.Value{username, pwd})
+	}
+	return nil
+}
+
+var userCmds = []*cobra.Command{
+	getUserCmd,
+	lsUsersCmd,
+	rmUserCmd,
+	setUserCmd,
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "get, set, list and remove users",
+	RunE:  usageAndErr,
+}
+
+func init() {
+	userCmd.AddCommand(userCmds...)
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+func Example_user() {
+	c := newCLITest(cliTestParams{})
+	defer c.cleanup()
+
+	c.Run("user ls")
+	c.Run("user set FOO")
+	c.Run("user set foo")
+	c.Run("user ls")
+	c.Run("user get foo")
+	c.Run("user get bar")
+	c.Run("user rm foo")
+	c.Run("user ls")
+
+	// Output:
+	// user ls
+	// username
+	// root
+	// user set FOO
+	// user set foo
+	// user ls
+	// username
+	// foo
+	// root
+	// user get foo
+	// username	has password
+	// foo	false
+	// user get bar
+	// ERROR: user "bar" does not exist
+	// user rm foo
+	// DROP ROLE
+	// user ls
+	// username
+	// root
+}
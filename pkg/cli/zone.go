@@ -0,0 +1,201 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// parseZoneSpecifier parses a zone target as it would appear in an
+// ALTER ... CONFIGURE ZONE statement, for example "RANGE default",
+// "DATABASE db" or "INDEX db.t@idx". The specifier is re-formatted before
+// being embedded in a query, so the input cannot inject arbitrary SQL.
+func parseZoneSpecifier(target string) (string, error) {
+	stmt, err := parser.ParseOne(fmt.Sprintf("ALTER %s CONFIGURE ZONE DISCARD", target))
+	if err != nil {
+		return "", errors.Errorf("invalid zone target %q: expected e.g. \"RANGE default\", "+
+			"\"DATABASE db\", \"TABLE db.t\" or \"INDEX db.t@idx\"", target)
+	}
+	setZone, ok := stmt.AST.(*tree.SetZoneConfig)
+	if !ok || setZone.AllIndexes {
+		return "", errors.Errorf("invalid zone target %q", target)
+	}
+	return setZone.ZoneSpecifier.String(), nil
+}
+
+var zoneLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "list all zone configurations",
+	Long: `
+List the targets of all zone configurations which have been set in the cluster.
+`,
+	Args: cobra.NoArgs,
+	RunE: maybeShoutError(runZoneLs),
+}
+
+func runZoneLs(cmd *cobra.Command, args []string) error {
+	conn, err := makeSQLClient("cockroach zone", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cols, rows, err := runQuery(conn, makeQuery(`SELECT target FROM [SHOW ZONE CONFIGURATIONS]`), false)
+	if err != nil {
+		return err
+	}
+	return printQueryOutput(os.Stdout, cols, newRowSliceIter(rows, "l"))
+}
+
+var zoneGetCmd = &cobra.Command{
+	Use:   "get <target>",
+	Short: "fetch the zone configuration for a target",
+	Long: `
+Fetch and display in YAML format the zone configuration which applies to the
+specified target, which is one of "RANGE <name>", "DATABASE <db>",
+"TABLE <db>.<table>", "INDEX <db>.<table>@<index>" or
+"PARTITION <partition> OF TABLE <db>.<table>". If the target has no zone
+configuration of its own, the configuration it inherits is displayed.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: maybeShoutError(runZoneGet),
+}
+
+func runZoneGet(cmd *cobra.Command, args []string) error {
+	zs, err := parseZoneSpecifier(args[0])
+	if err != nil {
+		return err
+	}
+
+	conn, err := makeSQLClient("cockroach zone", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return printZoneConfig(conn, zs)
+}
+
+// printZoneConfig displays the zone configuration which applies to the given
+// zone specifier.
+func printZoneConfig(conn *sqlConn, zs string) error {
+	vals, err := conn.QueryRow(fmt.Sprintf(
+		`SELECT full_config_yaml FROM [SHOW ZONE CONFIGURATION FOR %s]`, zs), nil)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s", vals[0])
+	return nil
+}
+
+var zoneSetCmd = &cobra.Command{
+	Use:   "set <target> --file=<file>",
+	Short: "create or update the zone configuration for a target",
+	Long: `
+Create or update the zone configuration for the specified target (see
+'zone get --help' for the target syntax) from a YAML file. Fields not
+present in the file are inherited from the parent zone. For example:
+
+  echo 'num_replicas: 5' | cockroach zone set "RANGE default" -f -
+
+The resulting zone configuration is displayed on success.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: maybeShoutError(runZoneSet),
+}
+
+func runZoneSet(cmd *cobra.Command, args []string) error {
+	zs, err := parseZoneSpecifier(args[0])
+	if err != nil {
+		return err
+	}
+
+	var config []byte
+	switch zoneCtx.zoneConfig {
+	case "":
+		return errors.New("no zone configuration specified; use --file")
+	case "-":
+		config, err = ioutil.ReadAll(os.Stdin)
+	default:
+		config, err = ioutil.ReadFile(zoneCtx.zoneConfig)
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading zone configuration")
+	}
+
+	conn, err := makeSQLClient("cockroach zone", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Exec(fmt.Sprintf(`ALTER %s CONFIGURE ZONE = %s`,
+		zs, lex.EscapeSQLString(string(config))), nil); err != nil {
+		return err
+	}
+	return printZoneConfig(conn, zs)
+}
+
+var zoneRmCmd = &cobra.Command{
+	Use:   "rm <target>",
+	Short: "remove the zone configuration for a target",
+	Long: `
+Remove the zone configuration for the specified target (see 'zone get --help'
+for the target syntax), after which it inherits the configuration of its
+parent zone. The zone configuration for "RANGE default" cannot be removed.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: maybeShoutError(runZoneRm),
+}
+
+func runZoneRm(cmd *cobra.Command, args []string) error {
+	zs, err := parseZoneSpecifier(args[0])
+	if err != nil {
+		return err
+	}
+
+	conn, err := makeSQLClient("cockroach zone", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Exec(fmt.Sprintf(`ALTER %s CONFIGURE ZONE DISCARD`, zs), nil)
+}
+
+var zoneCmds = []*cobra.Command{
+	zoneLsCmd,
+	zoneGetCmd,
+	zoneSetCmd,
+	zoneRmCmd,
+}
+
+var zoneCmd = &cobra.Command{
+	Use:   "zone [command]",
+	Short: "list, get, set and remove zone configurations",
+	Long: `
+List, get, set and remove zone configurations, which control the replication
+factor and placement of data in the cluster.
+`,
+	RunE: usageAndErr,
+}
+
+func init() {
+	zoneCmd.AddCommand(zoneCmds...)
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestParseZoneSpecifier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		target   string
+		expected string
+		err      string
+	}{
+		{target: "RANGE default", expected: "RANGE default"},
+		{target: "range meta", expected: "RANGE meta"},
+		{target: "DATABASE db", expected: "DATABASE db"},
+		{target: "TABLE db.t", expected: "TABLE db.t"},
+		{target: `TABLE "my db".t`, expected: `TABLE "my db".t`},
+		{target: "INDEX db.t@idx", expected: "INDEX db.t@idx"},
+		{target: "PARTITION p OF TABLE db.t", expected: "PARTITION p OF TABLE db.t"},
+		{target: "PARTITION p OF INDEX db.t@*", err: "invalid zone target"},
+		{target: "db.t", err: "invalid zone target"},
+		{target: "RANGE default CONFIGURE ZONE DISCARD; DROP DATABASE db; ALTER RANGE default", err: "invalid zone target"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.target, func(t *testing.T) {
+			zs, err := parseZoneSpecifier(tc.target)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err == nil && zs != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, zs)
+			}
+		})
+	}
+}
@@ -62,6 +62,10 @@ const (
 	Version20_1
 	VersionStart20_2
 	VersionGeospatialType
+	VersionCompactionGCFilter
+	VersionIntegerMerge
+	VersionScrubRangeLogEvent
+	VersionGossipCompression
 
 	// Add new versions here (step one of two).
 )
@@ -474,6 +478,33 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionGeospatialType,
 		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 2},
 	},
+	{
+		// VersionCompactionGCFilter enables the storage engine compactions to
+		// drop the MVCC versions shadowed below the GC threshold, which are
+		// then excluded from the replica checksums (see
+		// storage.CompactionGCEnabled).
+		Key:     VersionCompactionGCFilter,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 3},
+	},
+	{
+		// VersionIntegerMerge enables the merges of integer values, which the
+		// merge operator sums. Older versions concatenate them like bytes.
+		Key:     VersionIntegerMerge,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 4},
+	},
+	{
+		// VersionScrubRangeLogEvent enables the scrub_corruption range log
+		// events, which older versions can't parse.
+		Key:     VersionScrubRangeLogEvent,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 5},
+	},
+	{
+		// VersionGossipCompression enables the compression of gossip streams
+		// (see COCKROACH_GOSSIP_COMPRESSION_THRESHOLD), whose grpc-encoding
+		// older versions reject.
+		Key:     VersionGossipCompression,
+		Version: roachpb.Version{Major: 20, Minor: 1, Unstable: 6},
+	},
 
 	// Add new versions here (step two of two).
 
@@ -18,6 +18,10 @@
 //
 // To exclude this file except during fuzzing, tag it with:
 //   // +build gofuzz
+//
+// Seed inputs for a Fuzz func can be checked in, one per file, under the
+// testdata/fuzz/<FuzzXXX> directory of its package. They are copied into the
+// corpus of the go-fuzz workdir before fuzzing starts.
 package main
 
 import (
@@ -155,6 +159,9 @@ func execGoFuzz(
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	workdir := fmt.Sprintf("work-%s", fn)
+	if err := seedCorpus(dir, workdir, fn); err != nil {
+		fatal(err)
+	}
 	cmd := exec.CommandContext(ctx, "go-fuzz", "-func", fn, "-workdir", workdir)
 	cmd.Dir = dir
 	stderr, err := cmd.StderrPipe()
@@ -194,6 +201,36 @@ func execGoFuzz(
 	return crashers
 }
 
+// seedCorpus copies the seed inputs of fn, if any, into the corpus of the
+// workdir, next to the inputs found by previous runs.
+func seedCorpus(dir, workdir, fn string) error {
+	seedDir := filepath.Join(dir, "testdata", "fuzz", fn)
+	seeds, err := ioutil.ReadDir(seedDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	corpusDir := filepath.Join(dir, workdir, "corpus")
+	if err := os.MkdirAll(corpusDir, 0755); err != nil {
+		return err
+	}
+	for _, seed := range seeds {
+		if seed.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(seedDir, seed.Name()))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(corpusDir, seed.Name()), content, 0644); err != nil {
+			return err
+		}
+	}
+	log("%s: seeded corpus with %d inputs\n", fn, len(seeds))
+	return nil
+}
+
 var fuzzFuncRE = regexp.MustCompile(`(?m)^func (Fuzz\w*)\(\w+ \[\]byte\) int {$`)
 
 // findFuncs returns a list of fuzzable function names in the given package.
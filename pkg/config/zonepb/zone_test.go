@@ -382,6 +382,53 @@ func TestZoneConfigSubzones(t *testing.T) {
 	}
 }
 
+// TestZoneConfigInheritFromParent verifies that InheritFromParent fills in
+// the fields a zone config doesn't set from those of its parent.
+func TestZoneConfigInheritFromParent(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	parent := DefaultZoneConfig()
+	parent.Constraints = []ConstraintsConjunction{
+		{Constraints: []Constraint{{Value: "ssd", Type: Constraint_REQUIRED}}},
+	}
+	parent.LeasePreferences = []LeasePreference{
+		{Constraints: []Constraint{{Key: "region", Value: "us", Type: Constraint_REQUIRED}}},
+	}
+
+	// A zone without any fields of its own inherits all of them, and the
+	// inherited GC policy does not alias the parent's.
+	child := NewZoneConfig()
+	child.InheritFromParent(&parent)
+	require.True(t, child.IsComplete())
+	require.Equal(t, parent, *child)
+	child.GC.TTLSeconds++
+	require.Equal(t, DefaultZoneConfig().GC.TTLSeconds, parent.GC.TTLSeconds)
+
+	// Fields set on the zone are kept.
+	child = NewZoneConfig()
+	child.NumReplicas = proto.Int32(5)
+	child.GC = &GCPolicy{TTLSeconds: 600}
+	child.InheritFromParent(&parent)
+	require.Equal(t, int32(5), *child.NumReplicas)
+	require.Equal(t, int32(600), child.GC.TTLSeconds)
+	require.Equal(t, *parent.RangeMaxBytes, *child.RangeMaxBytes)
+
+	// Subzone placeholders take their replication factor from the parent.
+	child = NewZoneConfig()
+	child.DeleteTableConfig()
+	child.InheritFromParent(&parent)
+	require.Equal(t, *parent.NumReplicas, *child.NumReplicas)
+
+	// Constraints and lease preferences the parent itself inherits remain
+	// marked as inherited, so they are resolved further up the hierarchy.
+	incompleteParent := NewZoneConfig()
+	child = NewZoneConfig()
+	child.InheritFromParent(incompleteParent)
+	require.True(t, child.InheritedConstraints)
+	require.True(t, child.InheritedLeasePreferences)
+	require.False(t, child.IsComplete())
+}
+
 // TestZoneConfigMarshalYAML makes sure that ZoneConfig is correctly marshaled
 // to YAML and back.
 func TestZoneConfigMarshalYAML(t *testing.T) {
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 // client is a client-side RPC connection to a gossip peer node.
@@ -38,11 +39,20 @@ type client struct {
 	addr                  net.Addr                 // Peer node network address
 	forwardAddr           *util.UnresolvedAddr     // Set if disconnected with an alternate addr
 	remoteHighWaterStamps map[roachpb.NodeID]int64 // Remote server's high water timestamps
+	sentHighWaterStamps   map[roachpb.NodeID]int64 // High water timestamps sent to the remote server
 	closer                chan struct{}            // Client shutdown channel
 	clientMetrics         Metrics
 	nodeMetrics           Metrics
 }
 
+// clientHealthCheckInterval is the interval at which a client checks the
+// health of its RPC connection.
+const clientHealthCheckInterval = time.Second
+
+// errUnhealthyConnection is returned by a client whose RPC connection has
+// failed its heartbeats.
+var errUnhealthyConnection = errors.New("rpc connection unhealthy")
+
 // extractKeys returns a string representation of a gossip delta's keys.
 func extractKeys(delta map[string]*Info) string {
 	keys := make([]string, 0, len(delta))
@@ -59,6 +69,7 @@ func newClient(ambient log.AmbientContext, addr net.Addr, nodeMetrics Metrics) *
 		createdAt:             timeutil.Now(),
 		addr:                  addr,
 		remoteHighWaterStamps: map[roachpb.NodeID]int64{},
+		sentHighWaterStamps:   map[roachpb.NodeID]int64{},
 		closer:                make(chan struct{}),
 		clientMetrics:         makeMetrics(),
 		nodeMetrics:           nodeMetrics,
@@ -100,16 +111,22 @@ func (c *client) startLocked(
 
 		consecFailures := breaker.ConsecFailures()
 		var stream Gossip_GossipClient
+		var rpcConn *rpc.Connection
 		if err := breaker.Call(func() error {
 			// Note: avoid using `grpc.WithBlock` here. This code is already
 			// asynchronous from the caller's perspective, so the only effect of
 			// `WithBlock` here is blocking shutdown - at the time of this writing,
 			// that ends ups up making `kv` tests take twice as long.
-			conn, err := rpcCtx.GRPCUnvalidatedDial(c.addr.String()).Connect(ctx)
+			rpcConn = rpcCtx.GRPCUnvalidatedDial(c.addr.String())
+			conn, err := rpcConn.Connect(ctx)
 			if err != nil {
 				return err
 			}
-			if stream, err = NewGossipClient(conn).Gossip(ctx); err != nil {
+			var opts []grpc.CallOption
+			if useGossipCompression(ctx, rpcCtx.Settings()) {
+				opts = append(opts, grpc.UseCompressor(gossipCompressorName))
+			}
+			if stream, err = NewGossipClient(conn).Gossip(ctx, opts...); err != nil {
 				return err
 			}
 			return c.requestGossip(g, stream)
@@ -122,7 +139,12 @@ func (c *client) startLocked(
 
 		// Start gossiping.
 		log.Infof(ctx, "started gossip client to %s", c.addr)
-		if err := c.gossip(ctx, g, stream, stopper, &wg); err != nil {
+		if err := c.gossip(ctx, g, stream, stopper, &wg, rpcConn.Health); err != nil {
+			if errors.Cause(err) == errUnhealthyConnection {
+				// Hold off on reconnecting to this peer until the breaker
+				// allows it, so that the replacement client connects elsewhere.
+				breaker.Fail(err)
+			}
 			if !grpcutil.IsClosedConnection(err) {
 				g.mu.RLock()
 				if c.peerID != 0 {
@@ -153,7 +175,7 @@ func (c *client) requestGossip(g *Gossip, stream Gossip_GossipClient) error {
 	args := &Request{
 		NodeID:          g.NodeID.Get(),
 		Addr:            g.mu.is.NodeAddr,
-		HighWaterStamps: g.mu.is.getHighWaterStamps(),
+		HighWaterStamps: g.mu.is.getHighWaterStampsWithDiff(c.sentHighWaterStamps),
 		ClusterID:       g.clusterID.Get(),
 	}
 	g.mu.RUnlock()
@@ -185,7 +207,7 @@ func (c *client) sendGossip(g *Gossip, stream Gossip_GossipClient, firstReq bool
 			NodeID:          g.NodeID.Get(),
 			Addr:            g.mu.is.NodeAddr,
 			Delta:           delta,
-			HighWaterStamps: g.mu.is.getHighWaterStamps(),
+			HighWaterStamps: g.mu.is.getHighWaterStampsWithDiff(c.sentHighWaterStamps),
 			ClusterID:       g.clusterID.Get(),
 		}
 
@@ -227,6 +249,16 @@ func (c *client) handleResponse(ctx context.Context, g *Gossip, reply *Response)
 
 	// Combine remote node's infostore delta with ours.
 	if reply.Delta != nil {
+		if err := checkDeltaClusterID(reply.Delta, g.clusterID.Get()); err != nil {
+			return err
+		}
+		if reply.FullState {
+			if n := g.mu.is.countDiscrepancies(reply.Delta); n > 0 {
+				g.antiEntropyDiscrepancies.Inc(int64(n))
+				log.Infof(ctx, "anti-entropy exchange with n%d found %d missing or stale info(s)",
+					reply.NodeID, n)
+			}
+		}
 		freshCount, err := g.mu.is.combine(reply.Delta, reply.NodeID)
 		if err != nil {
 			log.Warningf(ctx, "failed to fully combine delta from n%d: %s", reply.NodeID, err)
@@ -295,6 +327,7 @@ func (c *client) gossip(
 	stream Gossip_GossipClient,
 	stopper *stop.Stopper,
 	wg *sync.WaitGroup,
+	connHealth func() error,
 ) error {
 	sendGossipChan := make(chan struct{}, 1)
 
@@ -357,11 +390,28 @@ func (c *client) gossip(
 			// propagating gossip infos to other nodes and needs to propagate the new
 			// expiration info.
 			unregister = g.RegisterCallback(".*", updateCallback, Redundant)
+			// Updates to priority keys bypass the callback queue.
+			g.mu.Lock()
+			unregisterPriority := g.mu.is.registerPriorityNotifier(sendGossipChan)
+			g.mu.Unlock()
+			unregisterCallback := unregister
+			unregister = func() {
+				unregisterCallback()
+				g.mu.Lock()
+				unregisterPriority()
+				g.mu.Unlock()
+			}
 		}
 	}
 	initTimer := time.NewTimer(time.Second)
 	defer initTimer.Stop()
 
+	// Monitor the health of the underlying RPC connection as determined by
+	// its heartbeats, which detect a failed peer more quickly than the
+	// gossip stream itself.
+	healthTicker := time.NewTicker(clientHealthCheckInterval)
+	defer healthTicker.Stop()
+
 	for count := 0; ; {
 		select {
 		case <-c.closer:
@@ -374,6 +424,10 @@ func (c *client) gossip(
 			maybeRegister()
 		case <-initTimer.C:
 			maybeRegister()
+		case <-healthTicker.C:
+			if err := connHealth(); err != nil && err != rpc.ErrNotHeartbeated {
+				return errors.Wrapf(errUnhealthyConnection, "%s", err)
+			}
 		case <-sendGossipChan:
 			if err := c.sendGossip(g, stream, count == 0); err != nil {
 				return err
@@ -214,6 +214,59 @@ func TestClientGossip(t *testing.T) {
 	})
 }
 
+// TestClientGossipAntiEntropy verifies that an info which is never part of
+// an incremental delta is repaired by the periodic full state exchange.
+func TestClientGossipAntiEntropy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+
+	// Shared cluster ID by all gossipers (this ensures that the gossipers
+	// don't talk to servers from unrelated tests by accident).
+	clusterID := uuid.MakeV4()
+
+	local := startGossip(clusterID, 1, stopper, t, metric.NewRegistry())
+	remote := startGossip(clusterID, 2, stopper, t, metric.NewRegistry())
+	remote.SetAntiEntropyInterval(10 * time.Millisecond)
+	disconnected := make(chan *client, 1)
+	c := newClient(log.AmbientContext{Tracer: tracing.NewTracer()}, remote.GetNodeAddr(), makeMetrics())
+
+	defer func() {
+		stopper.Stop(context.TODO())
+		if c != <-disconnected {
+			t.Errorf("expected client disconnect after remote close")
+		}
+	}()
+
+	if err := remote.AddInfo("remote-key", nil, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	gossipSucceedsSoon(t, stopper, clusterID, disconnected, map[*client]*Gossip{
+		c: local,
+	}, func() error {
+		_, err := local.GetInfo("remote-key")
+		return err
+	})
+
+	// Sneak an info into the remote infostore which is older than the
+	// remote's high water stamp as already seen by the local node.
+	remote.mu.Lock()
+	hidden := remote.mu.is.newInfo(nil, time.Hour)
+	hidden.Value.InitChecksum([]byte("hidden-key"))
+	hidden.OrigStamp = remote.mu.is.getInfo("remote-key").OrigStamp - 1
+	remote.mu.is.Infos["hidden-key"] = hidden
+	remote.mu.Unlock()
+
+	testutils.SucceedsSoon(t, func() error {
+		if _, err := local.GetInfo("hidden-key"); err != nil {
+			return err
+		}
+		if n := local.antiEntropyDiscrepancies.Count(); n == 0 {
+			return errors.New("expected anti-entropy discrepancy to be recorded")
+		}
+		return nil
+	})
+}
+
 // TestClientGossipMetrics verifies that gossip stats are generated.
 func TestClientGossipMetrics(t *testing.T) {
 	defer leaktest.AfterTest(t)()
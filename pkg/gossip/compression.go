@@ -0,0 +1,137 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/encoding"
+)
+
+// gossipCompressorName is the grpc-encoding under which gossip streams
+// negotiate compression. The server replies using the encoding chosen by
+// the client, so compression is decided per connection by the dialing
+// node. Servers which don't recognize the encoding reject the stream, so
+// clients only use it once VersionGossipCompression is active, which
+// guarantees that all nodes register the encoding.
+const gossipCompressorName = "gossip-snappy"
+
+// gossipCompressionThreshold is the size in bytes above which gossip
+// messages are snappy compressed. Smaller messages, such as the
+// high-water stamp updates which make up most gossip traffic, are sent
+// as is since compressing them costs more than it saves.
+//
+// Gossip-specific compression is disabled by default (a value of zero):
+// connections are already snappy compressed as a whole unless
+// COCKROACH_ENABLE_RPC_COMPRESSION is turned off, so compressing gossip a
+// second time gains nothing. It is meant for clusters which run with RPC
+// compression disabled and exchange large gossip infos.
+var gossipCompressionThreshold = envutil.EnvOrDefaultBytes(
+	"COCKROACH_GOSSIP_COMPRESSION_THRESHOLD", 0)
+
+// useGossipCompression returns whether gossip streams dialed by the node
+// should be compressed. Until the node knows the cluster version, it can't
+// tell whether its peers understand the encoding, and doesn't compress.
+func useGossipCompression(ctx context.Context, st *cluster.Settings) bool {
+	if gossipCompressionThreshold <= 0 {
+		return false
+	}
+	return st.Version.ActiveVersionOrEmpty(ctx).IsActive(clusterversion.VersionGossipCompression)
+}
+
+// Each message written by thresholdCompressor is prefixed by one of the
+// following bytes, indicating how the remainder is encoded.
+const (
+	encodingRaw    byte = 0
+	encodingSnappy byte = 1
+)
+
+// thresholdCompressor is an encoding.Compressor which only compresses
+// messages exceeding a size threshold. Like all encoding.Compressor
+// implementations, it must be goroutine safe.
+type thresholdCompressor struct {
+	threshold int
+}
+
+var _ encoding.Compressor = thresholdCompressor{}
+
+// Name implements the encoding.Compressor interface.
+func (thresholdCompressor) Name() string {
+	return gossipCompressorName
+}
+
+// Compress implements the encoding.Compressor interface.
+func (c thresholdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &thresholdWriter{w: w, threshold: c.threshold}, nil
+}
+
+// Decompress implements the encoding.Compressor interface.
+func (thresholdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	var header [1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	switch header[0] {
+	case encodingRaw:
+		return r, nil
+	case encodingSnappy:
+		compressed, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		decompressed, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(decompressed), nil
+	default:
+		return nil, errors.Errorf("unknown gossip message encoding %d", header[0])
+	}
+}
+
+// thresholdWriter buffers a message so that the decision of whether to
+// compress it can be made once its full size is known.
+type thresholdWriter struct {
+	w         io.Writer
+	threshold int
+	buf       bytes.Buffer
+}
+
+func (tw *thresholdWriter) Write(p []byte) (int, error) {
+	return tw.buf.Write(p)
+}
+
+func (tw *thresholdWriter) Close() error {
+	if tw.buf.Len() < tw.threshold {
+		if _, err := tw.w.Write([]byte{encodingRaw}); err != nil {
+			return err
+		}
+		_, err := tw.w.Write(tw.buf.Bytes())
+		return err
+	}
+	if _, err := tw.w.Write([]byte{encodingSnappy}); err != nil {
+		return err
+	}
+	_, err := tw.w.Write(snappy.Encode(nil, tw.buf.Bytes()))
+	return err
+}
+
+func init() {
+	encoding.RegisterCompressor(thresholdCompressor{threshold: int(gossipCompressionThreshold)})
+}
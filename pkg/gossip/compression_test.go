@@ -0,0 +1,102 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestThresholdCompressor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	c := thresholdCompressor{threshold: 64}
+
+	testCases := []struct {
+		msg      []byte
+		encoding byte
+	}{
+		{nil, encodingRaw},
+		{bytes.Repeat([]byte("a"), 63), encodingRaw},
+		{bytes.Repeat([]byte("a"), 64), encodingSnappy},
+		{bytes.Repeat([]byte("abcdef"), 1000), encodingSnappy},
+	}
+	for i, tc := range testCases {
+		var buf bytes.Buffer
+		w, err := c.Compress(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(tc.msg); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if e := buf.Bytes()[0]; e != tc.encoding {
+			t.Errorf("%d: expected encoding %d; got %d", i, tc.encoding, e)
+		}
+		if tc.encoding == encodingSnappy && buf.Len() >= len(tc.msg) {
+			t.Errorf("%d: expected compressed size < %d; got %d", i, len(tc.msg), buf.Len())
+		}
+
+		r, err := c.Decompress(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(tc.msg, out) {
+			t.Errorf("%d: expected %q after round trip; got %q", i, tc.msg, out)
+		}
+	}
+}
+
+// TestUseGossipCompression verifies that gossip streams are only compressed
+// once all nodes are known to understand the encoding.
+func TestUseGossipCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	defer func(threshold int64) { gossipCompressionThreshold = threshold }(gossipCompressionThreshold)
+
+	testCases := []struct {
+		threshold int64
+		version   roachpb.Version
+		exp       bool
+	}{
+		{0, clusterversion.TestingBinaryVersion, false},
+		{64, clusterversion.TestingBinaryVersion, true},
+		{64, clusterversion.VersionByKey(clusterversion.VersionGossipCompression - 1), false},
+		{64, roachpb.Version{}, false},
+	}
+	for i, tc := range testCases {
+		gossipCompressionThreshold = tc.threshold
+		st := cluster.MakeTestingClusterSettingsWithVersions(
+			clusterversion.TestingBinaryVersion, clusterversion.TestingBinaryMinSupportedVersion,
+			false /* initializeVersion */)
+		if tc.version != (roachpb.Version{}) {
+			if err := clusterversion.Initialize(ctx, tc.version, &st.SV); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if a := useGossipCompression(ctx, st); a != tc.exp {
+			t.Errorf("%d: expected %t; got %t", i, tc.exp, a)
+		}
+	}
+}
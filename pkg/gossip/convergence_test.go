@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/pkg/errors"
 )
 
 // The tests in this package have fairly small cluster sizes for the sake of
@@ -74,6 +75,17 @@ func TestConvergence(t *testing.T) {
 			"expected network to fully connect with <= %d connections refused; took %d",
 			maxConnsRefused, connsRefused)
 	}
+
+	// Connections are established asynchronously, and may be culled and
+	// replaced once the network is connected, but every node must hold at
+	// least one.
+	testutils.SucceedsSoon(t, func() error {
+		if fanout := network.Fanout(); fanout.Min == 0 {
+			return errors.Errorf(
+				"expected every node in a fully-connected network to hold a connection; fanout %s", fanout)
+		}
+		return nil
+	})
 }
 
 // TestNetworkReachesEquilibrium ensures that the gossip network stops bouncing
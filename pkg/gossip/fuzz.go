@@ -0,0 +1,94 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// maxFuzzMessageSize bounds the size of decompressed messages, like the
+// default maximum message size of gRPC servers.
+const maxFuzzMessageSize = 4 << 20
+
+// FuzzDecodeRequest decodes data as a gossip Request received by the
+// server, compressed or not, and combines its delta into an infostore.
+func FuzzDecodeRequest(data []byte) int {
+	var args Request
+	if !decodeGossipMessage(data, &args) {
+		return 0
+	}
+	return combineFuzzDelta(args.Delta, args.NodeID)
+}
+
+// FuzzDecodeResponse decodes data as a gossip Response received by the
+// client and combines its delta into an infostore.
+func FuzzDecodeResponse(data []byte) int {
+	var reply Response
+	if !decodeGossipMessage(data, &reply) {
+		return 0
+	}
+	return combineFuzzDelta(reply.Delta, reply.NodeID)
+}
+
+func decodeGossipMessage(data []byte, msg protoutil.Message) bool {
+	r, err := thresholdCompressor{}.Decompress(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, maxFuzzMessageSize+1))
+	if err != nil || len(b) > maxFuzzMessageSize {
+		return false
+	}
+	return protoutil.Unmarshal(b, msg) == nil
+}
+
+// combineFuzzDelta combines the delta into a fresh infostore and decodes
+// the descriptors it carries, like the callbacks of Gossip do.
+func combineFuzzDelta(delta map[string]*Info, nodeID roachpb.NodeID) int {
+	ctx := context.Background()
+	// The infostore runs its callbacks on a worker of the stopper, which needs
+	// to be stopped for every input not to leak a goroutine.
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	localNodeID := &base.NodeIDContainer{}
+	localNodeID.Set(ctx, 1)
+	is := newInfoStore(log.AmbientContext{Tracer: tracing.NewTracer()}, localNodeID, util.UnresolvedAddr{}, stopper)
+	if _, err := is.combine(delta, nodeID); err != nil {
+		return 0
+	}
+	for key, i := range is.Infos {
+		var err error
+		switch {
+		case IsNodeIDKey(key):
+			var desc roachpb.NodeDescriptor
+			err = i.Value.GetProto(&desc)
+		case strings.HasPrefix(key, KeyStorePrefix):
+			var desc roachpb.StoreDescriptor
+			err = i.Value.GetProto(&desc)
+		}
+		if err != nil {
+			return 0
+		}
+	}
+	return 1
+}
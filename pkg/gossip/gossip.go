@@ -40,8 +40,8 @@ the system with minimal total hops. The algorithm is as follows:
       node is considered partitioned; goto #1.
 
  3 On connect, if node has too many connected clients, gossip requests
-   are returned immediately with an alternate address set to a random
-   selection from amongst already-connected clients.
+   are returned immediately with an alternate address set to the
+   least-loaded of the already-connected clients.
 */
 
 package gossip
@@ -68,6 +68,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -82,14 +84,14 @@ import (
 )
 
 const (
-	// maxHops is the maximum number of hops which any gossip info
-	// should require to transit between any two nodes in a gossip
+	// maxHops is the default maximum number of hops which any gossip
+	// info should require to transit between any two nodes in a gossip
 	// network.
 	maxHops = 5
 
-	// minPeers is the minimum number of peers which the maxPeers()
-	// function will return. This is set higher than one to prevent
-	// excessive tightening of the network.
+	// minPeers is the default minimum number of peers which the
+	// maxPeers() function will return. This is set higher than one to
+	// prevent excessive tightening of the network.
 	minPeers = 3
 
 	// defaultStallInterval is the default interval for checking whether
@@ -107,6 +109,23 @@ const (
 	// efficiently targeted connection to the most distant node.
 	defaultCullInterval = 60 * time.Second
 
+	// defaultAntiEntropyInterval is the default interval at which a gossip
+	// server sends its full infostore to each connected client, regardless
+	// of the client's high water stamps, to repair any updates which failed
+	// to propagate incrementally.
+	defaultAntiEntropyInterval = 10 * time.Minute
+
+	// bootstrapBlacklistThreshold is the number of consecutive failed
+	// connection attempts after which a bootstrap address is temporarily
+	// skipped in favor of the other addresses.
+	bootstrapBlacklistThreshold = 3
+
+	// bootstrapBlacklistBase and bootstrapBlacklistMax bound the duration
+	// for which a failing bootstrap address is skipped. The duration doubles
+	// with each further failure.
+	bootstrapBlacklistBase = 5 * time.Second
+	bootstrapBlacklistMax  = 2 * time.Minute
+
 	// defaultClientsInterval is the default interval for updating the gossip
 	// clients key which allows every node in the cluster to create a map of
 	// gossip connectivity. This value is intentionally small as we want to
@@ -175,6 +194,24 @@ var (
 		Measurement: "Gossip Bytes",
 		Unit:        metric.Unit_BYTES,
 	}
+	MetaHopsMax = metric.Metadata{
+		Name:        "gossip.hops.max",
+		Help:        "Maximum number of hops to any other node's descriptor",
+		Measurement: "Hops",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaHopsAvg = metric.Metadata{
+		Name:        "gossip.hops.avg",
+		Help:        "Average number of hops to other nodes' descriptors",
+		Measurement: "Hops",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaAntiEntropyDiscrepancies = metric.Metadata{
+		Name:        "gossip.antientropy.discrepancies",
+		Help:        "Number of missing or stale infos found during anti-entropy exchanges",
+		Measurement: "Infos",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // KeyNotPresentError is returned by gossip when queried for a key that doesn't
@@ -230,6 +267,12 @@ type Gossip struct {
 	bootstrapInfo BootstrapInfo       // BootstrapInfo proto for persistent storage
 	bootstrapping map[string]struct{} // Set of active bootstrap clients
 	hasCleanedBS  bool
+	// bootstrapFailures tracks consecutive failed connection attempts to
+	// bootstrap addresses, keyed by address.
+	bootstrapFailures map[string]*bootstrapFailure
+	// recentPeersPersisted is the time at which the recent peers in
+	// bootstrapInfo were last written to storage.
+	recentPeersPersisted time.Time
 
 	// Note that access to each client's internal state is serialized by the
 	// embedded server's mutex. This is surprising!
@@ -248,6 +291,14 @@ type Gossip struct {
 	bootstrapInterval time.Duration
 	cullInterval      time.Duration
 
+	// fanout is protected by the embedded server's mutex.
+	fanout FanoutConfig
+
+	hopsMax *metric.Gauge
+	hopsAvg *metric.GaugeFloat64
+
+	antiEntropyDiscrepancies *metric.Counter
+
 	// The system config is treated unlike other info objects.
 	// It is used so often that we keep an unmarshaled version of it
 	// here and its own set of callbacks.
@@ -303,23 +354,28 @@ func New(
 ) *Gossip {
 	ambient.SetEventLog("gossip", "gossip")
 	g := &Gossip{
-		server:            newServer(ambient, clusterID, nodeID, stopper, registry),
-		Connected:         make(chan struct{}),
-		rpcContext:        rpcContext,
-		outgoing:          makeNodeSet(minPeers, metric.NewGauge(MetaConnectionsOutgoingGauge)),
-		bootstrapping:     map[string]struct{}{},
-		disconnected:      make(chan *client, 10),
-		stalledCh:         make(chan struct{}, 1),
-		stallInterval:     defaultStallInterval,
-		bootstrapInterval: defaultBootstrapInterval,
-		cullInterval:      defaultCullInterval,
-		resolversTried:    map[int]struct{}{},
-		nodeDescs:         map[roachpb.NodeID]*roachpb.NodeDescriptor{},
-		storeMap:          make(map[roachpb.StoreID]roachpb.NodeID),
-		resolverAddrs:     map[util.UnresolvedAddr]resolver.Resolver{},
-		bootstrapAddrs:    map[util.UnresolvedAddr]roachpb.NodeID{},
-		localityTierMap:   map[string]struct{}{},
-		defaultZoneConfig: defaultZoneConfig,
+		server:                   newServer(ambient, clusterID, nodeID, stopper, registry),
+		Connected:                make(chan struct{}),
+		rpcContext:               rpcContext,
+		outgoing:                 makeNodeSet(minPeers, metric.NewGauge(MetaConnectionsOutgoingGauge)),
+		bootstrapping:            map[string]struct{}{},
+		bootstrapFailures:        map[string]*bootstrapFailure{},
+		disconnected:             make(chan *client, 10),
+		stalledCh:                make(chan struct{}, 1),
+		stallInterval:            defaultStallInterval,
+		bootstrapInterval:        defaultBootstrapInterval,
+		cullInterval:             defaultCullInterval,
+		fanout:                   defaultFanoutConfig(),
+		hopsMax:                  metric.NewGauge(MetaHopsMax),
+		hopsAvg:                  metric.NewGaugeFloat64(MetaHopsAvg),
+		antiEntropyDiscrepancies: metric.NewCounter(MetaAntiEntropyDiscrepancies),
+		resolversTried:           map[int]struct{}{},
+		nodeDescs:                map[roachpb.NodeID]*roachpb.NodeDescriptor{},
+		storeMap:                 make(map[roachpb.StoreID]roachpb.NodeID),
+		resolverAddrs:            map[util.UnresolvedAddr]resolver.Resolver{},
+		bootstrapAddrs:           map[util.UnresolvedAddr]roachpb.NodeID{},
+		localityTierMap:          map[string]struct{}{},
+		defaultZoneConfig:        defaultZoneConfig,
 	}
 
 	for _, loc := range locality.Tiers {
@@ -328,14 +384,23 @@ func New(
 	stopper.AddCloser(stop.CloserFn(g.server.AmbientContext.FinishEventLog))
 
 	registry.AddMetric(g.outgoing.gauge)
+	registry.AddMetric(g.hopsMax)
+	registry.AddMetric(g.hopsAvg)
+	registry.AddMetric(g.antiEntropyDiscrepancies)
 	g.clientsMu.breakers = map[string]*circuit.Breaker{}
 
 	g.mu.Lock()
+	if rpcContext != nil {
+		g.mu.is.clock = rpcContext.LocalClock
+	}
 	// Add ourselves as a SystemConfig watcher.
 	g.mu.is.registerCallback(KeySystemConfig, g.updateSystemConfig)
 	// Add ourselves as a node descriptor watcher.
 	g.mu.is.registerCallback(MakePrefixPattern(KeyNodeIDPrefix), g.updateNodeAddress)
 	g.mu.is.registerCallback(MakePrefixPattern(KeyStorePrefix), g.updateStoreMap)
+	for _, pattern := range defaultPriorityPatterns {
+		g.mu.is.addPriorityPattern(pattern)
+	}
 	// Log gossip connectivity whenever we receive an update.
 	g.mu.Unlock()
 
@@ -405,6 +470,9 @@ func (g *Gossip) SetNodeDescriptor(desc *roachpb.NodeDescriptor) error {
 	if desc.Address.IsEmpty() {
 		log.Fatalf(ctx, "n%d address is empty", desc.NodeID)
 	}
+	if err := g.maybeAddNodeCert(desc.NodeID); err != nil {
+		return errors.Errorf("n%d: couldn't gossip certificate: %v", desc.NodeID, err)
+	}
 	if err := g.AddInfoProto(MakeNodeIDKey(desc.NodeID), desc, NodeDescriptorTTL); err != nil {
 		return errors.Errorf("n%d: couldn't gossip descriptor: %v", desc.NodeID, err)
 	}
@@ -412,6 +480,26 @@ func (g *Gossip) SetNodeDescriptor(desc *roachpb.NodeDescriptor) error {
 	return nil
 }
 
+// maybeAddNodeCert gossips the certificate whose key signs the infos
+// originated by the local node, if info signing is enabled and it isn't
+// gossiped yet. It never expires, and is replaced when the node restarts
+// with a different certificate.
+func (g *Gossip) maybeAddNodeCert(nodeID roachpb.NodeID) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.mu.is.signer == nil {
+		return nil
+	}
+	key := MakeNodeCertKey(nodeID)
+	cert := g.mu.is.signer.Certificate()
+	if i := g.mu.is.getInfo(key); i != nil && i.NodeID == nodeID {
+		if existing, err := i.Value.GetBytes(); err == nil && bytes.Equal(existing, cert) {
+			return nil
+		}
+	}
+	return g.addInfoLocked(key, cert, 0 /* ttl */)
+}
+
 // SetStallInterval sets the interval between successive checks
 // to determine whether this host is not connected to the gossip
 // network, or else is connected to a partition which doesn't
@@ -440,6 +528,37 @@ func (g *Gossip) SetCullInterval(interval time.Duration) {
 	g.cullInterval = interval
 }
 
+// SetFanoutConfig sets the parameters which control how many peers
+// this node connects to and how far apart infos may travel before the
+// network is tightened. The limits on incoming and outgoing
+// connections are recomputed immediately.
+func (g *Gossip) SetFanoutConfig(cfg FanoutConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.fanout = cfg
+	g.recomputeMaxPeersLocked()
+	return nil
+}
+
+// GetFanoutConfig returns the fanout parameters currently in use.
+func (g *Gossip) GetFanoutConfig() FanoutConfig {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.fanout
+}
+
+// SetAntiEntropyInterval sets the interval at which the full infostore
+// is sent to each incoming connection. The new interval applies to
+// connections established after the call.
+func (g *Gossip) SetAntiEntropyInterval(interval time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.antiEntropyInterval = interval
+}
+
 // SetStorage provides an instance of the Storage interface
 // for reading and writing gossip bootstrap data from persistent
 // storage. This should be invoked as early in the lifecycle of a
@@ -469,6 +588,7 @@ func (g *Gossip) SetStorage(storage Storage) error {
 			g.maybeAddBootstrapAddressLocked(addr, unknownNodeID)
 		}
 	}
+	g.mergeRecentPeersLocked(storedBI.RecentPeers)
 	// Persist merged addresses.
 	if numAddrs := len(g.bootstrapInfo.Addresses); numAddrs > len(storedBI.Addresses) {
 		if err := g.storage.WriteBootstrapInfo(&g.bootstrapInfo); err != nil {
@@ -491,6 +611,12 @@ func (g *Gossip) SetStorage(storage Storage) error {
 		}
 	}
 
+	// Try the peers this node was most recently connected to first, as
+	// they are the most likely to still be reachable.
+	if g.preferRecentPeersLocked(timeutil.Now()) {
+		newResolverFound = true
+	}
+
 	// If a new resolver was found, immediately signal bootstrap.
 	if newResolverFound {
 		if log.V(1) {
@@ -501,8 +627,26 @@ func (g *Gossip) SetStorage(storage Storage) error {
 	return nil
 }
 
+// SetInfoSigning enables signing of infos originated by this node and
+// verification of infos received from other nodes, so that a misbehaving
+// node cannot forge cluster metadata on behalf of another. Either argument
+// may be nil to disable the respective half. The certificate of the signer
+// is gossiped along with the node descriptor, and the infos of other nodes
+// are verified against the certificates they gossiped. Infos which fail
+// verification are dropped. This should be invoked before the gossip
+// instance is started.
+func (g *Gossip) SetInfoSigning(signer InfoSigner, verifier InfoVerifier) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mu.is.signer = signer
+	g.mu.is.verifier = verifier
+}
+
 // setResolvers initializes the set of gossip resolvers used to find
-// nodes to bootstrap the gossip network.
+// nodes to bootstrap the gossip network. Resolvers which were added
+// from persisted bootstrap info (see SetStorage) are retained after
+// the supplied resolvers so that a node can rejoin the cluster even if
+// none of the configured seeds are reachable.
 func (g *Gossip) setResolvers(resolvers []resolver.Resolver) {
 	if resolvers == nil {
 		return
@@ -511,10 +655,25 @@ func (g *Gossip) setResolvers(resolvers []resolver.Resolver) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	seen := make(map[string]struct{}, len(resolvers))
+	merged := make([]resolver.Resolver, 0, len(resolvers)+len(g.resolvers))
+	for _, r := range resolvers {
+		seen[r.Addr()] = struct{}{}
+		merged = append(merged, r)
+	}
+	for _, r := range g.resolvers {
+		if _, ok := seen[r.Addr()]; ok {
+			continue
+		}
+		seen[r.Addr()] = struct{}{}
+		merged = append(merged, r)
+	}
+
 	// Start index at end because get next address loop logic increments as first step.
-	g.resolverIdx = len(resolvers) - 1
-	g.resolvers = resolvers
+	g.resolverIdx = len(merged) - 1
+	g.resolvers = merged
 	g.resolversTried = map[int]struct{}{}
+	g.preferRecentPeersLocked(timeutil.Now())
 
 	// Start new bootstrapping immediately instead of waiting for next bootstrap interval.
 	g.maybeSignalStatusChangeLocked()
@@ -555,6 +714,16 @@ func (g *Gossip) GetNodeDescriptor(nodeID roachpb.NodeID) (*roachpb.NodeDescript
 	return g.getNodeDescriptorLocked(nodeID)
 }
 
+// GetStoreDescriptor looks up the most recently gossiped descriptor of the
+// store by ID, including its capacity and attributes.
+func (g *Gossip) GetStoreDescriptor(storeID roachpb.StoreID) (*roachpb.StoreDescriptor, error) {
+	desc := &roachpb.StoreDescriptor{}
+	if err := g.GetInfoProto(MakeStoreKey(storeID), desc); err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
+
 // LogStatus logs the current status of gossip such as the incoming and
 // outgoing connections.
 func (g *Gossip) LogStatus() {
@@ -764,16 +933,76 @@ func (g *Gossip) maybeCleanupBootstrapAddressesLocked() {
 	}
 }
 
+// FanoutConfig holds the parameters which determine the shape of the
+// gossip network. The zero value of a field is not valid; start from
+// GetFanoutConfig() when overriding individual parameters.
+type FanoutConfig struct {
+	// MaxHops is the maximum number of hops which any gossip info
+	// should require to transit between any two nodes. Infos arriving
+	// from further away cause the network to be tightened. Must be
+	// greater than 2.
+	MaxHops int
+	// MinPeers is the lower bound on the number of incoming and
+	// outgoing connections, regardless of cluster size.
+	MinPeers int
+	// MaxPeers, if non-zero, fixes the maximum number of incoming and
+	// outgoing connections instead of deriving it from the number of
+	// nodes in the cluster and MaxHops.
+	MaxPeers int
+	// MaxIncoming, if non-zero, caps the number of incoming connections
+	// below the peer limit above. Clients refused by a full node are
+	// redirected to one of its less-loaded peers, which keeps seed nodes
+	// in large clusters from becoming hubs for all gossip traffic.
+	MaxIncoming int
+}
+
+// defaultFanoutConfig returns the default fanout parameters, which may
+// be overridden via environment variables.
+func defaultFanoutConfig() FanoutConfig {
+	cfg := FanoutConfig{
+		MaxHops:     envutil.EnvOrDefaultInt("COCKROACH_GOSSIP_MAX_HOPS", maxHops),
+		MinPeers:    envutil.EnvOrDefaultInt("COCKROACH_GOSSIP_MIN_PEERS", minPeers),
+		MaxPeers:    envutil.EnvOrDefaultInt("COCKROACH_GOSSIP_MAX_PEERS", 0),
+		MaxIncoming: envutil.EnvOrDefaultInt("COCKROACH_GOSSIP_MAX_INCOMING", 0),
+	}
+	if err := cfg.validate(); err != nil {
+		log.Warningf(context.Background(), "ignoring gossip fanout configuration: %s", err)
+		return FanoutConfig{MaxHops: maxHops, MinPeers: minPeers}
+	}
+	return cfg
+}
+
+func (cfg FanoutConfig) validate() error {
+	if cfg.MaxHops <= 2 {
+		return errors.Errorf("max hops must be greater than 2; got %d", cfg.MaxHops)
+	}
+	if cfg.MinPeers < 1 {
+		return errors.Errorf("min peers must be at least 1; got %d", cfg.MinPeers)
+	}
+	if cfg.MaxPeers != 0 && cfg.MaxPeers < cfg.MinPeers {
+		return errors.Errorf("max peers (%d) must not be less than min peers (%d)",
+			cfg.MaxPeers, cfg.MinPeers)
+	}
+	if cfg.MaxIncoming < 0 {
+		return errors.Errorf("max incoming must not be negative; got %d", cfg.MaxIncoming)
+	}
+	return nil
+}
+
 // maxPeers returns the maximum number of peers each gossip node
-// may connect to. This is based on maxHops, which is a preset
-// maximum for number of hops allowed before the gossip network
-// will seek to "tighten" by creating new connections to distant
-// nodes.
-func maxPeers(nodeCount int) int {
+// may connect to. Unless fixed by MaxPeers, this is based on MaxHops,
+// which is a preset maximum for number of hops allowed before the
+// gossip network will seek to "tighten" by creating new connections
+// to distant nodes.
+func (cfg FanoutConfig) maxPeers(nodeCount int) int {
+	if cfg.MaxPeers != 0 {
+		return cfg.MaxPeers
+	}
 	// This formula uses maxHops-2, instead of maxHops, to provide a
 	// "fudge" factor for max connected peers, to account for the
 	// arbitrary, decentralized way in which gossip networks are created.
-	// This will return the following maxPeers for the given number of nodes:
+	// With the default settings, this will return the following maxPeers
+	// for the given number of nodes:
 	//	 <= 27 nodes -> 3 peers
 	//   <= 64 nodes -> 4 peers
 	//   <= 125 nodes -> 5 peers
@@ -785,9 +1014,9 @@ func maxPeers(nodeCount int) int {
 	// log(maxPeers) > log(nodeCount) / maxHops
 	// maxPeers > e^(log(nodeCount) / maxHops)
 	// hence maxPeers = ceil(e^(log(nodeCount) / maxHops)) should work
-	maxPeers := int(math.Ceil(math.Exp(math.Log(float64(nodeCount)) / float64(maxHops-2))))
-	if maxPeers < minPeers {
-		return minPeers
+	maxPeers := int(math.Ceil(math.Exp(math.Log(float64(nodeCount)) / float64(cfg.MaxHops-2))))
+	if maxPeers < cfg.MinPeers {
+		return cfg.MinPeers
 	}
 	return maxPeers
 }
@@ -899,20 +1128,34 @@ func (g *Gossip) updateClients() {
 	var buf bytes.Buffer
 	var sep string
 
-	g.mu.RLock()
+	g.mu.Lock()
 	g.clientsMu.Lock()
+	var peers []util.UnresolvedAddr
 	for _, c := range g.clientsMu.clients {
 		if c.peerID != 0 {
 			fmt.Fprintf(&buf, "%s%d", sep, c.peerID)
 			sep = ","
+			peers = append(peers, util.MakeUnresolvedAddr(c.addr.Network(), c.addr.String()))
 		}
 	}
 	g.clientsMu.Unlock()
-	g.mu.RUnlock()
+	g.recordRecentPeersLocked(timeutil.Now(), peers)
+	g.mu.Unlock()
 
 	if err := g.AddInfo(MakeGossipClientsKey(nodeID), buf.Bytes(), 2*defaultClientsInterval); err != nil {
 		log.Error(g.AnnotateCtx(context.Background()), err)
 	}
+	g.updateHopMetrics()
+}
+
+// updateHopMetrics recomputes the gauges tracking the number of hops
+// required for other nodes' infos to reach this node.
+func (g *Gossip) updateHopMetrics() {
+	g.mu.Lock()
+	avgHops, maxHops := g.mu.is.hopStats()
+	g.mu.Unlock()
+	g.hopsAvg.Update(avgHops)
+	g.hopsMax.Update(int64(maxHops))
 }
 
 // recomputeMaxPeersLocked recomputes max peers based on size of
@@ -925,8 +1168,12 @@ func (g *Gossip) updateClients() {
 // I'm not making this change now since it tends to lead to less balanced
 // networks and I'm not sure what all the consequences of that might be.
 func (g *Gossip) recomputeMaxPeersLocked() {
-	maxPeers := maxPeers(len(g.nodeDescs))
-	g.mu.incoming.setMaxSize(maxPeers)
+	maxPeers := g.fanout.maxPeers(len(g.nodeDescs))
+	maxIncoming := maxPeers
+	if g.fanout.MaxIncoming != 0 && g.fanout.MaxIncoming < maxIncoming {
+		maxIncoming = g.fanout.MaxIncoming
+	}
+	g.mu.incoming.setMaxSize(maxIncoming)
 	g.outgoing.setMaxSize(maxPeers)
 }
 
@@ -1071,6 +1318,19 @@ func (g *Gossip) GetInfoProto(key string, msg protoutil.Message) error {
 	return protoutil.Unmarshal(bytes, msg)
 }
 
+// GetInfoTimestamp returns the HLC timestamp at which the latest info for
+// the provided key was originated, or KeyNotPresentError if the key does
+// not exist or has expired. Consumers can use it to gauge how stale an
+// info is.
+func (g *Gossip) GetInfoTimestamp(key string) (hlc.Timestamp, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if i := g.mu.is.getInfo(key); i != nil {
+		return i.Value.Timestamp, nil
+	}
+	return hlc.Timestamp{}, NewKeyNotPresentError(key)
+}
+
 // InfoOriginatedHere returns true iff the latest info for the provided key
 // originated on this node. This is useful for ensuring that the system config
 // is regossiped as soon as possible when its lease changes hands.
@@ -1159,6 +1419,27 @@ func (g *Gossip) RegisterCallback(pattern string, method Callback, opts ...Callb
 	}
 }
 
+// ExpirationCallback is a callback method to be invoked when the info
+// denoted by key lapses because its TTL expired. The callback is passed the
+// last value gossiped for the key.
+type ExpirationCallback func(string, roachpb.Value)
+
+// RegisterExpirationCallback registers a callback for a key pattern to be
+// invoked whenever an info for a gossip key matching pattern expires
+// without having been refreshed. This allows consumers to distinguish a
+// node which stopped gossiping a key from one which gossiped a zero value.
+// Returns a function to unregister the callback.
+func (g *Gossip) RegisterExpirationCallback(pattern string, method ExpirationCallback) func() {
+	g.mu.Lock()
+	unregister := g.mu.is.registerExpirationCallback(pattern, method)
+	g.mu.Unlock()
+	return func() {
+		g.mu.Lock()
+		unregister()
+		g.mu.Unlock()
+	}
+}
+
 // GetSystemConfig returns the local unmarshaled version of the system config.
 // Returns nil if the system config hasn't been set yet.
 func (g *Gossip) GetSystemConfig() *config.SystemConfig {
@@ -1309,6 +1590,9 @@ func (g *Gossip) getNextBootstrapAddressLocked() net.Addr {
 			continue
 		} else {
 			addrStr := addr.String()
+			if g.isBootstrapBlacklistedLocked(addrStr) {
+				continue
+			}
 			if _, addrActive := g.bootstrapping[addrStr]; !addrActive {
 				g.bootstrapping[addrStr] = struct{}{}
 				return addr
@@ -1319,6 +1603,62 @@ func (g *Gossip) getNextBootstrapAddressLocked() net.Addr {
 	return nil
 }
 
+// refreshResolvers refreshes the addresses of the resolvers which enumerate
+// them from an external source. This may block on network calls, so it is
+// done without holding the gossip mutex, and getNextBootstrapAddressLocked
+// uses the results.
+func (g *Gossip) refreshResolvers(ctx context.Context) {
+	for _, r := range g.GetResolvers() {
+		if rf, ok := r.(resolver.Refresher); ok {
+			if err := rf.Refresh(ctx); err != nil {
+				log.Warningf(ctx, "%v", err)
+			}
+		}
+	}
+}
+
+// bootstrapFailure records consecutive failures to connect to a
+// bootstrap address.
+type bootstrapFailure struct {
+	count int
+	until time.Time // the address is skipped until this time
+}
+
+// isBootstrapBlacklistedLocked returns whether the bootstrap address has
+// failed repeatedly and should be skipped for the time being.
+func (g *Gossip) isBootstrapBlacklistedLocked(addr string) bool {
+	f, ok := g.bootstrapFailures[addr]
+	return ok && timeutil.Now().Before(f.until)
+}
+
+// recordBootstrapResultLocked updates the failure history of the address of
+// a client which disconnected. A client which never heard from its peer
+// counts as a failure; any other client clears the address' history.
+func (g *Gossip) recordBootstrapResultLocked(c *client) {
+	addr := c.addr.String()
+	if c.peerID != 0 {
+		delete(g.bootstrapFailures, addr)
+		return
+	}
+	f, ok := g.bootstrapFailures[addr]
+	if !ok {
+		f = &bootstrapFailure{}
+		g.bootstrapFailures[addr] = f
+	}
+	f.count++
+	if f.count >= bootstrapBlacklistThreshold {
+		backoff := bootstrapBlacklistBase << uint(f.count-bootstrapBlacklistThreshold)
+		if backoff > bootstrapBlacklistMax || backoff <= 0 {
+			backoff = bootstrapBlacklistMax
+		}
+		f.until = timeutil.Now().Add(backoff)
+		if f.count == bootstrapBlacklistThreshold {
+			log.Warningf(g.AnnotateCtx(context.TODO()),
+				"skipping bootstrap address %s for %s after %d failed attempts", addr, backoff, f.count)
+		}
+	}
+}
+
 // bootstrap connects the node to the gossip network. Bootstrapping
 // commences in the event there are no connected clients or the
 // sentinel gossip info is not available. After a successful bootstrap
@@ -1333,6 +1673,7 @@ func (g *Gossip) bootstrap() {
 		defer bootstrapTimer.Stop()
 		for {
 			if g.server.stopper.RunTask(ctx, "gossip.Gossip: bootstrap ", func(ctx context.Context) {
+				g.refreshResolvers(ctx)
 				g.mu.Lock()
 				defer g.mu.Unlock()
 				haveClients := g.outgoing.len() > 0
@@ -1432,9 +1773,12 @@ func (g *Gossip) manage() {
 							log.Eventf(ctx, "culling %s", c.addr)
 							c.close()
 
-							// After releasing the lock, block until the client disconnects.
+							// After releasing the lock, block until the client disconnects
+							// and then try to replace it with a connection closer to the
+							// originators of distant infos.
 							defer func() {
 								g.doDisconnected(<-g.disconnected)
+								g.tightenNetwork(ctx)
 							}()
 						} else {
 							if log.V(1) {
@@ -1451,6 +1795,10 @@ func (g *Gossip) manage() {
 				stallTimer.Reset(jitteredInterval(g.stallInterval))
 
 				g.mu.Lock()
+				// Expired infos are otherwise only removed lazily as the
+				// infostore is visited; sweep them here so that expiration
+				// callbacks fire in a timely manner on quiet networks.
+				g.mu.is.deleteExpired()
 				g.maybeSignalStatusChangeLocked()
 				g.mu.Unlock()
 			}
@@ -1474,14 +1822,14 @@ func (g *Gossip) tightenNetwork(ctx context.Context) {
 	if g.outgoing.hasSpace() {
 		distantNodeID, distantHops := g.mu.is.mostDistant(g.hasOutgoingLocked)
 		log.VEventf(ctx, 2, "distantHops: %d from %d", distantHops, distantNodeID)
-		if distantHops <= maxHops {
+		if distantHops <= uint32(g.fanout.MaxHops) {
 			return
 		}
 		if nodeAddr, err := g.getNodeIDAddressLocked(distantNodeID); err != nil {
 			log.Errorf(ctx, "unable to get address for n%d: %s", distantNodeID, err)
 		} else {
 			log.Infof(ctx, "starting client to n%d (%d > %d) to tighten network graph",
-				distantNodeID, distantHops, maxHops)
+				distantNodeID, distantHops, g.fanout.MaxHops)
 			log.Eventf(ctx, "tightening network with new client to %s", nodeAddr)
 			g.startClientLocked(nodeAddr)
 		}
@@ -1493,6 +1841,7 @@ func (g *Gossip) doDisconnected(c *client) {
 
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	g.recordBootstrapResultLocked(c)
 	g.removeClientLocked(c)
 
 	// If the client was disconnected with a forwarding address, connect now.
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/gossip/resolver"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -33,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/gogo/protobuf/proto"
@@ -59,6 +61,53 @@ func TestGossipInfoStore(t *testing.T) {
 	}
 }
 
+// TestGossipSystemConfig verifies that the system config is unmarshaled from
+// gossip and that registered channels are notified of updates.
+func TestGossipSystemConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	rpcContext := rpc.NewInsecureTestingContext(clock, stopper)
+	g := NewTest(1, rpcContext, rpc.NewServer(rpcContext), stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	ch := g.RegisterSystemConfigChannel()
+	select {
+	case <-ch:
+		t.Fatal("unexpected notification before the system config was gossiped")
+	default:
+	}
+	if cfg := g.GetSystemConfig(); cfg != nil {
+		t.Fatalf("expected no system config, got %+v", cfg)
+	}
+
+	entries := config.SystemConfigEntries{
+		Values: []roachpb.KeyValue{
+			{Key: roachpb.Key("a"), Value: roachpb.MakeValueFromString("b")},
+		},
+	}
+	if err := g.AddInfoProto(KeySystemConfig, &entries, 0 /* ttl */); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ch:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for system config notification")
+	}
+	cfg := g.GetSystemConfig()
+	if cfg == nil || !reflect.DeepEqual(cfg.Values, entries.Values) {
+		t.Fatalf("expected system config values %+v, got %+v", entries.Values, cfg)
+	}
+
+	// Channels registered after the system config is available are notified
+	// right away.
+	select {
+	case <-g.RegisterSystemConfigChannel():
+	default:
+		t.Fatal("expected immediate notification for an existing system config")
+	}
+}
+
 // TestGossipMoveNode verifies that if a node is moved to a new address, it
 // gets properly updated in gossip.
 func TestGossipMoveNode(t *testing.T) {
@@ -105,6 +154,38 @@ func TestGossipMoveNode(t *testing.T) {
 	})
 }
 
+// TestGossipStoreDescriptor verifies that gossiped store descriptors can be
+// looked up by store ID.
+func TestGossipStoreDescriptor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	if _, err := g.GetStoreDescriptor(1); !testutils.IsError(err, "does not exist") {
+		t.Fatalf("expected key not present error; got %v", err)
+	}
+
+	desc := &roachpb.StoreDescriptor{
+		StoreID: 1,
+		Attrs:   roachpb.Attributes{Attrs: []string{"ssd"}},
+		Node: roachpb.NodeDescriptor{
+			NodeID:   1,
+			Address:  util.MakeUnresolvedAddr("tcp", "1.1.1.1:1"),
+			Locality: roachpb.Locality{Tiers: []roachpb.Tier{{Key: "zone", Value: "a"}}},
+		},
+		Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 50},
+	}
+	if err := g.AddInfoProto(MakeStoreKey(desc.StoreID), desc, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := g.GetStoreDescriptor(desc.StoreID); err != nil {
+		t.Fatal(err)
+	} else if !proto.Equal(desc, val) {
+		t.Fatalf("expected store %+v, got %+v", desc, val)
+	}
+}
+
 func TestGossipGetNextBootstrapAddress(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	stopper := stop.NewStopper()
@@ -150,6 +231,175 @@ func TestGossipGetNextBootstrapAddress(t *testing.T) {
 	}
 }
 
+// TestGossipBootstrapBlacklist verifies that bootstrap addresses which
+// repeatedly fail are skipped until a connection to them succeeds.
+func TestGossipBootstrapBlacklist(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	var resolvers []resolver.Resolver
+	for _, rs := range []string{"127.0.0.1:9000", "127.0.0.1:9001"} {
+		r, err := resolver.NewResolver(rs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolvers = append(resolvers, r)
+	}
+	g := NewTest(0, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+	g.setResolvers(resolvers)
+
+	failing := util.NewUnresolvedAddr("tcp", "127.0.0.1:9000")
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := 0; i < bootstrapBlacklistThreshold; i++ {
+		c := newClient(log.AmbientContext{Tracer: tracing.NewTracer()}, failing, makeMetrics())
+		g.recordBootstrapResultLocked(c)
+	}
+
+	if addr := g.getNextBootstrapAddressLocked(); addr == nil || addr.String() != "127.0.0.1:9001" {
+		t.Fatalf("expected bootstrap address 127.0.0.1:9001; got %v", addr)
+	}
+	if addr := g.getNextBootstrapAddressLocked(); addr != nil {
+		t.Fatalf("expected blacklisted address to be skipped; got %v", addr)
+	}
+
+	c := newClient(log.AmbientContext{Tracer: tracing.NewTracer()}, failing, makeMetrics())
+	c.peerID = 2
+	g.recordBootstrapResultLocked(c)
+	if addr := g.getNextBootstrapAddressLocked(); addr == nil || addr.String() != failing.String() {
+		t.Fatalf("expected bootstrap address %s; got %v", failing, addr)
+	}
+}
+
+type testBootstrapStorage struct {
+	info   BootstrapInfo
+	writes int
+}
+
+func (s *testBootstrapStorage) ReadBootstrapInfo(info *BootstrapInfo) error {
+	*info = s.info
+	return nil
+}
+
+func (s *testBootstrapStorage) WriteBootstrapInfo(info *BootstrapInfo) error {
+	s.info = *info
+	s.writes++
+	return nil
+}
+
+// TestGossipSetResolversRetainsStored verifies that addresses loaded from
+// persistent storage are not discarded when the configured seed resolvers
+// are set afterwards.
+func TestGossipSetResolversRetainsStored(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	g := NewTest(0, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+	storage := &testBootstrapStorage{
+		info: BootstrapInfo{
+			Addresses: []util.UnresolvedAddr{
+				util.MakeUnresolvedAddr("tcp", "127.0.0.1:9000"),
+				util.MakeUnresolvedAddr("tcp", "127.0.0.1:9002"),
+			},
+		},
+	}
+	if err := g.SetStorage(storage); err != nil {
+		t.Fatal(err)
+	}
+
+	seed, err := resolver.NewResolver("127.0.0.1:9000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.setResolvers([]resolver.Resolver{seed})
+
+	var addrs []string
+	for _, r := range g.GetResolvers() {
+		addrs = append(addrs, r.Addr())
+	}
+	if exp := []string{"127.0.0.1:9000", "127.0.0.1:9002"}; !reflect.DeepEqual(exp, addrs) {
+		t.Errorf("expected resolvers %v; got %v", exp, addrs)
+	}
+}
+
+// TestGossipPrefersRecentPeers verifies that peers to which the node was
+// recently connected are tried before the seeds and other stored
+// addresses, and that new peers are persisted.
+func TestGossipPrefersRecentPeers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	now := timeutil.Now()
+	g := NewTest(0, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+	storage := &testBootstrapStorage{
+		info: BootstrapInfo{
+			Addresses: []util.UnresolvedAddr{
+				util.MakeUnresolvedAddr("tcp", "127.0.0.1:9000"),
+				util.MakeUnresolvedAddr("tcp", "127.0.0.1:9001"),
+				util.MakeUnresolvedAddr("tcp", "127.0.0.1:9002"),
+			},
+			RecentPeers: []RecentPeer{
+				{
+					Address:  util.MakeUnresolvedAddr("tcp", "127.0.0.1:9001"),
+					LastSeen: now.Add(-2 * recentPeerMaxAge).UnixNano(),
+				},
+				{
+					Address:  util.MakeUnresolvedAddr("tcp", "127.0.0.1:9002"),
+					LastSeen: now.Add(-time.Minute).UnixNano(),
+				},
+			},
+		},
+	}
+	if err := g.SetStorage(storage); err != nil {
+		t.Fatal(err)
+	}
+	seed, err := resolver.NewResolver("127.0.0.1:9003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.setResolvers([]resolver.Resolver{seed})
+
+	var addrs []string
+	for _, r := range g.GetResolvers() {
+		addrs = append(addrs, r.Addr())
+	}
+	exp := []string{"127.0.0.1:9002", "127.0.0.1:9003", "127.0.0.1:9000", "127.0.0.1:9001"}
+	if !reflect.DeepEqual(exp, addrs) {
+		t.Errorf("expected resolvers %v; got %v", exp, addrs)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if addr := g.getNextBootstrapAddressLocked(); addr == nil || addr.String() != "127.0.0.1:9002" {
+		t.Errorf("expected to bootstrap from the recent peer 127.0.0.1:9002; got %v", addr)
+	}
+
+	g.recordRecentPeersLocked(now, []util.UnresolvedAddr{util.MakeUnresolvedAddr("tcp", "127.0.0.1:9003")})
+	var recent []string
+	for _, p := range storage.info.RecentPeers {
+		recent = append(recent, p.Address.String())
+	}
+	if exp := []string{"127.0.0.1:9003", "127.0.0.1:9002", "127.0.0.1:9001"}; !reflect.DeepEqual(exp, recent) {
+		t.Errorf("expected persisted recent peers %v; got %v", exp, recent)
+	}
+
+	// With more connected peers than are retained, the recent peers are only
+	// persisted again once their set changes.
+	var conns []util.UnresolvedAddr
+	for i := 0; i < maxRecentPeers+2; i++ {
+		conns = append(conns, util.MakeUnresolvedAddr("tcp", fmt.Sprintf("127.0.0.1:%d", 9010+i)))
+	}
+	g.recordRecentPeersLocked(now, conns)
+	writes := storage.writes
+	g.recordRecentPeersLocked(now.Add(time.Second), conns)
+	if storage.writes != writes {
+		t.Errorf("expected the unchanged recent peers not to be persisted again")
+	}
+}
+
 func TestGossipLocalityResolver(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	stopper := stop.NewStopper()
@@ -273,6 +523,93 @@ func TestGossipRaceLogStatus(t *testing.T) {
 	close(gun)
 }
 
+// TestGossipFanoutConfig verifies that the fanout parameters are validated
+// and that they determine the connection limits.
+func TestGossipFanoutConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, cfg := range []FanoutConfig{
+		{MaxHops: 2, MinPeers: 3},
+		{MaxHops: 5, MinPeers: 0},
+		{MaxHops: 5, MinPeers: 3, MaxPeers: 2},
+		{MaxHops: 5, MinPeers: 3, MaxIncoming: -1},
+	} {
+		if err := cfg.validate(); err == nil {
+			t.Errorf("%+v: expected validation error", cfg)
+		}
+	}
+
+	testCases := []struct {
+		cfg       FanoutConfig
+		nodeCount int
+		expected  int
+	}{
+		{FanoutConfig{MaxHops: 5, MinPeers: 3}, 1, 3},
+		{FanoutConfig{MaxHops: 5, MinPeers: 3}, 64, 4},
+		{FanoutConfig{MaxHops: 5, MinPeers: 3}, 100, 5},
+		{FanoutConfig{MaxHops: 5, MinPeers: 5}, 64, 5},
+		{FanoutConfig{MaxHops: 4, MinPeers: 3}, 64, 8},
+		{FanoutConfig{MaxHops: 5, MinPeers: 3, MaxPeers: 10}, 1, 10},
+	}
+	for _, c := range testCases {
+		if maxPeers := c.cfg.maxPeers(c.nodeCount); maxPeers != c.expected {
+			t.Errorf("%+v: expected maxPeers(%d)=%d; got %d", c.cfg, c.nodeCount, c.expected, maxPeers)
+		}
+	}
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+	if err := g.SetFanoutConfig(FanoutConfig{MaxHops: 5, MinPeers: 3, MaxPeers: 7}); err != nil {
+		t.Fatal(err)
+	}
+	g.mu.RLock()
+	if g.outgoing.maxSize != 7 || g.mu.incoming.maxSize != 7 {
+		t.Errorf("expected connection limits of 7; got outgoing=%d incoming=%d",
+			g.outgoing.maxSize, g.mu.incoming.maxSize)
+	}
+	g.mu.RUnlock()
+
+	if err := g.SetFanoutConfig(FanoutConfig{MaxHops: 5, MinPeers: 3, MaxPeers: 7, MaxIncoming: 2}); err != nil {
+		t.Fatal(err)
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.outgoing.maxSize != 7 || g.mu.incoming.maxSize != 2 {
+		t.Errorf("expected connection limits of 7 outgoing and 2 incoming; got outgoing=%d incoming=%d",
+			g.outgoing.maxSize, g.mu.incoming.maxSize)
+	}
+}
+
+// TestGossipChooseAlternate verifies that clients refused by a full node
+// are forwarded to the least-loaded of its incoming peers.
+func TestGossipChooseAlternate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for nodeID, addr := range map[roachpb.NodeID]string{2: "127.0.0.1:9002", 3: "127.0.0.1:9003"} {
+		g.mu.nodeMap[*util.NewUnresolvedAddr("tcp", addr)] = serverInfo{peerID: nodeID}
+	}
+	// n2 has two incoming connections and n3 only one.
+	for nodeID, clients := range map[roachpb.NodeID]string{4: "2", 5: "2,3"} {
+		i := g.mu.is.newInfo([]byte(clients), time.Hour)
+		if err := g.mu.is.addInfo(MakeGossipClientsKey(nodeID), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		addr, nodeID := g.chooseAlternateLocked(context.TODO())
+		if nodeID != 3 || addr.String() != "127.0.0.1:9003" {
+			t.Fatalf("expected to forward to n3 at 127.0.0.1:9003; got n%d at %s", nodeID, &addr)
+		}
+	}
+}
+
 // TestGossipOutgoingLimitEnforced verifies that a gossip node won't open more
 // outgoing connections than it should. If the gossip implementation is racy
 // with respect to opening outgoing connections, this may not fail every time
@@ -286,7 +623,7 @@ func TestGossipOutgoingLimitEnforced(t *testing.T) {
 	// This test has an implicit dependency on the maxPeers logic deciding that
 	// maxPeers is 3 for a 5-node cluster, so let's go ahead and make that
 	// explicit.
-	maxPeers := maxPeers(5)
+	maxPeers := defaultFanoutConfig().maxPeers(5)
 	if maxPeers > 3 {
 		t.Fatalf("maxPeers(5)=%d, which is higher than this test's assumption", maxPeers)
 	}
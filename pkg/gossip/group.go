@@ -0,0 +1,238 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
+)
+
+// GroupType determines which of the values contributed to a group are
+// retained and propagated.
+type GroupType int
+
+const (
+	// MinGroup retains the infos with the smallest values.
+	MinGroup GroupType = iota
+	// MaxGroup retains the infos with the largest values.
+	MaxGroup
+)
+
+// group is a set of infos sharing a key prefix of which only the limit
+// infos with the smallest (MinGroup) or largest (MaxGroup) values are
+// kept. Each node contributes a value under its own key, and infos which
+// don't make the cut are discarded instead of being propagated, which
+// bounds the amount of gossip regardless of the size of the cluster.
+//
+// Since discarded infos are gone from the infostores of the nodes which
+// discarded them, each node also keeps the values it contributed, its
+// sources, and offers them again whenever the group changes or its infos
+// expire. A value which was evicted by a better one thus comes back once
+// it makes the cut again, e.g. after the better value is updated or
+// expires, and the nodes' views of the group converge on the best values
+// of the sources which are still live.
+type group struct {
+	prefix string
+	limit  int
+	typ    GroupType
+
+	// sources are the values contributed to the group by this node, by key.
+	sources map[string]groupSource
+	// offering is set while the sources are offered, to keep the infos
+	// added in the process from triggering another round.
+	offering bool
+}
+
+// groupSource is a value contributed to a group by this node.
+type groupSource struct {
+	val      int64
+	ttlStamp int64
+}
+
+// better returns whether a is preferable to b for the group.
+func (g *group) better(a, b int64) bool {
+	if g.typ == MinGroup {
+		return a < b
+	}
+	return a > b
+}
+
+func groupValue(i *Info) int64 {
+	// Group infos are only ever added with integer values by
+	// Gossip.AddGroupInfo; treat anything else as the least useful value.
+	v, err := i.Value.GetInt()
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// worst returns the key of the least preferable info in the group and
+// the number of infos in the group, skipping the info with key exclude.
+func (g *group) worst(is *infoStore, exclude string) (key string, count int) {
+	var worstVal int64
+	now := monotonicUnixNano()
+	for k, i := range is.Infos {
+		if k == exclude || !strings.HasPrefix(k, g.prefix) || i.expired(now) {
+			continue
+		}
+		count++
+		if v := groupValue(i); key == "" || g.better(worstVal, v) {
+			key, worstVal = k, v
+		}
+	}
+	return key, count
+}
+
+// registerGroup registers a group of infos sharing a key prefix. Returns
+// an error if the prefix overlaps with that of an existing group.
+func (is *infoStore) registerGroup(prefix string, limit int, typ GroupType) error {
+	if limit <= 0 {
+		return errors.Errorf("group %q: limit must be positive; got %d", prefix, limit)
+	}
+	for _, g := range is.groups {
+		if strings.HasPrefix(g.prefix, prefix) || strings.HasPrefix(prefix, g.prefix) {
+			return errors.Errorf("group %q overlaps existing group %q", prefix, g.prefix)
+		}
+	}
+	is.groups = append(is.groups, &group{
+		prefix:  prefix,
+		limit:   limit,
+		typ:     typ,
+		sources: make(map[string]groupSource),
+	})
+	return nil
+}
+
+// getGroup returns the group to which key belongs, or nil.
+func (is *infoStore) getGroup(key string) *group {
+	for _, g := range is.groups {
+		if strings.HasPrefix(key, g.prefix) {
+			return g
+		}
+	}
+	return nil
+}
+
+// admitToGroup determines whether the info may be added to its group,
+// evicting the least preferable member if the group is full. Returns
+// errNotFresh if the info doesn't make the cut.
+func (is *infoStore) admitToGroup(g *group, key string, i *Info) error {
+	worstKey, count := g.worst(is, key)
+	if count < g.limit {
+		return nil
+	}
+	if !g.better(groupValue(i), groupValue(is.Infos[worstKey])) {
+		return errNotFresh
+	}
+	delete(is.Infos, worstKey)
+	return nil
+}
+
+// addGroupSource records a value contributed to the group by this node and
+// adds it to the group. Returns errNotFresh if the value doesn't make the
+// cut; the source is kept and offered again as the group changes.
+func (is *infoStore) addGroupSource(g *group, key string, val int64, ttl time.Duration) error {
+	i := is.newInfo(nil, ttl)
+	i.Value.SetInt(val)
+	g.sources[key] = groupSource{val: val, ttlStamp: i.TTLStamp}
+	return is.addInfo(key, i)
+}
+
+// offerGroupSources adds the sources of the group which are missing from
+// the infostore and now make the cut, and forgets the expired ones.
+func (is *infoStore) offerGroupSources(g *group) {
+	if g.offering || len(g.sources) == 0 {
+		return
+	}
+	g.offering = true
+	defer func() { g.offering = false }()
+
+	now := monotonicUnixNano()
+	for key, src := range g.sources {
+		if src.ttlStamp <= now {
+			delete(g.sources, key)
+			continue
+		}
+		if is.getInfo(key) != nil {
+			continue
+		}
+		// The info keeps the expiration of the source.
+		i := is.newInfo(nil, 0 /* ttl */)
+		i.TTLStamp = src.ttlStamp
+		i.Value.SetInt(src.val)
+		if err := is.addInfo(key, i); err != nil && err != errNotFresh {
+			log.Warningf(is.AnnotateCtx(context.Background()), "unable to offer group info %q: %v", key, err)
+		}
+	}
+}
+
+// RegisterGroup registers a group of keys sharing the given prefix to
+// which nodes contribute integer values with AddGroupInfo. Only the
+// limit infos with the smallest (MinGroup) or largest (MaxGroup) values
+// are retained and propagated, so that for example a MinGroup with limit
+// one tracks the cluster-wide minimum. Groups must be registered
+// identically on all nodes before any infos are added under the prefix.
+func (g *Gossip) RegisterGroup(prefix string, limit int, typ GroupType) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.mu.is.registerGroup(prefix, limit, typ)
+}
+
+// AddGroupInfo contributes a value to the group registered for the key's
+// prefix. Returns an error if no such group is registered, or if the
+// value is not among those retained by the group, in which case it is
+// added later if it makes the cut before its TTL expires.
+func (g *Gossip) AddGroupInfo(key string, val int64, ttl time.Duration) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	grp := g.mu.is.getGroup(key)
+	if grp == nil {
+		return errors.Errorf("no group registered for key %q", key)
+	}
+	err := g.mu.is.addGroupSource(grp, key, val, ttl)
+	if err == nil {
+		g.signalConnectedLocked()
+	}
+	return err
+}
+
+// GetGroupInfos returns the values currently retained by the group with
+// the given prefix, keyed by info key.
+func (g *Gossip) GetGroupInfos(prefix string) (map[string]int64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	grp := g.mu.is.getGroup(prefix)
+	if grp == nil || grp.prefix != prefix {
+		return nil, errors.Errorf("no group registered with prefix %q", prefix)
+	}
+	infos := make(map[string]int64)
+	if err := g.mu.is.visitInfos(func(key string, i *Info) error {
+		if strings.HasPrefix(key, prefix) {
+			if err := i.Value.Verify([]byte(key)); err != nil {
+				return err
+			}
+			v, err := i.Value.GetInt()
+			if err != nil {
+				return err
+			}
+			infos[key] = v
+		}
+		return nil
+	}, false /* deleteExpired */); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
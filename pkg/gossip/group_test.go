@@ -0,0 +1,134 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+func TestGossipGroups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	if err := g.RegisterGroup("min:", 2, MinGroup); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RegisterGroup("max:", 1, MaxGroup); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RegisterGroup("min:a", 1, MinGroup); !testutils.IsError(err, "overlaps") {
+		t.Errorf("expected overlapping group error; got %v", err)
+	}
+	if err := g.AddGroupInfo("other:a", 1, time.Hour); !testutils.IsError(err, "no group registered") {
+		t.Errorf("expected unregistered group error; got %v", err)
+	}
+
+	testCases := []struct {
+		key         string
+		val         int64
+		expAccepted bool
+		expInfos    map[string]int64
+	}{
+		{"min:a", 5, true, map[string]int64{"min:a": 5}},
+		{"min:b", 3, true, map[string]int64{"min:a": 5, "min:b": 3}},
+		{"min:c", 7, false, map[string]int64{"min:a": 5, "min:b": 3}},
+		{"min:d", 1, true, map[string]int64{"min:b": 3, "min:d": 1}},
+		// Updating a member of a full group doesn't require an eviction, but
+		// lets the evicted min:a back in, which evicts the new min:b.
+		{"min:b", 9, true, map[string]int64{"min:a": 5, "min:d": 1}},
+		{"max:a", 5, true, map[string]int64{"max:a": 5}},
+		{"max:b", 4, false, map[string]int64{"max:a": 5}},
+		{"max:c", 6, true, map[string]int64{"max:c": 6}},
+	}
+	for i, c := range testCases {
+		err := g.AddGroupInfo(c.key, c.val, time.Hour)
+		if c.expAccepted && err != nil {
+			t.Fatalf("%d: unexpected error adding %s=%d: %s", i, c.key, c.val, err)
+		} else if !c.expAccepted && err != errNotFresh {
+			t.Fatalf("%d: expected %s=%d to be rejected; got %v", i, c.key, c.val, err)
+		}
+		infos, err := g.GetGroupInfos(c.key[:4])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(c.expInfos, infos) {
+			t.Errorf("%d: expected group infos %v; got %v", i, c.expInfos, infos)
+		}
+	}
+}
+
+func TestGossipGroupsReadd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	if err := g.RegisterGroup("min:", 1, MinGroup); err != nil {
+		t.Fatal(err)
+	}
+	// addPeerInfo adds an info to the group as if it had been gossiped by
+	// node 2.
+	addPeerInfo := func(key string, val int64, ttl time.Duration) {
+		t.Helper()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		i := g.mu.is.newInfo(nil, ttl)
+		i.NodeID = 2
+		i.Value.SetInt(val)
+		if err := g.mu.is.addInfo(key, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectInfos := func(expected map[string]int64) {
+		t.Helper()
+		infos, err := g.GetGroupInfos("min:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, infos) {
+			t.Fatalf("expected group infos %v; got %v", expected, infos)
+		}
+	}
+
+	if err := g.AddGroupInfo("min:n1", 5, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	expectInfos(map[string]int64{"min:n1": 5})
+
+	// A better value from another node evicts the local one.
+	addPeerInfo("min:n2", 3, time.Hour)
+	expectInfos(map[string]int64{"min:n2": 3})
+
+	// Once the other node's value gets worse, the local one comes back.
+	addPeerInfo("min:n2", 9, time.Hour)
+	expectInfos(map[string]int64{"min:n1": 5})
+
+	// Evicted again, the local value comes back once the better value
+	// expires.
+	addPeerInfo("min:n2", 1, time.Millisecond)
+	expectInfos(map[string]int64{"min:n2": 1})
+	time.Sleep(5 * time.Millisecond)
+	g.mu.Lock()
+	g.mu.is.deleteExpired()
+	g.mu.Unlock()
+	expectInfos(map[string]int64{"min:n1": 5})
+}
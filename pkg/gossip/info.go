@@ -20,5 +20,21 @@ func (i *Info) isFresh(highWaterStamp int64) bool {
 	return i.OrigStamp > highWaterStamp
 }
 
+// supersedes returns whether i should replace existing, an info for the
+// same key. Infos are versioned by the HLC timestamp of their value, so
+// the more recent update always wins. Between copies of the same update,
+// the one which arrived via the fewest hops wins. Conflicting updates
+// from different originators with identical timestamps are resolved in
+// favor of the higher node ID so that all nodes agree on the outcome.
+func (i *Info) supersedes(existing *Info) bool {
+	if ts, existingTS := i.Value.Timestamp, existing.Value.Timestamp; ts != existingTS {
+		return existingTS.Less(ts)
+	}
+	if i.NodeID != existing.NodeID {
+		return i.NodeID > existing.NodeID
+	}
+	return i.Hops < existing.Hops
+}
+
 // infoMap is a map of keys to info object pointers.
 type infoMap map[string]*Info
@@ -63,3 +63,35 @@ func TestIsFresh(t *testing.T) {
 		t.Error("info should be fresh from node0:", i)
 	}
 }
+
+func TestSupersedes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	info := func(wallTime int64, logical int32, nodeID roachpb.NodeID, hops uint32) *Info {
+		return &Info{
+			Value:  roachpb.Value{Timestamp: hlc.Timestamp{WallTime: wallTime, Logical: logical}},
+			NodeID: nodeID,
+			Hops:   hops,
+		}
+	}
+	testCases := []struct {
+		i, existing *Info
+		expected    bool
+	}{
+		// Later timestamps win regardless of originator and hops.
+		{info(2, 0, 1, 5), info(1, 0, 2, 0), true},
+		{info(1, 1, 1, 5), info(1, 0, 2, 0), true},
+		{info(1, 0, 2, 0), info(1, 1, 1, 5), false},
+		// Identical timestamps from different originators resolve by node ID.
+		{info(1, 0, 2, 5), info(1, 0, 1, 0), true},
+		{info(1, 0, 1, 0), info(1, 0, 2, 5), false},
+		// Copies of the same update resolve by hops.
+		{info(1, 0, 1, 1), info(1, 0, 1, 2), true},
+		{info(1, 0, 1, 2), info(1, 0, 1, 2), false},
+	}
+	for i, c := range testCases {
+		if s := c.i.supersedes(c.existing); s != c.expected {
+			t.Errorf("%d: expected %+v supersedes %+v to be %t", i, c.i, c.existing, c.expected)
+		}
+	}
+}
@@ -47,6 +47,13 @@ type callback struct {
 	redundant bool
 }
 
+// expirationCallback holds regexp pattern match and ExpirationCallback
+// method.
+type expirationCallback struct {
+	matcher stringMatcher
+	method  ExpirationCallback
+}
+
 // infoStore objects manage maps of Info objects. They maintain a
 // sequence number generator which they use to allocate new info
 // objects.
@@ -62,11 +69,26 @@ type infoStore struct {
 
 	nodeID  *base.NodeIDContainer
 	stopper *stop.Stopper
+	// clock, if set, provides the HLC timestamps with which infos
+	// originated by this node are versioned.
+	clock *hlc.Clock
 
 	Infos           infoMap                  `json:"infos,omitempty"` // Map from key to info
 	NodeAddr        util.UnresolvedAddr      `json:"-"`               // Address of node owning this info store: "host:port"
 	highWaterStamps map[roachpb.NodeID]int64 // Per-node information for gossip peers
 	callbacks       []*callback
+	expirations     []*expirationCallback
+	groups          []*group
+
+	// priorityPatterns match keys whose updates are signaled directly to
+	// priorityNotifiers rather than through the callback goroutine.
+	priorityPatterns  []*regexp.Regexp
+	priorityNotifiers []chan<- struct{}
+
+	// signer, if set, signs infos originated by this node. verifier, if set,
+	// verifies the signatures of infos received from other nodes.
+	signer   InfoSigner
+	verifier InfoVerifier
 
 	callbackWorkMu syncutil.Mutex // Protects callbackWork
 	callbackWork   []func()
@@ -209,7 +231,16 @@ func (is *infoStore) newInfo(val []byte, ttl time.Duration) *Info {
 	if ttl == 0 {
 		ttlStamp = math.MaxInt64
 	}
-	v := roachpb.MakeValueFromBytesAndTimestamp(val, hlc.Timestamp{WallTime: now})
+	ts := hlc.Timestamp{WallTime: now}
+	if is.clock != nil {
+		// Never version an info below the local wall time, which would
+		// cause updates originated with a lagging clock to lose out to
+		// earlier updates to the same key from other nodes.
+		if clockTS := is.clock.Now(); ts.Less(clockTS) {
+			ts = clockTS
+		}
+	}
+	v := roachpb.MakeValueFromBytesAndTimestamp(val, ts)
 	return &Info{
 		Value:    v,
 		TTLStamp: ttlStamp,
@@ -236,14 +267,14 @@ func (is *infoStore) addInfo(key string, i *Info) error {
 	if i.NodeID == 0 {
 		panic("gossip info's NodeID is 0")
 	}
-	// Only replace an existing info if new timestamp is greater, or if
-	// timestamps are equal, but new hops is smaller.
+	// Only replace an existing info if the new one supersedes it.
 	existingInfo, ok := is.Infos[key]
-	if ok {
-		iNanos := i.Value.Timestamp.WallTime
-		existingNanos := existingInfo.Value.Timestamp.WallTime
-		if iNanos < existingNanos || (iNanos == existingNanos && i.Hops >= existingInfo.Hops) {
-			return errNotFresh
+	if ok && !i.supersedes(existingInfo) {
+		return errNotFresh
+	}
+	if g := is.getGroup(key); g != nil {
+		if err := is.admitToGroup(g, key, i); err != nil {
+			return err
 		}
 	}
 	if i.OrigStamp == 0 {
@@ -254,6 +285,13 @@ func (is *infoStore) addInfo(key string, i *Info) error {
 			log.Fatal(context.Background(),
 				log.Safe(fmt.Sprintf("high water stamp %d >= %d", highWaterStamp, i.OrigStamp)))
 		}
+		if is.signer != nil {
+			sig, err := is.signer.Sign(infoSigningPayload(key, i))
+			if err != nil {
+				return errors.Wrapf(err, "unable to sign info %q", key)
+			}
+			i.Signature = sig
+		}
 	}
 	// Update info map.
 	is.Infos[key] = i
@@ -262,6 +300,11 @@ func (is *infoStore) addInfo(key string, i *Info) error {
 	changed := existingInfo == nil ||
 		!bytes.Equal(existingInfo.Value.RawBytes, i.Value.RawBytes)
 	is.processCallbacks(key, i.Value, changed)
+	is.maybeNotifyPriority(key)
+	if g := is.getGroup(key); g != nil && changed {
+		// The update may have evicted a member or made one worse.
+		is.offerGroupSources(g)
+	}
 	return nil
 }
 
@@ -276,6 +319,25 @@ func (is *infoStore) getHighWaterStamps() map[roachpb.NodeID]int64 {
 	return copy
 }
 
+// getHighWaterStampsWithDiff returns the high water stamps which differ
+// from those recorded in prevStamps, updating prevStamps in the process.
+// This is used to send peers only the stamps which have changed since they
+// were last sent on a connection; peers merge received stamps into their
+// existing view, so the omitted stamps are implied. Does not modify the
+// infoStore.
+func (is *infoStore) getHighWaterStampsWithDiff(
+	prevStamps map[roachpb.NodeID]int64,
+) map[roachpb.NodeID]int64 {
+	diff := make(map[roachpb.NodeID]int64)
+	for k, hws := range is.highWaterStamps {
+		if prevStamps[k] != hws {
+			prevStamps[k] = hws
+			diff[k] = hws
+		}
+	}
+	return diff
+}
+
 // registerCallback registers a callback for a key pattern to be
 // invoked whenever new info for a gossip key matching pattern is
 // received. The callback method is invoked with the info key which
@@ -317,6 +379,54 @@ func (is *infoStore) registerCallback(
 	}
 }
 
+// registerExpirationCallback registers a callback for a key pattern to
+// be invoked whenever an info for a gossip key matching pattern expires
+// and is removed from the infoStore. Returns a function to unregister the
+// callback. Note: the callback may fire after being unregistered.
+func (is *infoStore) registerExpirationCallback(
+	pattern string, method ExpirationCallback,
+) func() {
+	var matcher stringMatcher
+	if pattern == ".*" {
+		matcher = allMatcher{}
+	} else {
+		matcher = regexp.MustCompile(pattern)
+	}
+	cb := &expirationCallback{matcher: matcher, method: method}
+	is.expirations = append(is.expirations, cb)
+
+	return func() {
+		for i, targetCB := range is.expirations {
+			if targetCB == cb {
+				numCBs := len(is.expirations)
+				is.expirations[i] = is.expirations[numCBs-1]
+				is.expirations = is.expirations[:numCBs-1]
+				break
+			}
+		}
+	}
+}
+
+// processExpirations invokes the expiration callbacks matching the
+// specified key, which has just been removed from the infoStore.
+func (is *infoStore) processExpirations(key string, i *Info) {
+	var matches []ExpirationCallback
+	for _, cb := range is.expirations {
+		if cb.matcher.MatchString(key) {
+			matches = append(matches, cb.method)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+	content := i.Value
+	is.enqueueCallbackWork(func() {
+		for _, method := range matches {
+			method(key, content)
+		}
+	})
+}
+
 // processCallbacks processes callbacks for the specified key by
 // matching each callback's regular expression against the key and invoking
 // the corresponding callback method on a match.
@@ -332,11 +442,16 @@ func (is *infoStore) processCallbacks(key string, content roachpb.Value, changed
 
 func (is *infoStore) runCallbacks(key string, content roachpb.Value, callbacks ...Callback) {
 	// Add the callbacks to the callback work list.
-	f := func() {
+	is.enqueueCallbackWork(func() {
 		for _, method := range callbacks {
 			method(key, content)
 		}
-	}
+	})
+}
+
+// enqueueCallbackWork adds f to the work list run by the callback
+// goroutine and signals that goroutine.
+func (is *infoStore) enqueueCallbackWork(f func()) {
 	is.callbackWorkMu.Lock()
 	is.callbackWork = append(is.callbackWork, f)
 	is.callbackWorkMu.Unlock()
@@ -364,6 +479,7 @@ func (is *infoStore) visitInfos(visitInfo func(string, *Info) error, deleteExpir
 			if i.expired(now) {
 				if deleteExpired {
 					delete(is.Infos, k)
+					is.processExpirations(k, i)
 				}
 				continue
 			}
@@ -376,6 +492,20 @@ func (is *infoStore) visitInfos(visitInfo func(string, *Info) error, deleteExpir
 	return nil
 }
 
+// deleteExpired removes all expired infos from the infoStore, invoking
+// any matching expiration callbacks.
+func (is *infoStore) deleteExpired() {
+	if err := is.visitInfos(func(string, *Info) error {
+		return nil
+	}, true /* deleteExpired */); err != nil {
+		panic(err)
+	}
+	// Expired group members make room for the sources they evicted.
+	for _, g := range is.groups {
+		is.offerGroupSources(g)
+	}
+}
+
 // combine combines an incremental delta with the current infoStore.
 // All hop distances on infos are incremented to indicate they've
 // arrived from an external source. Returns the count of "fresh"
@@ -384,17 +514,41 @@ func (is *infoStore) combine(
 	infos map[string]*Info, nodeID roachpb.NodeID,
 ) (freshCount int, err error) {
 	localNodeID := is.nodeID.Get()
-	for key, i := range infos {
+	// The node certificates are combined first, so that the other infos of
+	// the delta can be verified against them.
+	keys := make([]string, 0, len(infos))
+	for key := range infos {
+		if IsNodeCertKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range infos {
+		if !IsNodeCertKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		i := infos[key]
+		// Deltas come off the wire, so malformed infos are rejected rather
+		// than tripping the assertions of addInfo.
+		if i == nil || i.NodeID == 0 || i.OrigStamp == 0 {
+			err = errors.Errorf("rejecting malformed info %q from n%d", key, nodeID)
+			continue
+		}
 		if i.NodeID == localNodeID {
 			ratchetMonotonic(i.OrigStamp)
 		}
 
+		if is.verifier != nil && i.NodeID != localNodeID {
+			if verifyErr := is.verifyInfo(key, i); verifyErr != nil {
+				err = errors.Wrapf(verifyErr, "rejecting info %q", key)
+				continue
+			}
+		}
+
 		infoCopy := *i
 		infoCopy.Hops++
 		infoCopy.PeerID = nodeID
-		if infoCopy.OrigStamp == 0 {
-			panic(errors.Errorf("combining info from n%d with 0 original timestamp", nodeID))
-		}
 		// errNotFresh errors from addInfo are ignored; they indicate that
 		// the data in *is is newer than in *delta.
 		if addErr := is.addInfo(key, &infoCopy); addErr == nil {
@@ -406,6 +560,31 @@ func (is *infoStore) combine(
 	return
 }
 
+// verifyInfo verifies the signature of an info originated by another node
+// against the certificate gossiped by that node. The certificate info of a
+// node is verified against the certificate it carries, which must be
+// gossiped by the node it is for.
+func (is *infoStore) verifyInfo(key string, i *Info) error {
+	var certInfo *Info
+	if IsNodeCertKey(key) {
+		certNodeID, err := NodeIDFromKey(key, KeyNodeCertPrefix)
+		if err != nil {
+			return err
+		}
+		if certNodeID != i.NodeID {
+			return errors.Errorf("certificate of n%d originated by n%d", certNodeID, i.NodeID)
+		}
+		certInfo = i
+	} else if certInfo = is.getInfo(MakeNodeCertKey(i.NodeID)); certInfo == nil {
+		return errors.Errorf("no certificate gossiped by n%d", i.NodeID)
+	}
+	cert, err := certInfo.Value.GetBytes()
+	if err != nil {
+		return err
+	}
+	return is.verifier.Verify(i.NodeID, cert, infoSigningPayload(key, i), i.Signature)
+}
+
 // delta returns a map of infos which have originating timestamps
 // newer than the high water timestamps indicated by the supplied
 // map (which is taken from the perspective of the peer node we're
@@ -427,6 +606,24 @@ func (is *infoStore) delta(highWaterTimestamps map[roachpb.NodeID]int64) map[str
 	return infos
 }
 
+// countDiscrepancies returns the number of infos in the supplied full
+// state of a peer's infostore which are missing locally or are newer than
+// the local copy. Infos originated by this node are skipped, as the local
+// copy is authoritative.
+func (is *infoStore) countDiscrepancies(infos map[string]*Info) int {
+	localNodeID := is.nodeID.Get()
+	var count int
+	for key, i := range infos {
+		if i.NodeID == localNodeID || i.expired(monotonicUnixNano()) {
+			continue
+		}
+		if existing := is.getInfo(key); existing == nil || existing.OrigStamp < i.OrigStamp {
+			count++
+		}
+	}
+	return count
+}
+
 // populateMostDistantMarkers adds the node ID infos to the infos map. The node
 // ID infos are used as markers in the mostDistant calculation and need to be
 // propagated regardless of high water stamps.
@@ -475,35 +672,81 @@ func (is *infoStore) mostDistant(
 	return nodeID, maxHops
 }
 
+// hopStats returns the average and maximum number of hops over the node
+// ID infos originating at other nodes. As in mostDistant, only node ID
+// keys are considered because they are regularly re-gossiped.
+//
+// May modify the infoStore.
+func (is *infoStore) hopStats() (avgHops float64, maxHops uint32) {
+	localNodeID := is.nodeID.Get()
+	var totalHops, count int64
+	if err := is.visitInfos(func(key string, i *Info) error {
+		if i.NodeID != localNodeID && IsNodeIDKey(key) {
+			totalHops += int64(i.Hops)
+			count++
+			if i.Hops > maxHops {
+				maxHops = i.Hops
+			}
+		}
+		return nil
+	}, true /* deleteExpired */); err != nil {
+		panic(err)
+	}
+	if count > 0 {
+		avgHops = float64(totalHops) / float64(count)
+	}
+	return avgHops, maxHops
+}
+
 // leastUseful determines which node ID from amongst the set is
-// currently contributing the least. Returns the node ID. If nodes is
-// empty, returns 0.
+// currently contributing the least. A peer's contribution is the
+// number of distinct originators whose infos were most recently
+// received through it; ties are broken in favor of culling the peer
+// whose infos travelled through the most hops on average, as it is
+// furthest from the originators of the infos it supplies. Returns the
+// node ID. If nodes is empty, returns 0.
 //
 // May modify the infoStore.
 func (is *infoStore) leastUseful(nodes nodeSet) roachpb.NodeID {
-	contrib := make(map[roachpb.NodeID]map[roachpb.NodeID]struct{}, nodes.len())
+	type contribution struct {
+		origins map[roachpb.NodeID]struct{}
+		infos   int
+		sumHops int
+	}
+	contrib := make(map[roachpb.NodeID]*contribution, nodes.len())
 	for node := range nodes.nodes {
-		contrib[node] = map[roachpb.NodeID]struct{}{}
+		contrib[node] = &contribution{origins: map[roachpb.NodeID]struct{}{}}
 	}
 	if err := is.visitInfos(func(key string, i *Info) error {
-		if _, ok := contrib[i.PeerID]; !ok {
-			contrib[i.PeerID] = map[roachpb.NodeID]struct{}{}
+		c, ok := contrib[i.PeerID]
+		if !ok {
+			c = &contribution{origins: map[roachpb.NodeID]struct{}{}}
+			contrib[i.PeerID] = c
 		}
-		contrib[i.PeerID][i.NodeID] = struct{}{}
+		c.origins[i.NodeID] = struct{}{}
+		c.infos++
+		c.sumHops += int(i.Hops)
 		return nil
 	}, true /* deleteExpired */); err != nil {
 		panic(err)
 	}
 
 	least := math.MaxInt32
+	var leastHops float64
 	var leastNode roachpb.NodeID
-	for id, m := range contrib {
-		count := len(m)
-		if nodes.hasNode(id) {
-			if count < least {
-				least = count
-				leastNode = id
-			}
+	for id, c := range contrib {
+		if !nodes.hasNode(id) {
+			continue
+		}
+		count := len(c.origins)
+		var avgHops float64
+		if c.infos > 0 {
+			avgHops = float64(c.sumHops) / float64(c.infos)
+		}
+		if count < least || (count == least && avgHops > leastHops) {
+			least = count
+			leastHops = avgHops
+			leastNode = id
 		}
 	}
 	return leastNode
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -237,6 +238,42 @@ func TestCombineInfosRatchetMonotonic(t *testing.T) {
 	}
 }
 
+// TestCombineMalformedInfos verifies that infos which could only come from
+// a corrupted or malicious delta are rejected without affecting the others.
+func TestCombineMalformedInfos(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	is, stopper := newTestInfoStore()
+	defer stopper.Stop(context.Background())
+
+	valid := func() *Info {
+		return &Info{NodeID: 2, TTLStamp: math.MaxInt64, OrigStamp: monotonicUnixNano()}
+	}
+	noNodeID, noOrigStamp := valid(), valid()
+	noNodeID.NodeID = 0
+	noOrigStamp.OrigStamp = 0
+
+	fresh, err := is.combine(map[string]*Info{
+		"valid":         valid(),
+		"nil":           nil,
+		"no-node-id":    noNodeID,
+		"no-orig-stamp": noOrigStamp,
+	}, 2)
+	if !testutils.IsError(err, "rejecting malformed info") {
+		t.Fatalf("expected malformed info error, got %v", err)
+	}
+	if fresh != 1 {
+		t.Fatalf("expected 1 fresh info, got %d", fresh)
+	}
+	for _, key := range []string{"nil", "no-node-id", "no-orig-stamp"} {
+		if is.getInfo(key) != nil {
+			t.Errorf("expected info %q to be rejected", key)
+		}
+	}
+	if is.getInfo("valid") == nil {
+		t.Errorf("expected valid info to be added")
+	}
+}
+
 // Helper method creates an infostore with 10 infos.
 func createTestInfoStore(t *testing.T) *infoStore {
 	is, stopper := newTestInfoStore()
@@ -306,6 +343,31 @@ func TestInfoStoreDelta(t *testing.T) {
 	}
 }
 
+// TestInfoStoreHighWaterStampsWithDiff verifies that only changed high
+// water stamps are returned on successive calls.
+func TestInfoStoreHighWaterStampsWithDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	is := createTestInfoStore(t)
+
+	sent := map[roachpb.NodeID]int64{}
+	if diff, full := is.getHighWaterStampsWithDiff(sent), is.getHighWaterStamps(); !reflect.DeepEqual(diff, full) {
+		t.Fatalf("expected initial diff %v to equal full stamps %v", diff, full)
+	}
+	if diff := is.getHighWaterStampsWithDiff(sent); len(diff) != 0 {
+		t.Fatalf("expected empty diff, got %v", diff)
+	}
+
+	info := is.newInfo(nil, time.Second)
+	info.NodeID = 2
+	if err := is.addInfo("b.new", info); err != nil {
+		t.Fatal(err)
+	}
+	diff := is.getHighWaterStampsWithDiff(sent)
+	if exp := map[roachpb.NodeID]int64{2: info.OrigStamp}; !reflect.DeepEqual(exp, diff) {
+		t.Errorf("expected diff %v, got %v", exp, diff)
+	}
+}
+
 // TestInfoStoreMostDistant verifies selection of most distant node &
 // associated hops.
 func TestInfoStoreMostDistant(t *testing.T) {
@@ -367,6 +429,37 @@ func TestInfoStoreMostDistant(t *testing.T) {
 	}
 }
 
+// TestInfoStoreHopStats verifies the average and maximum hops computed
+// over other nodes' node ID infos.
+func TestInfoStoreHopStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	is, stopper := newTestInfoStore()
+	defer stopper.Stop(context.TODO())
+
+	if avgHops, maxHops := is.hopStats(); avgHops != 0 || maxHops != 0 {
+		t.Fatalf("expected no hops for empty infostore; got %f, %d", avgHops, maxHops)
+	}
+
+	scInfo := is.newInfo(nil, time.Second)
+	scInfo.Hops = 100
+	scInfo.NodeID = 2
+	if err := is.addInfo(KeySystemConfig, scInfo); err != nil {
+		t.Fatal(err)
+	}
+	for nodeID, hops := range map[roachpb.NodeID]uint32{1: 7, 2: 2, 3: 4} {
+		inf := is.newInfo(nil, time.Second)
+		inf.Hops = hops
+		inf.NodeID = nodeID
+		if err := is.addInfo(MakeNodeIDKey(nodeID), inf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if avgHops, maxHops := is.hopStats(); avgHops != 3 || maxHops != 4 {
+		t.Errorf("expected avg hops 3 and max hops 4; got %f, %d", avgHops, maxHops)
+	}
+}
+
 // TestLeastUseful verifies that the least-contributing peer node
 // can be determined.
 func TestLeastUseful(t *testing.T) {
@@ -424,6 +517,31 @@ func TestLeastUseful(t *testing.T) {
 	}
 }
 
+// TestLeastUsefulTieBreak verifies that when peers contribute infos from
+// the same number of originators, the peer furthest from those originators
+// is considered least useful.
+func TestLeastUsefulTieBreak(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	is, stopper := newTestInfoStore()
+	defer stopper.Stop(context.TODO())
+
+	set := makeNodeSet(3, metric.NewGauge(metric.Metadata{Name: ""}))
+	for i, hops := range []uint32{1, 4, 2} {
+		peerID := roachpb.NodeID(i + 2)
+		set.addNode(peerID)
+		inf := is.newInfo(nil, time.Second)
+		inf.NodeID = peerID + 10
+		inf.PeerID = peerID
+		inf.Hops = hops
+		if err := is.addInfo(fmt.Sprintf("a%d", i), inf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if id := is.leastUseful(set); id != 3 {
+		t.Errorf("expected n3 as least useful; got n%d", id)
+	}
+}
+
 type callbackRecord struct {
 	keys []string
 	wg   *sync.WaitGroup
@@ -577,3 +695,77 @@ func TestRegisterCallback(t *testing.T) {
 		t.Errorf("expected %v, got %v", expKeys, cb.Keys())
 	}
 }
+
+// TestExpirationCallback verifies that expiration callbacks fire for
+// matching infos once they lapse, and not for infos which are still live.
+func TestExpirationCallback(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	is, stopper := newTestInfoStore()
+	defer stopper.Stop(context.TODO())
+	wg := &sync.WaitGroup{}
+	cb := callbackRecord{wg: wg}
+
+	is.registerExpirationCallback("key.*", cb.Add)
+
+	if err := is.addInfo("key1", is.newInfo(nil, time.Nanosecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := is.addInfo("key2", is.newInfo(nil, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := is.addInfo("other", is.newInfo(nil, time.Nanosecond)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Nanosecond)
+
+	wg.Add(1)
+	is.deleteExpired()
+	wg.Wait()
+	if expKeys, actKeys := []string{"key1"}, cb.Keys(); !reflect.DeepEqual(actKeys, expKeys) {
+		t.Errorf("expected %v, got %v", expKeys, actKeys)
+	}
+	if _, ok := is.Infos["other"]; ok {
+		t.Errorf("expected expired info %q to be removed", "other")
+	}
+}
+
+// TestPriorityNotifier verifies that priority notifiers are only signaled
+// for updates to priority keys.
+func TestPriorityNotifier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	is, stopper := newTestInfoStore()
+	defer stopper.Stop(context.TODO())
+	is.addPriorityPattern(KeySentinel)
+
+	ch := make(chan struct{}, 1)
+	unregister := is.registerPriorityNotifier(ch)
+	notified := func() bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+
+	if err := is.addInfo("other", is.newInfo(nil, time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if notified() {
+		t.Error("unexpected notification for non-priority key")
+	}
+	if err := is.addInfo(KeySentinel, is.newInfo(nil, time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if !notified() {
+		t.Error("expected notification for priority key")
+	}
+
+	unregister()
+	if err := is.addInfo(KeySentinel, is.newInfo([]byte("a"), time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if notified() {
+		t.Error("unexpected notification after unregistering")
+	}
+}
@@ -44,6 +44,12 @@ const (
 	// string address of the node. E.g. node:1 => 127.0.0.1:24001
 	KeyNodeIDPrefix = "node"
 
+	// KeyNodeCertPrefix is the key prefix for gossiping the certificates
+	// whose keys sign the infos originated by the nodes, when info signing
+	// is enabled. The suffix is a node ID and the value is the DER-encoded
+	// certificate.
+	KeyNodeCertPrefix = "node-cert"
+
 	// KeyHealthAlertPrefix is the key prefix for gossiping health alerts. The
 	// value is a proto of type HealthCheckResult.
 	KeyNodeHealthAlertPrefix = "health-alert"
@@ -104,9 +110,10 @@ func MakeKey(components ...string) string {
 // MakePrefixPattern returns a regular expression pattern that
 // matches precisely the Gossip keys created by invocations of
 // MakeKey with multiple arguments for which the first argument
-// is equal to the given prefix.
+// is equal to the given prefix. The pattern is anchored so that
+// keys which merely contain the prefix are not matched.
 func MakePrefixPattern(prefix string) string {
-	return regexp.QuoteMeta(prefix+separator) + ".*"
+	return "^" + regexp.QuoteMeta(prefix+separator) + ".*"
 }
 
 // MakeNodeIDKey returns the gossip key for node ID info.
@@ -134,6 +141,17 @@ func NodeIDFromKey(key string, prefix string) (roachpb.NodeID, error) {
 	return roachpb.NodeID(nodeID), nil
 }
 
+// MakeNodeCertKey returns the gossip key for the certificate of the given
+// node.
+func MakeNodeCertKey(nodeID roachpb.NodeID) string {
+	return MakeKey(KeyNodeCertPrefix, nodeID.String())
+}
+
+// IsNodeCertKey returns true iff the provided key is a node certificate key.
+func IsNodeCertKey(key string) bool {
+	return strings.HasPrefix(key, KeyNodeCertPrefix+separator)
+}
+
 // MakeGossipClientsKey returns the gossip client key for the given node.
 func MakeGossipClientsKey(nodeID roachpb.NodeID) string {
 	return MakeKey(KeyGossipClientsPrefix, nodeID.String())
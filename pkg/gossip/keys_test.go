@@ -11,6 +11,7 @@
 package gossip
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -88,3 +89,28 @@ func TestStoreIDFromKey(t *testing.T) {
 		})
 	}
 }
+
+func TestMakePrefixPattern(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	re := regexp.MustCompile(MakePrefixPattern(KeyStorePrefix))
+	testCases := []struct {
+		key   string
+		match bool
+	}{
+		{MakeStoreKey(1), true},
+		{MakeStoreKey(123), true},
+		{KeyStorePrefix, false},
+		{"foo" + MakeStoreKey(1), false},
+		{MakeKey("restore", "1"), false},
+		{MakeNodeIDKey(1), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			if match := re.MatchString(tc.key); match != tc.match {
+				t.Errorf("expected match=%t, got %t", tc.match, match)
+			}
+		})
+	}
+}
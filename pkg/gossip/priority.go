@@ -0,0 +1,82 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import "regexp"
+
+// defaultPriorityPatterns match the keys which are needed to recover from
+// leadership changes and partitions, and which are therefore forwarded to
+// peers as soon as they are received.
+var defaultPriorityPatterns = []string{
+	KeySentinel,
+	KeyClusterID,
+	KeyFirstRangeDescriptor,
+	MakePrefixPattern(KeyNodeLivenessPrefix),
+}
+
+// addPriorityPattern flags keys matching the pattern as high priority.
+func (is *infoStore) addPriorityPattern(pattern string) {
+	is.priorityPatterns = append(is.priorityPatterns, regexp.MustCompile(pattern))
+}
+
+// isPriorityKey returns whether the key matches a priority pattern.
+func (is *infoStore) isPriorityKey(key string) bool {
+	for _, re := range is.priorityPatterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerPriorityNotifier registers a channel which is sent on, without
+// blocking, whenever an info with a priority key is added. Updates to
+// other keys reach outgoing clients through a callback, which runs on the
+// callback goroutine behind any other pending callbacks. Returns a
+// function to unregister the channel.
+func (is *infoStore) registerPriorityNotifier(ch chan<- struct{}) func() {
+	is.priorityNotifiers = append(is.priorityNotifiers, ch)
+	return func() {
+		for i, target := range is.priorityNotifiers {
+			if target == ch {
+				n := len(is.priorityNotifiers)
+				is.priorityNotifiers[i] = is.priorityNotifiers[n-1]
+				is.priorityNotifiers = is.priorityNotifiers[:n-1]
+				break
+			}
+		}
+	}
+}
+
+// maybeNotifyPriority notifies the registered priority notifiers if key is
+// a priority key.
+func (is *infoStore) maybeNotifyPriority(key string) {
+	if len(is.priorityNotifiers) == 0 || !is.isPriorityKey(key) {
+		return
+	}
+	for _, ch := range is.priorityNotifiers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// AddPriorityPattern flags keys matching the supplied regular expression
+// as high priority, in addition to the sentinel, cluster ID, first range
+// descriptor and node liveness keys. Updates to priority keys are
+// forwarded to peers immediately instead of waiting on the processing of
+// other gossip callbacks.
+func (g *Gossip) AddPriorityPattern(pattern string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mu.is.addPriorityPattern(pattern)
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/gossip/resolver"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const (
+	// maxRecentPeers is the number of recently connected peers which are
+	// persisted in the bootstrap info.
+	maxRecentPeers = 5
+	// recentPeerMaxAge is the age beyond which a recent peer is no
+	// longer preferred over the other bootstrap addresses.
+	recentPeerMaxAge = 24 * time.Hour
+	// recentPeersPersistInterval is the interval at which the last-seen
+	// times of the recent peers are persisted. Changes to the set of
+	// recent peers are persisted immediately.
+	recentPeersPersistInterval = 10 * time.Minute
+)
+
+// recordRecentPeersLocked refreshes the last-seen time of the given peers,
+// to which this node currently has healthy outgoing connections, and
+// persists the recent peers if they changed. The gossip mutex must be held
+// by the caller.
+func (g *Gossip) recordRecentPeersLocked(now time.Time, addrs []util.UnresolvedAddr) {
+	if len(addrs) == 0 {
+		return
+	}
+	prev := make(map[util.UnresolvedAddr]struct{}, len(g.bootstrapInfo.RecentPeers))
+	for _, p := range g.bootstrapInfo.RecentPeers {
+		prev[p.Address] = struct{}{}
+	}
+	for _, addr := range addrs {
+		found := false
+		for i := range g.bootstrapInfo.RecentPeers {
+			if p := &g.bootstrapInfo.RecentPeers[i]; p.Address == addr {
+				p.LastSeen = now.UnixNano()
+				found = true
+				break
+			}
+		}
+		if !found {
+			g.bootstrapInfo.RecentPeers = append(g.bootstrapInfo.RecentPeers,
+				RecentPeer{Address: addr, LastSeen: now.UnixNano()})
+		}
+	}
+	sortRecentPeers(g.bootstrapInfo.RecentPeers)
+	if len(g.bootstrapInfo.RecentPeers) > maxRecentPeers {
+		g.bootstrapInfo.RecentPeers = g.bootstrapInfo.RecentPeers[:maxRecentPeers]
+	}
+
+	// With more connected peers than are retained, the ones which don't make
+	// the cut are appended and dropped again on every call, so whether the
+	// set of recent peers changed is determined once it is truncated.
+	changed := len(g.bootstrapInfo.RecentPeers) != len(prev)
+	for _, p := range g.bootstrapInfo.RecentPeers {
+		if _, ok := prev[p.Address]; !ok {
+			changed = true
+		}
+	}
+
+	if g.storage == nil || (!changed && now.Sub(g.recentPeersPersisted) < recentPeersPersistInterval) {
+		return
+	}
+	g.recentPeersPersisted = now
+	if err := g.storage.WriteBootstrapInfo(&g.bootstrapInfo); err != nil {
+		log.Error(g.AnnotateCtx(context.TODO()), err)
+	}
+}
+
+// mergeRecentPeersLocked merges recent peers read from storage into the
+// bootstrap info, retaining the latest last-seen time of each peer. The
+// gossip mutex must be held by the caller.
+func (g *Gossip) mergeRecentPeersLocked(stored []RecentPeer) {
+	for _, sp := range stored {
+		found := false
+		for i := range g.bootstrapInfo.RecentPeers {
+			if p := &g.bootstrapInfo.RecentPeers[i]; p.Address == sp.Address {
+				if sp.LastSeen > p.LastSeen {
+					p.LastSeen = sp.LastSeen
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			g.bootstrapInfo.RecentPeers = append(g.bootstrapInfo.RecentPeers, sp)
+		}
+	}
+	sortRecentPeers(g.bootstrapInfo.RecentPeers)
+	if len(g.bootstrapInfo.RecentPeers) > maxRecentPeers {
+		g.bootstrapInfo.RecentPeers = g.bootstrapInfo.RecentPeers[:maxRecentPeers]
+	}
+}
+
+// preferRecentPeersLocked moves resolvers for the peers which were seen
+// within recentPeerMaxAge to the front of the resolvers, most recently
+// seen first, so that a restarted node reconnects to peers which are
+// known to be good instead of working through the seeds. Returns whether
+// any such peer was found. The gossip mutex must be held by the caller.
+func (g *Gossip) preferRecentPeersLocked(now time.Time) bool {
+	var preferred []resolver.Resolver
+	preferredAddrs := map[string]struct{}{}
+	for _, p := range g.bootstrapInfo.RecentPeers {
+		if now.Sub(time.Unix(0, p.LastSeen)) > recentPeerMaxAge || p.Address == g.mu.is.NodeAddr {
+			continue
+		}
+		g.maybeAddResolverLocked(p.Address)
+		r, ok := g.resolverAddrs[p.Address]
+		if !ok {
+			continue
+		}
+		preferred = append(preferred, r)
+		preferredAddrs[r.Addr()] = struct{}{}
+	}
+	if len(preferred) == 0 {
+		return false
+	}
+	for _, r := range g.resolvers {
+		if _, ok := preferredAddrs[r.Addr()]; !ok {
+			preferred = append(preferred, r)
+		}
+	}
+	g.resolvers = preferred
+	// Start index at end because get next address loop logic increments as
+	// first step.
+	g.resolverIdx = len(g.resolvers) - 1
+	g.resolversTried = map[int]struct{}{}
+	return true
+}
+
+// sortRecentPeers sorts the peers by decreasing last-seen time.
+func sortRecentPeers(peers []RecentPeer) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		return peers[i].LastSeen > peers[j].LastSeen
+	})
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// discoveryRefreshInterval is the minimum interval between two
+	// enumerations of the peers by a discovery resolver.
+	discoveryRefreshInterval = time.Minute
+	// discoveryTimeout bounds the time spent enumerating the peers.
+	discoveryTimeout = 10 * time.Second
+)
+
+// A Lister enumerates the host:port addresses of candidate bootstrap
+// peers from an external source, such as a cloud provider's API.
+type Lister interface {
+	List(ctx context.Context) ([]string, error)
+}
+
+// A ListerFactory constructs a Lister for a discovery address of the form
+// <scheme>://<target>[?<params>]. The port parameter, if any, has been
+// consumed and is applied to addresses which don't include a port.
+type ListerFactory func(target string, params url.Values) (Lister, error)
+
+var listers struct {
+	syncutil.Mutex
+	factories map[string]ListerFactory
+}
+
+// RegisterLister makes the peer discovery mechanism constructed by f
+// available to join addresses with the given scheme. It is intended to
+// be called from init functions.
+func RegisterLister(scheme string, f ListerFactory) {
+	listers.Lock()
+	defer listers.Unlock()
+	if listers.factories == nil {
+		listers.factories = make(map[string]ListerFactory)
+	}
+	if _, ok := listers.factories[scheme]; ok {
+		panic(errors.Errorf("lister already registered for scheme %q", scheme))
+	}
+	listers.factories[scheme] = f
+}
+
+func lookupListerFactory(scheme string) ListerFactory {
+	listers.Lock()
+	defer listers.Unlock()
+	return listers.factories[scheme]
+}
+
+// splitDiscoveryAddress splits an address of the form
+// <scheme>://<target>[?<params>].
+func splitDiscoveryAddress(
+	address string,
+) (scheme, target string, params url.Values, err error) {
+	idx := strings.Index(address, "://")
+	scheme, target = address[:idx], address[idx+len("://"):]
+	if idx := strings.IndexByte(target, '?'); idx >= 0 {
+		params, err = url.ParseQuery(target[idx+1:])
+		if err != nil {
+			return "", "", nil, errors.Wrapf(err, "invalid discovery address %q", address)
+		}
+		target = target[:idx]
+	} else {
+		params = url.Values{}
+	}
+	return scheme, target, params, nil
+}
+
+// IsDiscoveryAddress returns whether the join address designates a peer
+// discovery mechanism, such as k8s://cockroachdb.default.svc.cluster.local,
+// rather than a host.
+func IsDiscoveryAddress(address string) bool {
+	return strings.Index(address, "://") > 0
+}
+
+func newDiscoveryResolver(address string) (Resolver, error) {
+	scheme, target, params, err := splitDiscoveryAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	factory := lookupListerFactory(scheme)
+	if factory == nil {
+		return nil, errors.Errorf("unknown peer discovery scheme %q in %q", scheme, address)
+	}
+	port := params.Get("port")
+	if port == "" {
+		port = base.DefaultPort
+	}
+	params.Del("port")
+	lister, err := factory(target, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid discovery address %q", address)
+	}
+	return &discoveryResolver{
+		typ:    scheme,
+		addr:   address,
+		port:   port,
+		lister: lister,
+	}, nil
+}
+
+// discoveryResolver is a resolver which enumerates the bootstrap peers
+// using a Lister. Each call to GetAddress returns the next peer in turn,
+// and Refresh enumerates the peers anew once all of them have been
+// returned and discoveryRefreshInterval has passed.
+type discoveryResolver struct {
+	typ    string
+	addr   string
+	port   string
+	lister Lister
+
+	mu struct {
+		syncutil.Mutex
+		addrs       []string
+		idx         int
+		lastRefresh time.Time
+	}
+}
+
+var _ Refresher = &discoveryResolver{}
+
+// Type returns the resolver type.
+func (dr *discoveryResolver) Type() string { return dr.typ }
+
+// Addr returns the resolver address.
+func (dr *discoveryResolver) Addr() string { return dr.addr }
+
+// GetAddress returns a net.Addr or error.
+func (dr *discoveryResolver) GetAddress() (net.Addr, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if len(dr.mu.addrs) == 0 {
+		return nil, errors.Errorf("no peers found by %s", dr.addr)
+	}
+	if dr.mu.idx >= len(dr.mu.addrs) {
+		dr.mu.idx = 0
+	}
+	addr := dr.mu.addrs[dr.mu.idx]
+	dr.mu.idx++
+	return util.NewUnresolvedAddr("tcp", addr), nil
+}
+
+// Refresh implements the Refresher interface.
+func (dr *discoveryResolver) Refresh(ctx context.Context) error {
+	dr.mu.Lock()
+	if dr.mu.idx < len(dr.mu.addrs) || timeutil.Since(dr.mu.lastRefresh) < discoveryRefreshInterval {
+		dr.mu.Unlock()
+		return nil
+	}
+	dr.mu.lastRefresh = timeutil.Now()
+	dr.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	listed, err := dr.lister.List(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to discover peers using %s", dr.addr)
+	}
+	addrs := make([]string, 0, len(listed))
+	for _, addr := range listed {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, dr.port)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.mu.addrs = addrs
+	dr.mu.idx = 0
+	return nil
+}
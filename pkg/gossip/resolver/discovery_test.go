@@ -0,0 +1,152 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package resolver
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLister struct {
+	addrs []string
+	calls int
+}
+
+func (l *fakeLister) List(context.Context) ([]string, error) {
+	l.calls++
+	return l.addrs, nil
+}
+
+func TestDiscoveryResolver(t *testing.T) {
+	lister := &fakeLister{addrs: []string{"10.0.0.1", "10.0.0.2:1234", "fe80::1"}}
+	RegisterLister("fake", func(target string, params url.Values) (Lister, error) {
+		require.Equal(t, "cluster", target)
+		require.Equal(t, url.Values{"opt": {"x"}}, params)
+		return lister, nil
+	})
+
+	r, err := NewResolver("fake://cluster?opt=x&port=5000")
+	require.NoError(t, err)
+	require.Equal(t, "fake", r.Type())
+	require.Equal(t, "fake://cluster?opt=x&port=5000", r.Addr())
+
+	// The peers are only returned once they have been listed.
+	_, err = r.GetAddress()
+	require.Error(t, err)
+
+	// The peers are returned in turn, and only listed once within the
+	// refresh interval.
+	var addrs []string
+	for i := 0; i < 4; i++ {
+		require.NoError(t, r.(Refresher).Refresh(context.Background()))
+		addr, err := r.GetAddress()
+		require.NoError(t, err)
+		require.Equal(t, "tcp", addr.Network())
+		addrs = append(addrs, addr.String())
+	}
+	require.Equal(t, []string{"10.0.0.1:5000", "10.0.0.2:1234", "[fe80::1]:5000", "10.0.0.1:5000"}, addrs)
+	require.Equal(t, 1, lister.calls)
+
+	_, err = NewResolver("unknown://cluster")
+	require.Error(t, err)
+}
+
+func TestParseDiscoveryAddress(t *testing.T) {
+	testCases := []struct {
+		address string
+		success bool
+	}{
+		{"k8s://cockroachdb.default.svc.cluster.local", true},
+		{"k8s://cockroachdb.default.svc.cluster.local:26257", true},
+		{"k8s://", false},
+		{"k8s://svc?zone=a", false},
+		{"ec2://cockroachdb-cluster=prod?region=us-east-1", true},
+		{"ec2://cockroachdb-cluster", false},
+		{"ec2://=prod", false},
+		{"gce://cockroachdb?project=p&zone=us-east1-b&port=26257", true},
+		{"gce://cockroachdb?region=us-east1", false},
+		{"gce://", false},
+		{"gce://cockroachdb?zone=%zz", false},
+	}
+	for _, tc := range testCases {
+		_, err := NewResolver(tc.address)
+		if (err == nil) != tc.success {
+			t.Errorf("%s: expected success=%t, got err=%v", tc.address, tc.success, err)
+		}
+	}
+}
+
+func TestK8sLister(t *testing.T) {
+	defer func(saved func(context.Context, string) ([]string, error)) {
+		lookupHost = saved
+	}(lookupHost)
+	lookupHost = func(_ context.Context, host string) ([]string, error) {
+		require.Equal(t, "cockroachdb.default.svc", host)
+		return []string{"10.0.0.1", "10.0.0.2"}, nil
+	}
+
+	r, err := NewResolver("k8s://cockroachdb.default.svc")
+	require.NoError(t, err)
+	require.NoError(t, r.(Refresher).Refresh(context.Background()))
+	addr, err := r.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1:"+base.DefaultPort, addr.String())
+
+	r, err = NewResolver("k8s://cockroachdb.default.svc:1234")
+	require.NoError(t, err)
+	require.NoError(t, r.(Refresher).Refresh(context.Background()))
+	addr, err = r.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1:1234", addr.String())
+}
+
+func TestParseEC2DescribeInstances(t *testing.T) {
+	const resp = `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <reservationSet>
+    <item>
+      <instancesSet>
+        <item><privateIpAddress>10.0.0.1</privateIpAddress></item>
+        <item><privateIpAddress>10.0.0.2</privateIpAddress></item>
+      </instancesSet>
+    </item>
+    <item>
+      <instancesSet>
+        <item><privateIpAddress>10.0.1.1</privateIpAddress></item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+  <nextToken>abc</nextToken>
+</DescribeInstancesResponse>`
+	addrs, nextToken, err := parseEC2DescribeInstances([]byte(resp))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.1.1"}, addrs)
+	require.Equal(t, "abc", nextToken)
+}
+
+func TestParseGCEResponses(t *testing.T) {
+	instances, pageToken, err := parseGCEInstanceGroup([]byte(`{
+  "items": [
+    {"instance": "https://compute.googleapis.com/compute/v1/projects/p/zones/z/instances/a", "status": "RUNNING"},
+    {"instance": "https://compute.googleapis.com/compute/v1/projects/p/zones/z/instances/b", "status": "RUNNING"}
+  ]
+}`))
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	require.Equal(t, "", pageToken)
+
+	addr, err := parseGCEInstance([]byte(`{"networkInterfaces": [{"networkIP": "10.0.0.1"}, {"networkIP": "10.1.0.1"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", addr)
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package resolver
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+// ec2APIVersion is the version of the EC2 query API used to describe
+// instances.
+const ec2APIVersion = "2016-11-15"
+
+func ec2Endpoint(region string) string {
+	return "https://ec2." + region + ".amazonaws.com/"
+}
+
+// ec2Lister enumerates the running EC2 instances carrying a tag, for
+// example ec2://cockroachdb-cluster=prod?region=us-east-1. The region
+// defaults to that of the local instance, and credentials are obtained
+// from the standard AWS sources, such as the instance profile.
+type ec2Lister struct {
+	tagKey   string
+	tagValue string
+	region   string
+}
+
+func newEC2Lister(target string, params url.Values) (Lister, error) {
+	idx := strings.IndexByte(target, '=')
+	if idx <= 0 {
+		return nil, errors.Errorf("expected <tag-key>=<tag-value>; got %q", target)
+	}
+	l := &ec2Lister{tagKey: target[:idx], tagValue: target[idx+1:], region: params.Get("region")}
+	params.Del("region")
+	if len(params) > 0 {
+		return nil, errors.Errorf("unexpected parameters %s", params.Encode())
+	}
+	return l, nil
+}
+
+// List implements the Lister interface.
+func (l *ec2Lister) List(ctx context.Context) ([]string, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	region := l.region
+	if region == "" {
+		if region, err = ec2metadata.New(sess).Region(); err != nil {
+			return nil, errors.Wrap(err, "failed to determine region")
+		}
+	}
+	signer := v4.NewSigner(sess.Config.Credentials)
+
+	var addrs []string
+	var nextToken string
+	for {
+		form := url.Values{
+			"Action":           {"DescribeInstances"},
+			"Version":          {ec2APIVersion},
+			"Filter.1.Name":    {"tag:" + l.tagKey},
+			"Filter.1.Value.1": {l.tagValue},
+			"Filter.2.Name":    {"instance-state-name"},
+			"Filter.2.Value.1": {"running"},
+		}
+		if nextToken != "" {
+			form.Set("NextToken", nextToken)
+		}
+		body := strings.NewReader(form.Encode())
+		req, err := http.NewRequest("POST", ec2Endpoint(region), body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+		if _, err := signer.Sign(req, body, "ec2", region, timeutil.Now()); err != nil {
+			return nil, errors.Wrap(err, "failed to sign request")
+		}
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe instances")
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("failed to describe instances: %s: %s", resp.Status, b)
+		}
+		var page []string
+		page, nextToken, err = parseEC2DescribeInstances(b)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, page...)
+		if nextToken == "" {
+			return addrs, nil
+		}
+	}
+}
+
+// parseEC2DescribeInstances parses a response to the DescribeInstances
+// API call, returning the private IPs of the instances and the token of
+// the next page.
+func parseEC2DescribeInstances(b []byte) (addrs []string, nextToken string, _ error) {
+	var resp struct {
+		Reservations []struct {
+			Instances []struct {
+				PrivateIPAddress string `xml:"privateIpAddress"`
+			} `xml:"instancesSet>item"`
+		} `xml:"reservationSet>item"`
+		NextToken string `xml:"nextToken"`
+	}
+	if err := xml.Unmarshal(b, &resp); err != nil {
+		return nil, "", errors.Wrap(err, "failed to parse instances")
+	}
+	for _, r := range resp.Reservations {
+		for _, i := range r.Instances {
+			if i.PrivateIPAddress != "" {
+				addrs = append(addrs, i.PrivateIPAddress)
+			}
+		}
+	}
+	return addrs, resp.NextToken, nil
+}
+
+func init() {
+	RegisterLister("ec2", newEC2Lister)
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	gceMetadataURL = "http://metadata.google.internal/computeMetadata/v1"
+	gceComputeURL  = "https://compute.googleapis.com/compute/v1"
+)
+
+// gceLister enumerates the running instances of a GCE instance group,
+// for example gce://cockroachdb?zone=us-east1-b. The project and zone
+// default to those of the local instance, and the API is accessed using
+// the credentials of the instance's default service account.
+type gceLister struct {
+	group   string
+	project string
+	zone    string
+}
+
+func newGCELister(target string, params url.Values) (Lister, error) {
+	if target == "" {
+		return nil, errors.New("missing instance group name")
+	}
+	l := &gceLister{group: target, project: params.Get("project"), zone: params.Get("zone")}
+	params.Del("project")
+	params.Del("zone")
+	if len(params) > 0 {
+		return nil, errors.Errorf("unexpected parameters %s", params.Encode())
+	}
+	return l, nil
+}
+
+// gceGet issues a GET request against the metadata server or, if token
+// is non-empty, the compute API.
+func gceGet(ctx context.Context, u, token string) ([]byte, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return gceDo(ctx, req, token)
+}
+
+func gceDo(ctx context.Context, req *http.Request, token string) ([]byte, error) {
+	if token == "" {
+		req.Header.Set("Metadata-Flavor", "Google")
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s %s: %s: %s", req.Method, req.URL, resp.Status, body)
+	}
+	return body, nil
+}
+
+// List implements the Lister interface.
+func (l *gceLister) List(ctx context.Context) ([]string, error) {
+	project, zone := l.project, l.zone
+	if project == "" {
+		b, err := gceGet(ctx, gceMetadataURL+"/project/project-id", "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to determine project")
+		}
+		project = string(b)
+	}
+	if zone == "" {
+		// The zone is returned as projects/<number>/zones/<zone>.
+		b, err := gceGet(ctx, gceMetadataURL+"/instance/zone", "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to determine zone")
+		}
+		zone = string(b[bytes.LastIndexByte(b, '/')+1:])
+	}
+
+	b, err := gceGet(ctx, gceMetadataURL+"/instance/service-accounts/default/token", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain access token")
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, errors.Wrap(err, "failed to parse access token")
+	}
+
+	instances, err := l.listInstances(ctx, project, zone, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, instance := range instances {
+		b, err := gceGet(ctx, instance, token.AccessToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe instance")
+		}
+		addr, err := parseGCEInstance(b)
+		if err != nil {
+			return nil, err
+		}
+		if addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// listInstances returns the URLs of the running instances in the group.
+func (l *gceLister) listInstances(
+	ctx context.Context, project, zone, token string,
+) ([]string, error) {
+	var instances []string
+	var pageToken string
+	for {
+		u := fmt.Sprintf("%s/projects/%s/zones/%s/instanceGroups/%s/listInstances",
+			gceComputeURL, url.PathEscape(project), url.PathEscape(zone), url.PathEscape(l.group))
+		if pageToken != "" {
+			u += "?pageToken=" + url.QueryEscape(pageToken)
+		}
+		req, err := http.NewRequest("POST", u, strings.NewReader(`{"instanceState":"RUNNING"}`))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		b, err := gceDo(ctx, req, token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list instances of group %q", l.group)
+		}
+		var page []string
+		page, pageToken, err = parseGCEInstanceGroup(b)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, page...)
+		if pageToken == "" {
+			return instances, nil
+		}
+	}
+}
+
+// parseGCEInstanceGroup parses a response to the listInstances API call,
+// returning the URLs of the instances and the token of the next page.
+func parseGCEInstanceGroup(b []byte) (instances []string, pageToken string, _ error) {
+	var resp struct {
+		Items []struct {
+			Instance string `json:"instance"`
+		} `json:"items"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, "", errors.Wrap(err, "failed to parse instance group")
+	}
+	for _, item := range resp.Items {
+		instances = append(instances, item.Instance)
+	}
+	return instances, resp.NextPageToken, nil
+}
+
+// parseGCEInstance parses a response to the instances.get API call,
+// returning the internal IP of the instance's primary network interface.
+func parseGCEInstance(b []byte) (string, error) {
+	var resp struct {
+		NetworkInterfaces []struct {
+			NetworkIP string `json:"networkIP"`
+		} `json:"networkInterfaces"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return "", errors.Wrap(err, "failed to parse instance")
+	}
+	if len(resp.NetworkInterfaces) == 0 {
+		return "", nil
+	}
+	return resp.NetworkInterfaces[0].NetworkIP, nil
+}
+
+func init() {
+	RegisterLister("gce", newGCELister)
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	lookupHost = net.DefaultResolver.LookupHost
+)
+
+// k8sLister enumerates the pods backing a Kubernetes headless service,
+// whose DNS name resolves to the addresses of all of the ready pods, for
+// example k8s://cockroachdb.default.svc.cluster.local.
+type k8sLister struct {
+	service string
+}
+
+func newK8sLister(target string, params url.Values) (Lister, error) {
+	if target == "" {
+		return nil, errors.New("missing service name")
+	}
+	if len(params) > 0 {
+		return nil, errors.Errorf("unexpected parameters %s", params.Encode())
+	}
+	return &k8sLister{service: target}, nil
+}
+
+// List implements the Lister interface.
+func (l *k8sLister) List(ctx context.Context) ([]string, error) {
+	service, port, err := net.SplitHostPort(l.service)
+	if err != nil {
+		service, port = l.service, ""
+	}
+	addrs, err := lookupHost(ctx, service)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve service %q", service)
+	}
+	if port != "" {
+		for i := range addrs {
+			addrs[i] = net.JoinHostPort(addrs[i], port)
+		}
+	}
+	return addrs, nil
+}
+
+func init() {
+	RegisterLister("k8s", newK8sLister)
+}
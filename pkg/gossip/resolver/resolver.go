@@ -11,6 +11,7 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -33,11 +34,35 @@ type Resolver interface {
 	GetAddress() (net.Addr, error)
 }
 
-// NewResolver takes an address and returns a new resolver.
+// A Refresher is a Resolver whose addresses are enumerated from an external
+// source, such as DNS or a cloud provider's API. So that GetAddress never
+// blocks, it only returns the addresses found by the latest call to Refresh,
+// which does the enumeration. Refresh is meant to be called without holding
+// the locks under which GetAddress is called, and may be called concurrently
+// with it.
+type Refresher interface {
+	Resolver
+	// Refresh enumerates the addresses anew, if all of those found by the
+	// previous enumeration have been returned and it is due.
+	Refresh(ctx context.Context) error
+}
+
+// NewResolver takes an address and returns a new resolver. Addresses of
+// the form <scheme>://<target>[?<params>] designate a peer discovery
+// mechanism registered with RegisterLister; the built-in ones are:
+//
+//   k8s://<headless service>[:<port>]
+//   ec2://<tag-key>=<tag-value>[?region=<region>]
+//   gce://<instance group>[?project=<project>&zone=<zone>]
+//
+// All of them accept a port parameter, which defaults to base.DefaultPort.
 func NewResolver(address string) (Resolver, error) {
 	if len(address) == 0 {
 		return nil, errors.Errorf("invalid address value: %q", address)
 	}
+	if IsDiscoveryAddress(address) {
+		return newDiscoveryResolver(address)
+	}
 
 	// Ensure addr has port and host set.
 	address = ensureHostPort(address, base.DefaultPort)
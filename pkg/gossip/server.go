@@ -14,6 +14,8 @@ import (
 	"context"
 	"math/rand"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -58,6 +60,10 @@ type server struct {
 	}
 	tighten chan struct{} // Sent on when we may want to tighten the network
 
+	// antiEntropyInterval is the interval between full state exchanges on
+	// each incoming connection. Protected by mu.
+	antiEntropyInterval time.Duration
+
 	nodeMetrics   Metrics
 	serverMetrics Metrics
 
@@ -73,13 +79,14 @@ func newServer(
 	registry *metric.Registry,
 ) *server {
 	s := &server{
-		AmbientContext: ambient,
-		clusterID:      clusterID,
-		NodeID:         nodeID,
-		stopper:        stopper,
-		tighten:        make(chan struct{}, 1),
-		nodeMetrics:    makeMetrics(),
-		serverMetrics:  makeMetrics(),
+		AmbientContext:      ambient,
+		clusterID:           clusterID,
+		NodeID:              nodeID,
+		stopper:             stopper,
+		tighten:             make(chan struct{}, 1),
+		antiEntropyInterval: defaultAntiEntropyInterval,
+		nodeMetrics:         makeMetrics(),
+		serverMetrics:       makeMetrics(),
 	}
 
 	s.mu.is = newInfoStore(s.AmbientContext, nodeID, util.UnresolvedAddr{}, stopper)
@@ -98,6 +105,30 @@ func (s *server) GetNodeMetrics() *Metrics {
 	return &s.nodeMetrics
 }
 
+// checkDeltaClusterID returns an error if the delta carries the cluster ID
+// info of a cluster other than the given one, in which case none of its infos
+// may be combined into the infostore. Nodes which don't know their cluster ID
+// yet learn it from gossip, and accept any.
+func checkDeltaClusterID(delta map[string]*Info, clusterID uuid.UUID) error {
+	i, ok := delta[KeyClusterID]
+	if !ok || clusterID == uuid.Nil {
+		return nil
+	}
+	b, err := i.Value.GetBytes()
+	if err != nil {
+		return errors.Wrap(err, "unable to parse gossiped cluster ID")
+	}
+	remoteID, err := uuid.FromBytes(b)
+	if err != nil {
+		return errors.Wrap(err, "unable to parse gossiped cluster ID")
+	}
+	if remoteID != clusterID {
+		return errors.Errorf("gossip from different cluster %s refused (local cluster %s)",
+			remoteID, clusterID)
+	}
+	return nil
+}
+
 // Gossip receives gossiped information from a peer node.
 // The received delta is combined with the infostore, and this
 // node's own gossip is returned to requesting client.
@@ -107,7 +138,8 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 		return err
 	}
 	if (args.ClusterID != uuid.UUID{}) && args.ClusterID != s.clusterID.Get() {
-		return errors.Errorf("gossip connection refused from different cluster %s", args.ClusterID)
+		return errors.Errorf("gossip connection refused from different cluster %s (local cluster %s)",
+			args.ClusterID, s.clusterID.Get())
 	}
 
 	ctx, cancel := context.WithCancel(s.AnnotateCtx(stream.Context()))
@@ -135,10 +167,15 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 
 	errCh := make(chan error, 1)
 
+	// sentHighWaterStamps tracks the high water stamps which have been sent on
+	// this connection so that subsequent responses only need to include the
+	// stamps which have changed. Protected by s.mu.
+	sentHighWaterStamps := make(map[roachpb.NodeID]int64)
+
 	// Starting workers in a task prevents data races during shutdown.
 	if err := s.stopper.RunTask(ctx, "gossip.server: receiver", func(ctx context.Context) {
 		s.stopper.RunWorker(ctx, func(ctx context.Context) {
-			errCh <- s.gossipReceiver(ctx, &args, send, stream.Recv)
+			errCh <- s.gossipReceiver(ctx, &args, sentHighWaterStamps, send, stream.Recv)
 		})
 	}); err != nil {
 		return err
@@ -146,13 +183,29 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 
 	reply := new(Response)
 
+	// Periodically send the full infostore regardless of the client's high
+	// water stamps so that any infos which were lost or failed to apply
+	// during incremental propagation are eventually repaired.
+	s.mu.RLock()
+	antiEntropyInterval := s.antiEntropyInterval
+	s.mu.RUnlock()
+	antiEntropyTimer := timeutil.NewTimer()
+	defer antiEntropyTimer.Stop()
+	antiEntropyTimer.Reset(jitteredInterval(antiEntropyInterval))
+	var fullState bool
+
 	for init := true; ; init = false {
 		s.mu.Lock()
 		// Store the old ready so that if it gets replaced with a new one
 		// (once the lock is released) and is closed, we still trigger the
 		// select below.
 		ready := s.mu.ready
-		delta := s.mu.is.delta(args.HighWaterStamps)
+		var delta map[string]*Info
+		if fullState {
+			delta = s.mu.is.delta(nil)
+		} else {
+			delta = s.mu.is.delta(args.HighWaterStamps)
+		}
 		if init {
 			s.mu.is.populateMostDistantMarkers(delta)
 		}
@@ -163,7 +216,7 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 		// Send a response if this is the first response on the connection, or if
 		// there are deltas to send. The first condition is necessary to make sure
 		// the remote node receives our high water stamps in a timely fashion.
-		if infoCount := len(delta); init || infoCount > 0 {
+		if infoCount := len(delta); init || fullState || infoCount > 0 {
 			if log.V(1) {
 				log.Infof(ctx, "returning %d info(s) to n%d: %s",
 					infoCount, args.NodeID, extractKeys(delta))
@@ -177,8 +230,9 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 
 			*reply = Response{
 				NodeID:          s.NodeID.Get(),
-				HighWaterStamps: s.mu.is.getHighWaterStamps(),
+				HighWaterStamps: s.mu.is.getHighWaterStampsWithDiff(sentHighWaterStamps),
 				Delta:           delta,
+				FullState:       fullState,
 			}
 
 			s.mu.Unlock()
@@ -188,6 +242,7 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 			s.mu.Lock()
 		}
 
+		fullState = false
 		s.mu.Unlock()
 
 		select {
@@ -196,13 +251,70 @@ func (s *server) Gossip(stream Gossip_GossipServer) error {
 		case err := <-errCh:
 			return err
 		case <-ready:
+		case <-antiEntropyTimer.C:
+			antiEntropyTimer.Read = true
+			antiEntropyTimer.Reset(jitteredInterval(antiEntropyInterval))
+			fullState = true
 		}
 	}
 }
 
+// chooseAlternateLocked returns the address and node ID of the incoming
+// peer to which a refused client should be forwarded. Peers are weighed by
+// the number of incoming connections they are known to have, as gossiped
+// by their clients under KeyGossipClientsPrefix, and the least loaded one
+// is chosen, breaking ties randomly. Forwarding to a random peer instead
+// would leave nodes which many clients bootstrap through, such as seed
+// nodes, with a hub of connections whose peers are just as full.
+func (s *server) chooseAlternateLocked(
+	ctx context.Context,
+) (util.UnresolvedAddr, roachpb.NodeID) {
+	loads := make(map[roachpb.NodeID]int)
+	for key, i := range s.mu.is.Infos {
+		if !strings.HasPrefix(key, KeyGossipClientsPrefix+separator) {
+			continue
+		}
+		v, err := i.Value.GetBytes()
+		if err != nil {
+			log.Errorf(ctx, "unable to retrieve gossip value for %s: %v", key, err)
+			continue
+		}
+		if len(v) == 0 {
+			continue
+		}
+		for _, part := range strings.Split(string(v), ",") {
+			id, err := strconv.ParseInt(part, 10 /* base */, 64 /* bitSize */)
+			if err != nil {
+				log.Errorf(ctx, "unable to parse node ID: %v", err)
+				continue
+			}
+			loads[roachpb.NodeID(id)]++
+		}
+	}
+
+	var alternateAddr util.UnresolvedAddr
+	var alternateNodeID roachpb.NodeID
+	minLoad, ties := 0, 0
+	for addr, info := range s.mu.nodeMap {
+		load := loads[info.peerID]
+		if ties == 0 || load < minLoad {
+			minLoad, ties = load, 0
+		} else if load > minLoad {
+			continue
+		}
+		// Pick uniformly among the peers with the lowest load seen so far.
+		ties++
+		if rand.Intn(ties) == 0 {
+			alternateAddr, alternateNodeID = addr, info.peerID
+		}
+	}
+	return alternateAddr, alternateNodeID
+}
+
 func (s *server) gossipReceiver(
 	ctx context.Context,
 	argsPtr **Request,
+	sentHighWaterStamps map[roachpb.NodeID]int64,
 	senderFn func(*Response) error,
 	receiverFn func() (*Request, error),
 ) error {
@@ -260,18 +372,7 @@ func (s *server) gossipReceiver(
 				}(args.NodeID, args.Addr)
 			} else {
 				// If we don't have any space left, forward the client along to a peer.
-				var alternateAddr util.UnresolvedAddr
-				var alternateNodeID roachpb.NodeID
-				// Choose a random peer for forwarding.
-				altIdx := rand.Intn(len(s.mu.nodeMap))
-				for addr, info := range s.mu.nodeMap {
-					if altIdx == 0 {
-						alternateAddr = addr
-						alternateNodeID = info.peerID
-						break
-					}
-					altIdx--
-				}
+				alternateAddr, alternateNodeID := s.chooseAlternateLocked(ctx)
 
 				s.nodeMetrics.ConnectionsRefused.Inc(1)
 				log.Infof(ctx, "refusing gossip from n%d (max %d conns); forwarding to n%d (%s)",
@@ -305,6 +406,9 @@ func (s *server) gossipReceiver(
 		s.serverMetrics.BytesReceived.Inc(bytesReceived)
 		s.serverMetrics.InfosReceived.Inc(infosReceived)
 
+		if err := checkDeltaClusterID(args.Delta, s.clusterID.Get()); err != nil {
+			return err
+		}
 		freshCount, err := s.mu.is.combine(args.Delta, args.NodeID)
 		if err != nil {
 			log.Warningf(ctx, "failed to fully combine gossip delta from n%d: %s", args.NodeID, err)
@@ -316,7 +420,7 @@ func (s *server) gossipReceiver(
 
 		*reply = Response{
 			NodeID:          s.NodeID.Get(),
-			HighWaterStamps: s.mu.is.getHighWaterStamps(),
+			HighWaterStamps: s.mu.is.getHighWaterStampsWithDiff(sentHighWaterStamps),
 		}
 
 		s.mu.Unlock()
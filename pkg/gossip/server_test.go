@@ -0,0 +1,85 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"google.golang.org/grpc"
+)
+
+// fakeGossipStream is a Gossip_GossipServer which hands out a single
+// request.
+type fakeGossipStream struct {
+	grpc.ServerStream
+	req *Request
+}
+
+func (s *fakeGossipStream) Recv() (*Request, error) { return s.req, nil }
+
+func (s *fakeGossipStream) Send(*Response) error { return nil }
+
+// TestGossipServerRefusesDifferentCluster verifies that the gossip server
+// refuses connections from nodes which belong to a different cluster.
+func TestGossipServerRefusesDifferentCluster(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+	localID, remoteID := uuid.MakeV4(), uuid.MakeV4()
+	g.server.clusterID.Set(context.Background(), localID)
+
+	err := g.server.Gossip(&fakeGossipStream{req: &Request{NodeID: 2, ClusterID: remoteID}})
+	expected := fmt.Sprintf("gossip connection refused from different cluster %s \\(local cluster %s\\)",
+		remoteID, localID)
+	if !testutils.IsError(err, expected) {
+		t.Fatalf("expected error %q, got %v", expected, err)
+	}
+}
+
+// TestCheckDeltaClusterID verifies that gossip carrying the cluster ID of a
+// different cluster is refused, unless the local cluster ID isn't known yet.
+func TestCheckDeltaClusterID(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	localID, remoteID := uuid.MakeV4(), uuid.MakeV4()
+	delta := func(id uuid.UUID) map[string]*Info {
+		i := &Info{}
+		i.Value.SetBytes(id.GetBytes())
+		return map[string]*Info{KeyClusterID: i, KeySentinel: {}}
+	}
+
+	testCases := []struct {
+		delta     map[string]*Info
+		clusterID uuid.UUID
+		expected  string
+	}{
+		{map[string]*Info{KeySentinel: {}}, localID, ""},
+		{delta(localID), localID, ""},
+		{delta(remoteID), uuid.Nil, ""},
+		{delta(remoteID), localID, fmt.Sprintf(
+			"gossip from different cluster %s refused \\(local cluster %s\\)", remoteID, localID)},
+	}
+	for i, c := range testCases {
+		if err := checkDeltaClusterID(c.delta, c.clusterID); !testutils.IsError(err, c.expected) {
+			t.Errorf("%d: expected error %q, got %v", i, c.expected, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+)
+
+// InfoSigner signs infos originated by the local node so that receivers
+// can verify that they were neither forged nor modified in transit.
+type InfoSigner interface {
+	Sign(payload []byte) ([]byte, error)
+	// Certificate returns the DER-encoded certificate whose key signs the
+	// infos. It is gossiped by the local node under its node certificate key
+	// (see MakeNodeCertKey), so that the other nodes can verify the infos it
+	// originates against it.
+	Certificate() []byte
+}
+
+// InfoVerifier verifies the signature of an info received from the gossip
+// network. It is passed the ID of the node which claims to have originated
+// the info, along with the DER-encoded certificate gossiped by that node.
+type InfoVerifier interface {
+	Verify(nodeID roachpb.NodeID, cert, payload, signature []byte) error
+}
+
+// infoSigningPayload returns the bytes covered by an info's signature. The
+// fields which are modified as the info propagates through the network
+// (Hops and PeerID) are excluded.
+func infoSigningPayload(key string, i *Info) []byte {
+	buf := make([]byte, 0, len(key)+len(i.Value.RawBytes)+40)
+	buf = append(buf, key...)
+	var scratch [8]byte
+	for _, v := range []uint64{
+		uint64(i.NodeID),
+		uint64(i.OrigStamp),
+		uint64(i.TTLStamp),
+		uint64(i.Value.Timestamp.WallTime),
+		uint64(i.Value.Timestamp.Logical),
+	} {
+		binary.BigEndian.PutUint64(scratch[:], v)
+		buf = append(buf, scratch[:]...)
+	}
+	return append(buf, i.Value.RawBytes...)
+}
+
+type keySigner struct {
+	cert []byte
+	key  crypto.Signer
+}
+
+// NewInfoSigner returns an InfoSigner which signs the SHA-256 digest of
+// each info using the supplied private key, typically the node
+// certificate's key, of which cert is the DER-encoded certificate. ECDSA and
+// RSA keys are supported.
+func NewInfoSigner(cert []byte, key crypto.Signer) InfoSigner {
+	return keySigner{cert: cert, key: key}
+}
+
+// Sign implements the InfoSigner interface.
+func (s keySigner) Sign(payload []byte) ([]byte, error) {
+	digest := sha256.Sum256(payload)
+	return s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// Certificate implements the InfoSigner interface.
+func (s keySigner) Certificate() []byte {
+	return s.cert
+}
+
+// certVerifier verifies infos against the certificates gossiped by their
+// originating nodes, which must be signed by one of its roots.
+type certVerifier struct {
+	roots *x509.CertPool
+
+	mu struct {
+		syncutil.Mutex
+		// keys caches the public key of the last certificate verified for
+		// each node, along with the certificate.
+		keys map[roachpb.NodeID]verifiedCert
+	}
+}
+
+type verifiedCert struct {
+	der string
+	pub crypto.PublicKey
+}
+
+// NewInfoVerifier returns an InfoVerifier which checks signatures produced
+// by an InfoSigner against the certificate gossiped by the originating
+// node, once it has verified that the certificate was issued by one of the
+// supplied roots, typically the cluster CA.
+//
+// This only binds infos to node IDs by proof of possession: a valid signature
+// proves that the info was originated by a holder of the key of a
+// certificate issued by the CA, which gossiped that certificate for the node
+// ID of the info. Node certificates aren't bound to node IDs, so a holder of
+// any node certificate can gossip it for another node ID (replacing the
+// certificate of that node), and a cluster sharing a single node certificate
+// gets no protection between its nodes.
+func NewInfoVerifier(roots *x509.CertPool) InfoVerifier {
+	v := &certVerifier{roots: roots}
+	v.mu.keys = map[roachpb.NodeID]verifiedCert{}
+	return v
+}
+
+// publicKey returns the public key of the given certificate of a node, once
+// it is verified to be issued by the roots.
+func (v *certVerifier) publicKey(nodeID roachpb.NodeID, der []byte) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if c, ok := v.mu.keys[nodeID]; ok && c.der == string(der) {
+		return c.pub, nil
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrapf(err, "malformed certificate gossiped by n%d", nodeID)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     v.roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, errors.Wrapf(err, "untrusted certificate gossiped by n%d", nodeID)
+	}
+	v.mu.keys[nodeID] = verifiedCert{der: string(der), pub: cert.PublicKey}
+	return cert.PublicKey, nil
+}
+
+// Verify implements the InfoVerifier interface.
+func (v *certVerifier) Verify(nodeID roachpb.NodeID, cert, payload, signature []byte) error {
+	if len(signature) == 0 {
+		return errors.Errorf("missing signature from n%d", nodeID)
+	}
+	if len(cert) == 0 {
+		return errors.Errorf("no certificate gossiped by n%d", nodeID)
+	}
+	pub, err := v.publicKey(nodeID, cert)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(payload)
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		var sig struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(signature, &sig); err != nil {
+			return errors.Wrapf(err, "malformed signature from n%d", nodeID)
+		} else if len(rest) != 0 {
+			return errors.Errorf("malformed signature from n%d: trailing data", nodeID)
+		}
+		if !ecdsa.Verify(pub, digest[:], sig.R, sig.S) {
+			return errors.Errorf("invalid signature from n%d", nodeID)
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrapf(err, "invalid signature from n%d", nodeID)
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported public key type %T for n%d", pub, nodeID)
+	}
+}
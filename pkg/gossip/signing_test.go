@@ -0,0 +1,224 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// testCA issues certificates for the test signers.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             timeutil.Now().Add(-time.Hour),
+		NotAfter:              timeutil.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// newSigner returns a signer with its own key, whose certificate is issued
+// by the CA.
+func (ca *testCA) newSigner(t *testing.T) InfoSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(timeutil.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    timeutil.Now().Add(-time.Hour),
+		NotAfter:     timeutil.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewInfoSigner(der, key)
+}
+
+// newSigningTestInfoStore returns an infostore for the given node which
+// signs its infos with the signer, if any, and has gossiped its certificate.
+func newSigningTestInfoStore(
+	t *testing.T, nodeID roachpb.NodeID, signer InfoSigner, stopper *stop.Stopper,
+) *infoStore {
+	nc := &base.NodeIDContainer{}
+	nc.Set(context.TODO(), nodeID)
+	is := newInfoStore(log.AmbientContext{Tracer: tracing.NewTracer()}, nc, emptyAddr, stopper)
+	if signer != nil {
+		is.signer = signer
+		if err := is.addInfo(MakeNodeCertKey(nodeID), is.newInfo(signer.Certificate(), 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return is
+}
+
+// TestInfoSigning verifies that signed infos are accepted by a verifying
+// infostore and that forged or unsigned infos are rejected.
+func TestInfoSigning(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	ca := newTestCA(t)
+
+	src := newSigningTestInfoStore(t, 1, ca.newSigner(t), stopper)
+	if err := src.addInfo("a", src.newInfo([]byte("value"), time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	signed := src.getInfo("a")
+	if len(signed.Signature) == 0 {
+		t.Fatal("expected info to be signed")
+	}
+	certKey := MakeNodeCertKey(1)
+
+	dst := newSigningTestInfoStore(t, 2, nil /* signer */, stopper)
+	dst.verifier = NewInfoVerifier(ca.pool)
+
+	// The infos of a node can't be verified until its certificate is known.
+	if _, err := dst.combine(map[string]*Info{"a": signed}, 1); !testutils.IsError(err, "no certificate gossiped by n1") {
+		t.Fatalf("expected missing certificate error, got %v", err)
+	}
+	if freshCount, err := dst.combine(map[string]*Info{"a": signed, certKey: src.getInfo(certKey)}, 1); err != nil {
+		t.Fatal(err)
+	} else if freshCount != 2 {
+		t.Fatalf("expected 2 fresh infos, got %d", freshCount)
+	}
+
+	forged := *signed
+	forged.TTLStamp++
+	unsigned := *signed
+	unsigned.Signature = nil
+	for key, info := range map[string]*Info{"forged": &forged, "unsigned": &unsigned} {
+		if freshCount, err := dst.combine(map[string]*Info{key: info}, 1); err == nil {
+			t.Errorf("%s: expected verification error", key)
+		} else if freshCount != 0 {
+			t.Errorf("%s: expected no fresh infos, got %d", key, freshCount)
+		}
+		if dst.getInfo(key) != nil {
+			t.Errorf("%s: expected info to be rejected", key)
+		}
+	}
+}
+
+// TestInfoSigningDistinctKeys verifies that the infos of nodes holding
+// distinct keys are verified against the certificate of their originating
+// node, so that a node can't forge the infos of another.
+func TestInfoSigningDistinctKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	ca := newTestCA(t)
+
+	n1 := newSigningTestInfoStore(t, 1, ca.newSigner(t), stopper)
+	n2 := newSigningTestInfoStore(t, 2, ca.newSigner(t), stopper)
+	for _, is := range []*infoStore{n1, n2} {
+		key := MakeKey("info", is.nodeID.Get().String())
+		if err := is.addInfo(key, is.newInfo([]byte("value"), time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := newSigningTestInfoStore(t, 3, nil /* signer */, stopper)
+	dst.verifier = NewInfoVerifier(ca.pool)
+	for _, is := range []*infoStore{n1, n2} {
+		if freshCount, err := dst.combine(is.delta(nil), is.nodeID.Get()); err != nil {
+			t.Fatal(err)
+		} else if freshCount != 2 {
+			t.Fatalf("n%d: expected 2 fresh infos, got %d", is.nodeID.Get(), freshCount)
+		}
+	}
+
+	// n2 forges an info on behalf of n1, signed with its own key.
+	forged := n2.newInfo([]byte("forged"), time.Hour)
+	forged.NodeID = 1
+	forged.OrigStamp = monotonicUnixNano()
+	sig, err := n2.signer.Sign(infoSigningPayload("forged", forged))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged.Signature = sig
+
+	// n2 relays its own certificate as the certificate of n1.
+	forgedCert := *n2.getInfo(MakeNodeCertKey(2))
+	forgedCert.OrigStamp = monotonicUnixNano()
+
+	// A certificate which wasn't issued by the CA.
+	untrusted := newSigningTestInfoStore(t, 4, newTestCA(t).newSigner(t), stopper)
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		info   *Info
+		expErr string
+	}{
+		{"forged info", "forged", forged, "invalid signature from n1"},
+		{"forged cert", MakeNodeCertKey(1), &forgedCert, "certificate of n1 originated by n2"},
+		{"untrusted cert", MakeNodeCertKey(4), untrusted.getInfo(MakeNodeCertKey(4)), "untrusted certificate gossiped by n4"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if freshCount, err := dst.combine(map[string]*Info{tc.key: tc.info}, 2); !testutils.IsError(err, tc.expErr) {
+				t.Fatalf("expected %q, got %v", tc.expErr, err)
+			} else if freshCount != 0 {
+				t.Fatalf("expected no fresh infos, got %d", freshCount)
+			}
+		})
+	}
+	if i := dst.getInfo("forged"); i != nil {
+		t.Fatalf("expected forged info to be rejected, got %+v", i)
+	}
+	if cert := dst.getInfo(MakeNodeCertKey(1)); !bytes.Equal(cert.Value.RawBytes, n1.getInfo(MakeNodeCertKey(1)).Value.RawBytes) {
+		t.Fatal("expected the certificate of n1 to be retained")
+	}
+}
@@ -13,6 +13,7 @@ package simulation
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"google.golang.org/grpc"
@@ -51,7 +53,9 @@ func (n *Node) Addr() net.Addr {
 	return n.Listener.Addr()
 }
 
-// Network provides access to a test gossip network of nodes.
+// Network provides access to a test gossip network of nodes. The nodes all
+// bootstrap off the first one, and neither leave the network nor lose
+// messages while it runs.
 type Network struct {
 	Nodes           []*Node
 	Stopper         *stop.Stopper
@@ -172,6 +176,7 @@ func (n *Network) GetNodeFromID(nodeID roachpb.NodeID) (*Node, bool) {
 // The simulation callback receives the cycle and the network as arguments.
 func (n *Network) SimulateNetwork(simCallback func(cycle int, network *Network) bool) {
 	n.Start()
+	start := timeutil.Now()
 	nodes := n.Nodes
 	for cycle := 1; ; cycle++ {
 		// Node 0 gossips sentinel & cluster ID every cycle.
@@ -210,7 +215,8 @@ func (n *Network) SimulateNetwork(simCallback func(cycle int, network *Network)
 		}
 		time.Sleep(5 * time.Millisecond)
 	}
-	log.Infof(context.TODO(), "gossip network simulation: total infos sent=%d, received=%d", n.infosSent(), n.infosReceived())
+	log.Infof(context.TODO(), "gossip network simulation: total infos sent=%d, received=%d, elapsed=%s, fanout=%s",
+		n.infosSent(), n.infosReceived(), timeutil.Since(start), n.Fanout())
 }
 
 // Start starts all gossip nodes.
@@ -257,6 +263,40 @@ func (n *Network) IsNetworkConnected() bool {
 	return true
 }
 
+// FanoutStats summarizes the number of gossip connections (incoming
+// and outgoing) held by the nodes of a Network.
+type FanoutStats struct {
+	Min, Max int
+	Mean     float64
+}
+
+func (s FanoutStats) String() string {
+	return fmt.Sprintf("min=%d max=%d mean=%.2f", s.Min, s.Max, s.Mean)
+}
+
+// Fanout returns statistics about the number of gossip connections held
+// by each node in the network. A connection between two nodes is counted
+// once for each endpoint.
+func (n *Network) Fanout() FanoutStats {
+	var stats FanoutStats
+	if len(n.Nodes) == 0 {
+		return stats
+	}
+	var total int
+	for i, node := range n.Nodes {
+		conns := len(node.Gossip.Incoming()) + len(node.Gossip.Outgoing())
+		if i == 0 || conns < stats.Min {
+			stats.Min = conns
+		}
+		if conns > stats.Max {
+			stats.Max = conns
+		}
+		total += conns
+	}
+	stats.Mean = float64(total) / float64(len(n.Nodes))
+	return stats
+}
+
 // infosSent returns the total count of infos sent from all nodes in
 // the network.
 func (n *Network) infosSent() int {
@@ -12,7 +12,9 @@ package gossip
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -104,3 +106,49 @@ func (c Connectivity) String() string {
 	}
 	return buf.String()
 }
+
+// DOT returns the connectivity graph in the Graphviz DOT language. Edges
+// point from the node holding an outgoing gossip client connection to the
+// node serving it.
+func (c Connectivity) DOT() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "digraph gossip {\n")
+	if c.SentinelNodeID != 0 {
+		fmt.Fprintf(&buf, "  n%d [label=\"n%d (sentinel)\"];\n", c.SentinelNodeID, c.SentinelNodeID)
+	}
+	for _, conn := range c.ClientConns {
+		fmt.Fprintf(&buf, "  n%d -> n%d;\n", conn.SourceID, conn.TargetID)
+	}
+	fmt.Fprintf(&buf, "}\n")
+	return buf.String()
+}
+
+// ConnectivityDebugFn returns an http.HandlerFunc which renders the gossip
+// network as seen by this node. By default the cluster-wide connectivity
+// graph is rendered in the Graphviz DOT language. With "format=json", the
+// graph is returned along with this node's incoming and outgoing
+// connections and their ages, which helps when debugging partial
+// connectivity.
+func (g *Gossip) ConnectivityDebugFn() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		connectivity := g.Connectivity()
+		if req.URL.Query().Get("format") != "json" {
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+			_, _ = w.Write([]byte(connectivity.DOT()))
+			return
+		}
+		resp := struct {
+			Connectivity Connectivity `json:"connectivity"`
+			Outgoing     ClientStatus `json:"outgoing"`
+			Incoming     ServerStatus `json:"incoming"`
+		}{
+			Connectivity: connectivity,
+			Outgoing:     g.clientStatus(),
+			Incoming:     g.server.status(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -56,3 +56,23 @@ func TestGossipStatus(t *testing.T) {
 	}
 
 }
+
+func TestGossipConnectivityDOT(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := Connectivity{
+		SentinelNodeID: 1,
+		ClientConns: []Connectivity_Conn{
+			{SourceID: 2, TargetID: 1},
+			{SourceID: 3, TargetID: 2},
+		},
+	}
+	if exp, act := `digraph gossip {
+  n1 [label="n1 (sentinel)"];
+  n2 -> n1;
+  n3 -> n2;
+}
+`, c.DOT(); exp != act {
+		t.Errorf("expected:\n%q\ngot:\n%q", exp, act)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// WatchEvent describes an update to a watched gossip key.
+type WatchEvent struct {
+	Key   string
+	Value roachpb.Value
+	// Timestamp is the HLC timestamp at which the value was originated.
+	Timestamp hlc.Timestamp
+}
+
+// watcher buffers the updates for a Watch between the gossip callback
+// goroutine and the consumer. Updates are coalesced per key so that a
+// slow consumer neither blocks gossip callbacks nor accumulates an
+// unbounded backlog; it is always eventually handed the latest value of
+// every key which changed.
+type watcher struct {
+	notify chan struct{}
+	mu     struct {
+		syncutil.Mutex
+		keys    []string              // keys with pending events, in update order
+		pending map[string]WatchEvent // latest undelivered event per key
+	}
+}
+
+func newWatcher() *watcher {
+	w := &watcher{notify: make(chan struct{}, 1)}
+	w.mu.pending = make(map[string]WatchEvent)
+	return w
+}
+
+// enqueue is the Callback registered for the watched prefix.
+func (w *watcher) enqueue(key string, val roachpb.Value) {
+	w.mu.Lock()
+	if _, ok := w.mu.pending[key]; !ok {
+		w.mu.keys = append(w.mu.keys, key)
+	}
+	w.mu.pending[key] = WatchEvent{Key: key, Value: val, Timestamp: val.Timestamp}
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the oldest pending event, if any.
+func (w *watcher) next() (WatchEvent, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.mu.keys) == 0 {
+		return WatchEvent{}, false
+	}
+	key := w.mu.keys[0]
+	w.mu.keys = w.mu.keys[1:]
+	ev := w.mu.pending[key]
+	delete(w.mu.pending, key)
+	return ev, true
+}
+
+// Watch returns a channel of updates to the gossip keys beginning with
+// prefix, along with a function which stops the watch. The current value
+// of every matching key is delivered before any subsequent update. If a
+// key is updated several times before the consumer receives the event,
+// only the latest value is delivered. The channel is closed once the
+// watch is stopped or the node shuts down.
+func (g *Gossip) Watch(prefix string) (<-chan WatchEvent, func()) {
+	w := newWatcher()
+	unregister := g.RegisterCallback("^"+regexp.QuoteMeta(prefix), w.enqueue)
+
+	ch := make(chan WatchEvent)
+	done := make(chan struct{})
+	ctx := g.AnnotateCtx(context.Background())
+	g.server.stopper.RunWorker(ctx, func(ctx context.Context) {
+		defer close(ch)
+		for {
+			if ev, ok := w.next(); ok {
+				select {
+				case ch <- ev:
+					continue
+				case <-done:
+					return
+				case <-g.server.stopper.ShouldQuiesce():
+					return
+				}
+			}
+			select {
+			case <-w.notify:
+			case <-done:
+				return
+			case <-g.server.stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			unregister()
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gossip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+func TestGossipWatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	g := NewTest(1, nil, nil, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+
+	expectEvent := func(ch <-chan WatchEvent, key, val string) {
+		t.Helper()
+		select {
+		case ev := <-ch:
+			b, err := ev.Value.GetBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ev.Key != key || string(b) != val {
+				t.Fatalf("expected %s=%s; got %s=%s", key, val, ev.Key, b)
+			}
+			if ev.Timestamp.WallTime == 0 {
+				t.Fatalf("expected event for %s to carry a timestamp", key)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s=%s", key, val)
+		}
+	}
+
+	if err := g.AddInfo("watch:a", []byte("1"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	ch, unwatch := g.Watch("watch:")
+
+	// The current value is delivered first.
+	expectEvent(ch, "watch:a", "1")
+
+	// Keys outside the prefix are not delivered.
+	if err := g.AddInfo("other", []byte("x"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddInfo("watch:b", []byte("2"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(ch, "watch:b", "2")
+
+	if err := g.AddInfo("watch:a", []byte("3"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(ch, "watch:a", "3")
+
+	unwatch()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("unexpected event after the watch was stopped")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watch channel to close")
+	}
+}
@@ -407,7 +407,8 @@ func (b *Batch) PutInline(key, value interface{}) {
 // typed nil value (e.g. []byte(nil)).
 //
 // A new result will be appended to the batch which will contain a single row
-// and Result.Err will indicate success or failure.
+// and Result.Err will indicate success or failure. On a mismatch, Result.Err is
+// a *roachpb.ConditionFailedError carrying the existing value.
 //
 // key can be either a byte slice or a string. value can be any key type, a
 // protoutil.Message or any Go primitive type (bool, int, etc).
@@ -376,7 +376,8 @@ func (db *DB) PutInline(ctx context.Context, key, value interface{}) error {
 // pass nil for expValue. Note that this must be an interface{}(nil), not a
 // typed nil value (e.g. []byte(nil)).
 //
-// Returns an error if the existing value is not equal to expValue.
+// Returns a *roachpb.ConditionFailedError carrying the existing value, if any,
+// when it is not equal to expValue.
 //
 // key can be either a byte slice or a string. value can be any key type, a
 // protoutil.Message or any Go primitive type (bool, int, etc).
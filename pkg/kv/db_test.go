@@ -118,8 +118,11 @@ func TestDB_CPut(t *testing.T) {
 	}
 	checkResult(t, []byte("2"), result.ValueBytes())
 
-	if err = db.CPut(ctx, "aa", "3", strToValue("1")); err == nil {
-		t.Fatal("expected error from conditional put")
+	err = db.CPut(ctx, "aa", "3", strToValue("1"))
+	if cErr, ok := err.(*roachpb.ConditionFailedError); !ok {
+		t.Fatalf("expected ConditionFailedError from conditional put, got %v", err)
+	} else if actual, err := cErr.ActualValue.GetBytes(); err != nil || !bytes.Equal(actual, []byte("2")) {
+		t.Fatalf("expected actual value \"2\", got %v (%v)", cErr.ActualValue, err)
 	}
 	result, err = db.Get(ctx, "aa")
 	if err != nil {
@@ -127,8 +130,11 @@ func TestDB_CPut(t *testing.T) {
 	}
 	checkResult(t, []byte("2"), result.ValueBytes())
 
-	if err = db.CPut(ctx, "bb", "4", strToValue("1")); err == nil {
-		t.Fatal("expected error from conditional put")
+	err = db.CPut(ctx, "bb", "4", strToValue("1"))
+	if cErr, ok := err.(*roachpb.ConditionFailedError); !ok {
+		t.Fatalf("expected ConditionFailedError from conditional put, got %v", err)
+	} else if cErr.ActualValue != nil {
+		t.Fatalf("expected no actual value, got %v", cErr.ActualValue)
 	}
 	result, err = db.Get(ctx, "bb")
 	if err != nil {
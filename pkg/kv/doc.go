@@ -114,6 +114,10 @@ retries as a matter of course. This is why the transaction functionality is
 exposed through a retryable function. The retryable function should have no
 side effects which are not idempotent.
 
+All transactions run at SERIALIZABLE isolation. Earlier versions also offered
+SNAPSHOT isolation, which was removed because it permitted write skew
+anomalies and was rarely used.
+
 Transactions should endeavor to use batches to perform multiple operations in a
 single RPC. In addition to the reduced number of RPCs to the server, this
 allows writes to the same range to be batched together. In cases where the
@@ -0,0 +1,86 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvapi
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// Result is the result of one operation of a Batch.
+type Result struct {
+	// Value is the value read by a Get, or nil if the key does not exist.
+	Value []byte
+	// Rows are the rows read by a Scan.
+	Rows []KeyValue
+}
+
+// Batch groups operations which are sent to the cluster together by
+// DB.Run. Results holds the result of each operation, in the order they
+// were added, once the batch has run.
+type Batch struct {
+	Results []Result
+
+	reqs []roachpb.Request
+}
+
+// Get adds a read of a key to the batch.
+func (b *Batch) Get(key roachpb.Key) {
+	b.reqs = append(b.reqs, roachpb.NewGet(key))
+}
+
+// Put adds a write of a key to the batch.
+func (b *Batch) Put(key roachpb.Key, value []byte) {
+	b.reqs = append(b.reqs, roachpb.NewPut(key, roachpb.MakeValueFromBytes(value)))
+}
+
+// Delete adds the deletion of a key to the batch.
+func (b *Batch) Delete(key roachpb.Key) {
+	b.reqs = append(b.reqs, roachpb.NewDelete(key))
+}
+
+// Scan adds a read of all the rows in the span [start, end) to the batch.
+func (b *Batch) Scan(start, end roachpb.Key) {
+	b.reqs = append(b.reqs, roachpb.NewScan(start, end, false /* forUpdate */))
+}
+
+// DeleteRange adds the deletion of all the rows in the span [start, end) to
+// the batch.
+func (b *Batch) DeleteRange(start, end roachpb.Key) {
+	b.reqs = append(b.reqs, roachpb.NewDeleteRange(start, end, false /* returnKeys */))
+}
+
+func (b *Batch) fillResults(br *roachpb.BatchResponse) error {
+	if len(br.Responses) != len(b.reqs) {
+		return errors.AssertionFailedf("expected %d responses, got %d", len(b.reqs), len(br.Responses))
+	}
+	b.Results = make([]Result, len(b.reqs))
+	for i, ru := range br.Responses {
+		switch resp := ru.GetInner().(type) {
+		case *roachpb.GetResponse:
+			if resp.Value == nil {
+				continue
+			}
+			v, err := resp.Value.GetBytes()
+			if err != nil {
+				return errors.Wrapf(err, "decoding value of key %s", b.reqs[i].Header().Key)
+			}
+			b.Results[i].Value = v
+		case *roachpb.ScanResponse:
+			rows, err := keyValues(resp.Rows)
+			if err != nil {
+				return err
+			}
+			b.Results[i].Rows = rows
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package kvapi is a client for the KV service exposed by every node (see
+// serverpb.KVServer). It lets applications outside the cluster read and
+// write keys without programming against raw RPC methods.
+//
+// A DB sends each batch to one of the configured nodes, which routes the
+// requests to the ranges holding their keys. Connections come from the
+// rpc.Context, which caches them and re-dials nodes whose heartbeats fail.
+// Batches which could not reach a node, or were rejected because of stale
+// range routing, are retried on the next node.
+package kvapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// scanPageSize is the number of rows fetched per request by DB.Scan.
+const scanPageSize = 1000
+
+// DefaultRetryOptions are the retry options used when Config.RetryOptions
+// is not set.
+var DefaultRetryOptions = retry.Options{
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+	MaxRetries:     10,
+}
+
+// Config configures a DB.
+type Config struct {
+	// Addrs are the RPC addresses of the nodes to send requests to. The
+	// first reachable node is used until it fails.
+	Addrs []string
+	// RetryOptions control how batches which failed for transient reasons
+	// are retried.
+	RetryOptions retry.Options
+}
+
+// KeyValue is a key and its value.
+type KeyValue struct {
+	Key   roachpb.Key
+	Value []byte
+}
+
+// DB is a handle to a cluster for reading and writing keys through the KV
+// service. It is safe for concurrent use.
+type DB struct {
+	rpcCtx    *rpc.Context
+	addrs     []string
+	retryOpts retry.Options
+
+	mu struct {
+		syncutil.Mutex
+		// addrIdx is the index in addrs of the node requests are sent to.
+		addrIdx int
+	}
+}
+
+// NewDB returns a DB which sends requests to the given nodes using
+// connections from rpcCtx. The user of the requests is the user of the
+// client certificate of rpcCtx.
+func NewDB(rpcCtx *rpc.Context, cfg Config) (*DB, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, errors.New("no node addresses specified")
+	}
+	db := &DB{
+		rpcCtx:    rpcCtx,
+		addrs:     append([]string(nil), cfg.Addrs...),
+		retryOpts: cfg.RetryOptions,
+	}
+	if db.retryOpts == (retry.Options{}) {
+		db.retryOpts = DefaultRetryOptions
+	}
+	return db, nil
+}
+
+// Get returns the value of a key, or nil if the key does not exist.
+func (db *DB) Get(ctx context.Context, key roachpb.Key) ([]byte, error) {
+	b := &Batch{}
+	b.Get(key)
+	if err := db.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	return b.Results[0].Value, nil
+}
+
+// Put sets the value of a key.
+func (db *DB) Put(ctx context.Context, key roachpb.Key, value []byte) error {
+	b := &Batch{}
+	b.Put(key, value)
+	return db.Run(ctx, b)
+}
+
+// Delete deletes the given keys. Deleting a key which does not exist is not
+// an error.
+func (db *DB) Delete(ctx context.Context, keys ...roachpb.Key) error {
+	b := &Batch{}
+	for _, key := range keys {
+		b.Delete(key)
+	}
+	return db.Run(ctx, b)
+}
+
+// Scan returns the rows in the span [start, end), in key order. At most
+// maxRows rows are returned, or all of them if maxRows is zero.
+func (db *DB) Scan(
+	ctx context.Context, start, end roachpb.Key, maxRows int64,
+) ([]KeyValue, error) {
+	var rows []KeyValue
+	span := &roachpb.Span{Key: start, EndKey: end}
+	for span != nil {
+		limit := int64(scanPageSize)
+		if maxRows > 0 && maxRows-int64(len(rows)) < limit {
+			limit = maxRows - int64(len(rows))
+		}
+		var ba roachpb.BatchRequest
+		ba.MaxSpanRequestKeys = limit
+		ba.Add(roachpb.NewScan(span.Key, span.EndKey, false /* forUpdate */))
+		br, err := db.send(ctx, &ba)
+		if err != nil {
+			return nil, err
+		}
+		resp := br.Responses[0].GetScan()
+		page, err := keyValues(resp.Rows)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, page...)
+		if maxRows > 0 && int64(len(rows)) >= maxRows {
+			break
+		}
+		span = resp.ResumeSpan
+	}
+	return rows, nil
+}
+
+// Run sends the operations of the batch to the cluster and fills in
+// b.Results. The writes of a batch are applied atomically.
+func (db *DB) Run(ctx context.Context, b *Batch) error {
+	if len(b.reqs) == 0 {
+		return nil
+	}
+	var ba roachpb.BatchRequest
+	ba.Add(b.reqs...)
+	br, err := db.send(ctx, &ba)
+	if err != nil {
+		return err
+	}
+	return b.fillResults(br)
+}
+
+// send sends the batch to the current node, moving on to the next one when
+// the node cannot be reached. Batches which may have been evaluated are only
+// retried if they are idempotent.
+func (db *DB) send(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) (*roachpb.BatchResponse, error) {
+	idempotent := true
+	for _, ru := range ba.Requests {
+		if _, ok := ru.GetInner().(*roachpb.ConditionalPutRequest); ok {
+			idempotent = false
+		}
+	}
+
+	var lastErr error
+	for r := retry.StartWithCtx(ctx, db.retryOpts); r.Next(); {
+		addr := db.currentAddr()
+		conn, err := db.rpcCtx.GRPCUnvalidatedDial(addr).Connect(ctx)
+		if err != nil {
+			log.VEventf(ctx, 2, "failed to connect to %s: %s", addr, err)
+			lastErr = err
+			db.skipAddr(addr)
+			continue
+		}
+		br, err := serverpb.NewKVClient(conn).Batch(ctx, ba)
+		if err != nil {
+			if !grpcutil.RequestDidNotStart(err) && !(idempotent && isUnavailable(err)) {
+				return nil, err
+			}
+			log.VEventf(ctx, 2, "failed to send batch to %s: %s", addr, err)
+			lastErr = err
+			db.skipAddr(addr)
+			continue
+		}
+		if br.Error != nil {
+			if !isRoutingError(br.Error) {
+				return nil, br.Error.GoError()
+			}
+			log.VEventf(ctx, 2, "batch sent to %s was misrouted: %s", addr, br.Error)
+			lastErr = br.Error.GoError()
+			continue
+		}
+		return br, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.Wrap(lastErr, "batch failed after retries")
+}
+
+func (db *DB) currentAddr() string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.addrs[db.mu.addrIdx]
+}
+
+// skipAddr moves on to the next node, unless another request already did
+// after addr failed.
+func (db *DB) skipAddr(addr string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.addrs[db.mu.addrIdx] == addr {
+		db.mu.addrIdx = (db.mu.addrIdx + 1) % len(db.addrs)
+	}
+}
+
+// isUnavailable returns true if the error indicates that the node could not
+// be reached, in which case the batch may or may not have been evaluated.
+func isUnavailable(err error) bool {
+	if s, ok := grpcstatus.FromError(errors.Cause(err)); ok && s.Code() == codes.Unavailable {
+		return true
+	}
+	return grpcutil.IsClosedConnection(err)
+}
+
+// isRoutingError returns true for the errors returned when the node could
+// not find the range or leaseholder for a request. The request was not
+// evaluated, so it can safely be retried once the range caches catch up.
+func isRoutingError(pErr *roachpb.Error) bool {
+	switch pErr.GetDetail().(type) {
+	case *roachpb.NotLeaseHolderError, *roachpb.RangeNotFoundError,
+		*roachpb.RangeKeyMismatchError:
+		return true
+	}
+	return false
+}
+
+func keyValues(rows []roachpb.KeyValue) ([]KeyValue, error) {
+	kvs := make([]KeyValue, len(rows))
+	for i := range rows {
+		v, err := rows[i].Value.GetBytes()
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding value of key %s", rows[i].Key)
+		}
+		kvs[i] = KeyValue{Key: rows[i].Key, Value: v}
+	}
+	return kvs, nil
+}
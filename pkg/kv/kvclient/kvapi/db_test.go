@@ -0,0 +1,157 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvapi_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvapi"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/stretchr/testify/require"
+)
+
+// startDB starts a server and returns a DB connected to it as root. The
+// first address of the DB does not accept connections, so that every test
+// exercises the failover to the next node.
+func startDB(t *testing.T) (serverutils.TestServerInterface, *kvapi.DB) {
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadAddr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	rpcContext := rpc.NewContext(
+		log.AmbientContext{Tracer: s.ClusterSettings().Tracer},
+		testutils.NewTestBaseContext(security.RootUser), s.Clock(), s.Stopper(),
+		s.ClusterSettings(),
+	)
+	db, err := kvapi.NewDB(rpcContext, kvapi.Config{
+		Addrs: []string{deadAddr, s.ServingRPCAddr()},
+		RetryOptions: retry.Options{
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+			Multiplier:     2,
+		},
+	})
+	require.NoError(t, err)
+	return s, db
+}
+
+func TestDB(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db := startDB(t)
+	defer s.Stopper().Stop(ctx)
+
+	keyA, keyB, keyC := roachpb.Key("a"), roachpb.Key("b"), roachpb.Key("c")
+	require.NoError(t, db.Put(ctx, keyA, []byte("1")))
+	v, err := db.Get(ctx, keyA)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), v)
+
+	b := &kvapi.Batch{}
+	b.Put(keyB, []byte("2"))
+	b.Put(keyC, []byte("3"))
+	b.Get(keyA)
+	require.NoError(t, db.Run(ctx, b))
+	require.Equal(t, []byte("1"), b.Results[2].Value)
+
+	rows, err := db.Scan(ctx, keyA, roachpb.Key("d"), 0 /* maxRows */)
+	require.NoError(t, err)
+	require.Equal(t, []kvapi.KeyValue{
+		{Key: keyA, Value: []byte("1")},
+		{Key: keyB, Value: []byte("2")},
+		{Key: keyC, Value: []byte("3")},
+	}, rows)
+	rows, err = db.Scan(ctx, keyA, roachpb.Key("d"), 2 /* maxRows */)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.NoError(t, db.Delete(ctx, keyA, keyB))
+	v, err = db.Get(ctx, keyA)
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
+
+// TestDBRunTransaction increments a counter from concurrent transactions and
+// verifies that no increment is lost.
+func TestDBRunTransaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db := startDB(t)
+	defer s.Stopper().Stop(ctx)
+
+	const numTxns = 10
+	key := roachpb.Key("counter")
+	var attempts int32
+	var wg sync.WaitGroup
+	errs := make(chan error, numTxns)
+	for i := 0; i < numTxns; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- db.RunTransaction(ctx, func(ctx context.Context, txn *kvapi.Txn) error {
+				atomic.AddInt32(&attempts, 1)
+				v, err := txn.Get(ctx, key)
+				if err != nil {
+					return err
+				}
+				var n int
+				if v != nil {
+					if n, err = strconv.Atoi(string(v)); err != nil {
+						return err
+					}
+				}
+				txn.Put(key, []byte(strconv.Itoa(n+1)))
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	v, err := db.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, strconv.Itoa(numTxns), string(v))
+	require.True(t, atomic.LoadInt32(&attempts) >= numTxns)
+
+	// Deleting a key which was read is conditional as well.
+	require.NoError(t, db.RunTransaction(ctx, func(ctx context.Context, txn *kvapi.Txn) error {
+		if _, err := txn.Get(ctx, key); err != nil {
+			return err
+		}
+		txn.Delete(key)
+		return nil
+	}))
+	v, err = db.Get(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
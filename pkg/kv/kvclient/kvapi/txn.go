@@ -0,0 +1,161 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvapi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+)
+
+// Txn is a transaction started by DB.RunTransaction.
+//
+// The KV service only evaluates non-transactional batches, so transactions
+// are optimistic: reads go to the cluster right away, writes are buffered,
+// and the commit sends a single batch which applies all the writes at once
+// and checks, with conditional puts, that none of the keys read has changed
+// in the meantime. Scans are not supported since their result cannot be
+// checked this way. Transactions which do not write commit without checking
+// their reads, so their reads need not all reflect the same point in time.
+type Txn struct {
+	db *DB
+	// reads holds the values of the keys read from the cluster, nil for the
+	// keys which did not exist.
+	reads map[string]*roachpb.Value
+	// writes holds the buffered writes, nil for deletions.
+	writes map[string][]byte
+}
+
+// Get returns the value of a key, or nil if the key does not exist. It
+// reflects the writes of the transaction.
+func (txn *Txn) Get(ctx context.Context, key roachpb.Key) ([]byte, error) {
+	if v, ok := txn.writes[string(key)]; ok {
+		return v, nil
+	}
+	v, ok := txn.reads[string(key)]
+	if !ok {
+		var ba roachpb.BatchRequest
+		ba.Add(roachpb.NewGet(key))
+		br, err := txn.db.send(ctx, &ba)
+		if err != nil {
+			return nil, err
+		}
+		v = br.Responses[0].GetGet().Value
+		txn.reads[string(key)] = v
+	}
+	if v == nil {
+		return nil, nil
+	}
+	return v.GetBytes()
+}
+
+// Put sets the value of a key when the transaction commits.
+func (txn *Txn) Put(key roachpb.Key, value []byte) {
+	if value == nil {
+		value = []byte{}
+	}
+	txn.writes[string(key)] = value
+}
+
+// Delete deletes a key when the transaction commits.
+func (txn *Txn) Delete(key roachpb.Key) {
+	txn.writes[string(key)] = nil
+}
+
+// commitBatch returns the batch which applies the writes of the
+// transaction, conditional on the keys read still having the values read.
+// The requests are sorted by key.
+func (txn *Txn) commitBatch() *roachpb.BatchRequest {
+	ba := &roachpb.BatchRequest{}
+	if len(txn.writes) == 0 {
+		return ba
+	}
+	keys := make([]string, 0, len(txn.reads)+len(txn.writes))
+	for k := range txn.reads {
+		keys = append(keys, k)
+	}
+	for k := range txn.writes {
+		if _, ok := txn.reads[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := roachpb.Key(k)
+		read, wasRead := txn.reads[k]
+		// Copy the value read, since NewConditionalPut sets the checksum of
+		// the new value in place.
+		var exp roachpb.Value
+		if read != nil {
+			exp.RawBytes = append([]byte(nil), read.RawBytes...)
+			exp.ClearChecksum()
+		}
+		written, wasWritten := txn.writes[k]
+		var value roachpb.Value
+		if written != nil {
+			value = roachpb.MakeValueFromBytes(written)
+		}
+		switch {
+		case !wasRead && written != nil:
+			ba.Add(roachpb.NewPut(key, value))
+		case !wasRead:
+			ba.Add(roachpb.NewDelete(key))
+		case wasWritten:
+			// A nil value writes a deletion tombstone.
+			ba.Add(roachpb.NewConditionalPut(key, value, exp, false /* allowNotExist */))
+		default:
+			// Rewrite the value read to verify that it did not change.
+			value.RawBytes = append([]byte(nil), exp.RawBytes...)
+			ba.Add(roachpb.NewConditionalPut(key, value, exp, false /* allowNotExist */))
+		}
+	}
+	return ba
+}
+
+// RunTransaction runs fn in a transaction and commits its writes if it
+// returns nil. fn is retried, with a fresh transaction, when a key it read
+// was modified before the commit, so it must not have side effects other
+// than through its Txn.
+func (db *DB) RunTransaction(ctx context.Context, fn func(context.Context, *Txn) error) error {
+	var lastErr error
+	for r := retry.StartWithCtx(ctx, db.retryOpts); r.Next(); {
+		txn := &Txn{
+			db:     db,
+			reads:  make(map[string]*roachpb.Value),
+			writes: make(map[string][]byte),
+		}
+		if err := fn(ctx, txn); err != nil {
+			return err
+		}
+		ba := txn.commitBatch()
+		if len(ba.Requests) == 0 {
+			return nil
+		}
+		_, err := db.send(ctx, ba)
+		if err == nil {
+			return nil
+		}
+		if !errors.HasType(err, (*roachpb.ConditionFailedError)(nil)) {
+			return err
+		}
+		log.VEventf(ctx, 2, "retrying transaction: %s", err)
+		lastErr = err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.Wrap(lastErr, "transaction failed after retries")
+}
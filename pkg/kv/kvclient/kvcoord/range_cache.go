@@ -590,9 +590,14 @@ func (rdc *RangeDescriptorCache) insertRangeDescriptorsLocked(
 		// pre-existing, overlapping descriptor which might have been
 		// re-inserted due to concurrent range lookups.
 		continueWithInsert, err := rdc.clearOverlappingCachedRangeDescriptors(ctx, &rs[i])
-		if err != nil || !continueWithInsert {
+		if err != nil {
 			return err
 		}
+		if !continueWithInsert {
+			// A newer descriptor is already cached; skip this one but keep
+			// inserting the rest.
+			continue
+		}
 		rangeKey := keys.RangeMetaKey(rs[i].EndKey)
 		if log.V(2) {
 			log.Infof(ctx, "adding descriptor: key=%s desc=%s", rangeKey, &rs[i])
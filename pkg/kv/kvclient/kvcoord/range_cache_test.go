@@ -1121,3 +1121,53 @@ func TestRangeCacheGeneration(t *testing.T) {
 		})
 	}
 }
+
+// TestRangeCacheInsertSkipsStaleDescriptors verifies that a stale descriptor
+// passed to InsertRangeDescriptors does not prevent the descriptors after it
+// from being inserted.
+func TestRangeCacheInsertSkipsStaleDescriptors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.TODO()
+
+	descAM1 := roachpb.RangeDescriptor{
+		StartKey:             roachpb.RKey("a"),
+		EndKey:               roachpb.RKey("m"),
+		Generation:           proto.Int64(1),
+		GenerationComparable: proto.Bool(true),
+	}
+	descAM2 := roachpb.RangeDescriptor{
+		StartKey:             roachpb.RKey("a"),
+		EndKey:               roachpb.RKey("m"),
+		Generation:           proto.Int64(2),
+		GenerationComparable: proto.Bool(true),
+	}
+	descMZ1 := roachpb.RangeDescriptor{
+		StartKey:             roachpb.RKey("m"),
+		EndKey:               roachpb.RKey("z"),
+		Generation:           proto.Int64(1),
+		GenerationComparable: proto.Bool(true),
+	}
+
+	st := cluster.MakeTestingClusterSettings()
+	cache := NewRangeDescriptorCache(st, nil, staticSize(2<<10))
+	if err := cache.InsertRangeDescriptors(ctx, descAM2); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.InsertRangeDescriptors(ctx, descAM1, descMZ1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		key      roachpb.RKey
+		expected *roachpb.RangeDescriptor
+	}{
+		{roachpb.RKey("b"), &descAM2},
+		{roachpb.RKey("n"), &descMZ1},
+	} {
+		if actualDesc, err := cache.GetCachedRangeDescriptor(tc.key, false); err != nil {
+			t.Fatal(err)
+		} else if !tc.expected.Equal(actualDesc) {
+			t.Errorf("key %s: expected descriptor %s; got %s", tc.key, tc.expected, actualDesc)
+		}
+	}
+}
@@ -897,6 +897,10 @@ func storeHasReplica(storeID roachpb.StoreID, existing []roachpb.ReplicaDescript
 	return false
 }
 
+// sameLocalityAndAttrs returns whether the two stores are interchangeable as
+// far as zone config constraints and locality diversity are concerned, i.e.
+// whether their node localities, node attributes and store attributes all
+// match.
 func sameLocalityAndAttrs(s1, s2 roachpb.StoreDescriptor) bool {
 	if !s1.Node.Locality.Equals(s2.Node.Locality) {
 		return false
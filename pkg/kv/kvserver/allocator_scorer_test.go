@@ -1410,6 +1410,48 @@ func TestRemovalDiversityScore(t *testing.T) {
 	}
 }
 
+func TestSameLocalityAndAttrs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	makeStore := func(locality string, nodeAttrs, storeAttrs []string) roachpb.StoreDescriptor {
+		var l roachpb.Locality
+		if err := l.Set(locality); err != nil {
+			t.Fatal(err)
+		}
+		return roachpb.StoreDescriptor{
+			Attrs: roachpb.Attributes{Attrs: storeAttrs},
+			Node: roachpb.NodeDescriptor{
+				Locality: l,
+				Attrs:    roachpb.Attributes{Attrs: nodeAttrs},
+			},
+		}
+	}
+	base := makeStore("region=us,zone=a", []string{"ram:64gb"}, []string{"ssd", "fast"})
+
+	testCases := []struct {
+		name     string
+		store    roachpb.StoreDescriptor
+		expected bool
+	}{
+		{"identical", makeStore("region=us,zone=a", []string{"ram:64gb"}, []string{"ssd", "fast"}), true},
+		{"reordered store attrs", makeStore("region=us,zone=a", []string{"ram:64gb"}, []string{"fast", "ssd"}), true},
+		{"different zone", makeStore("region=us,zone=b", []string{"ram:64gb"}, []string{"ssd", "fast"}), false},
+		{"fewer tiers", makeStore("region=us", []string{"ram:64gb"}, []string{"ssd", "fast"}), false},
+		{"different node attrs", makeStore("region=us,zone=a", nil, []string{"ssd", "fast"}), false},
+		{"different store attrs", makeStore("region=us,zone=a", []string{"ram:64gb"}, []string{"hdd", "fast"}), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if a, e := sameLocalityAndAttrs(base, tc.store), tc.expected; a != e {
+				t.Errorf("sameLocalityAndAttrs(%v, %v) = %t, want %t", base, tc.store, a, e)
+			}
+			if a, e := sameLocalityAndAttrs(tc.store, base), tc.expected; a != e {
+				t.Errorf("sameLocalityAndAttrs(%v, %v) = %t, want %t", tc.store, base, a, e)
+			}
+		})
+	}
+}
+
 func TestDiversityScoreEquivalence(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1559,3 +1601,35 @@ func TestMaxCapacity(t *testing.T) {
 		}
 	}
 }
+
+func TestRebalanceToMaxCapacity(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		available int64
+		// Whether the store may receive a necessary replica and whether it may
+		// receive a rebalance, respectively.
+		allocate, rebalance bool
+	}{
+		{available: 100, allocate: true, rebalance: true},
+		{available: 8, allocate: true, rebalance: true},
+		// A store between the two thresholds can still accept replicas that are
+		// needed to restore replication, but is no longer a rebalance target.
+		{available: 7, allocate: true, rebalance: false},
+		{available: 5, allocate: false, rebalance: false},
+		{available: 0, allocate: false, rebalance: false},
+	}
+	for _, tc := range testCases {
+		store := roachpb.StoreDescriptor{
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: tc.available},
+		}
+		if a := maxCapacityCheck(store); a != tc.allocate {
+			t.Errorf("%d available: expected max capacity check %t, actual %t",
+				tc.available, tc.allocate, a)
+		}
+		if a := rebalanceToMaxCapacityCheck(store); a != tc.rebalance {
+			t.Errorf("%d available: expected rebalance max capacity check %t, actual %t",
+				tc.available, tc.rebalance, a)
+		}
+	}
+}
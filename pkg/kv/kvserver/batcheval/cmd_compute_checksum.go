@@ -37,8 +37,14 @@ func declareKeysComputeChecksum(
 // Version numbers for Replica checksum computation. Requests silently no-op
 // unless the versions are compatible.
 const (
-	ReplicaChecksumVersion    = 4
-	ReplicaChecksumGCInterval = time.Hour
+	ReplicaChecksumVersion = 4
+	// ReplicaChecksumVersionGCShadowed is the version of the checksums which
+	// exclude the MVCC versions shadowed below the GC threshold, which the
+	// storage engine compactions may have dropped on some of the replicas
+	// only. It is only requested once all of the nodes support it (see
+	// storage.CompactionGCActive).
+	ReplicaChecksumVersionGCShadowed = 5
+	ReplicaChecksumGCInterval        = time.Hour
 )
 
 // ComputeChecksum starts the process of computing a checksum on the replica at
@@ -0,0 +1,82 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeleteRangeResumeSpan verifies that DeleteRange respects
+// MaxSpanRequestKeys and returns a resume span covering the keys it did not
+// get to.
+func TestDeleteRangeResumeSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+
+	keys := []roachpb.Key{roachpb.Key("a"), roachpb.Key("b"), roachpb.Key("c"), roachpb.Key("d")}
+	for _, k := range keys {
+		require.NoError(t, storage.MVCCPut(
+			ctx, eng, nil, k, hlc.Timestamp{WallTime: 1}, roachpb.MakeValueFromString("value"), nil,
+		))
+	}
+
+	deleteRange := func(start roachpb.Key, maxKeys int64) *roachpb.DeleteRangeResponse {
+		var ms enginepb.MVCCStats
+		resp := &roachpb.DeleteRangeResponse{}
+		_, err := DeleteRange(ctx, eng, CommandArgs{
+			Args: &roachpb.DeleteRangeRequest{
+				RequestHeader: roachpb.RequestHeader{Key: start, EndKey: roachpb.Key("z")},
+				ReturnKeys:    true,
+			},
+			Header: roachpb.Header{
+				Timestamp:          hlc.Timestamp{WallTime: 2},
+				MaxSpanRequestKeys: maxKeys,
+			},
+			Stats: &ms,
+		}, resp)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := deleteRange(keys[0], 3)
+	require.EqualValues(t, 3, resp.NumKeys)
+	require.Equal(t, keys[:3], resp.Keys)
+	require.NotNil(t, resp.ResumeSpan)
+	require.Equal(t, keys[3], resp.ResumeSpan.Key)
+	require.Equal(t, roachpb.Key("z"), resp.ResumeSpan.EndKey)
+	require.Equal(t, roachpb.RESUME_KEY_LIMIT, resp.ResumeReason)
+
+	// Resuming deletes the remaining key and finishes the span.
+	resp = deleteRange(resp.ResumeSpan.Key, 3)
+	require.EqualValues(t, 1, resp.NumKeys)
+	require.Equal(t, keys[3:], resp.Keys)
+	require.Nil(t, resp.ResumeSpan)
+
+	kvs, err := storage.Scan(eng, roachpb.KeyMin, roachpb.KeyMax, 0)
+	require.NoError(t, err)
+	// Every key now has a deletion tombstone above its original version.
+	require.Len(t, kvs, 2*len(keys))
+	res, err := storage.MVCCScan(ctx, eng, roachpb.KeyMin, roachpb.KeyMax,
+		hlc.Timestamp{WallTime: 3}, storage.MVCCScanOptions{})
+	require.NoError(t, err)
+	require.Empty(t, res.KVs)
+}
@@ -13,6 +13,7 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
@@ -80,6 +81,14 @@ func GC(
 		return result.Result{}, err
 	}
 
+	// The storage engine compactions may have dropped some of the versions
+	// shadowed below the GC threshold, which MVCCGarbageCollect then can't
+	// subtract from the stats. See storage.CompactionGCer.
+	if storage.CompactionGCActive(ctx, cArgs.EvalCtx.ClusterSettings()) {
+		_ = clusterversion.VersionContainsEstimatesCounter // see for info on ContainsEstimates migration
+		cArgs.Stats.ContainsEstimates++
+	}
+
 	// Protect against multiple GC requests arriving out of order; we track
 	// the maximum timestamps.
 
@@ -0,0 +1,77 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrement(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+
+	key, strKey := roachpb.Key("a"), roachpb.Key("b")
+	require.NoError(t, storage.MVCCPut(
+		ctx, eng, nil, strKey, hlc.Timestamp{WallTime: 1}, roachpb.MakeValueFromString("foo"), nil,
+	))
+
+	var wallTime int64 = 1
+	increment := func(key roachpb.Key, inc int64) (int64, error) {
+		wallTime++
+		var ms enginepb.MVCCStats
+		resp := &roachpb.IncrementResponse{}
+		_, err := Increment(ctx, eng, CommandArgs{
+			Args: &roachpb.IncrementRequest{
+				RequestHeader: roachpb.RequestHeader{Key: key},
+				Increment:     inc,
+			},
+			Header: roachpb.Header{Timestamp: hlc.Timestamp{WallTime: wallTime}},
+			Stats:  &ms,
+		}, resp)
+		return resp.NewValue, err
+	}
+
+	// A missing key is treated as zero.
+	newVal, err := increment(key, 5)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), newVal)
+
+	newVal, err = increment(key, -7)
+	require.NoError(t, err)
+	require.Equal(t, int64(-2), newVal)
+
+	value, _, err := storage.MVCCGet(ctx, eng, key, hlc.Timestamp{WallTime: wallTime}, storage.MVCCGetOptions{})
+	require.NoError(t, err)
+	i, err := value.GetInt()
+	require.NoError(t, err)
+	require.Equal(t, int64(-2), i)
+
+	// Overflowing returns the current value along with the error.
+	newVal, err = increment(key, math.MinInt64)
+	require.IsType(t, &roachpb.IntegerOverflowError{}, err)
+	require.Equal(t, int64(-2), newVal)
+
+	_, err = increment(strKey, 1)
+	require.True(t, testutils.IsError(err, "does not contain an integer value"), "%v", err)
+}
@@ -13,9 +13,11 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -34,5 +36,14 @@ func Merge(
 	args := cArgs.Args.(*roachpb.MergeRequest)
 	h := cArgs.Header
 
+	// The merge operator of the older versions concatenates the integer
+	// operands instead of summing them, so the replicas running them would
+	// resolve the merge differently.
+	if args.Value.GetTag() == roachpb.ValueType_INT &&
+		!cArgs.EvalCtx.ClusterSettings().Version.IsActive(ctx, clusterversion.VersionIntegerMerge) {
+		return result.Result{}, errors.Errorf("integer merges require cluster version %s",
+			clusterversion.VersionByKey(clusterversion.VersionIntegerMerge))
+	}
+
 	return result.Result{}, storage.MVCCMerge(ctx, readWriter, cArgs.Stats, args.Key, h.Timestamp, args.Value)
 }
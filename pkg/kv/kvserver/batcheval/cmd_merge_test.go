@@ -0,0 +1,68 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMergeIntegerVersionGate verifies that integer merges are refused until
+// all of the nodes sum the integer operands.
+func TestMergeIntegerVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+
+	key := roachpb.Key("a")
+	merge := func(st *cluster.Settings, i int64) error {
+		var value roachpb.Value
+		value.SetInt(i)
+		var ms enginepb.MVCCStats
+		_, err := Merge(ctx, eng, CommandArgs{
+			EvalCtx: (&MockEvalCtx{ClusterSettings: st}).EvalContext(),
+			Args: &roachpb.MergeRequest{
+				RequestHeader: roachpb.RequestHeader{Key: key},
+				Value:         value,
+			},
+			Stats: &ms,
+		}, &roachpb.MergeResponse{})
+		return err
+	}
+
+	oldVersion := clusterversion.VersionByKey(clusterversion.VersionIntegerMerge - 1)
+	oldSettings := cluster.MakeTestingClusterSettingsWithVersions(oldVersion, oldVersion, true /* initializeVersion */)
+	err := merge(oldSettings, 1)
+	require.True(t, testutils.IsError(err, "integer merges require cluster version"), "%v", err)
+
+	st := cluster.MakeTestingClusterSettings()
+	require.NoError(t, merge(st, 1))
+	require.NoError(t, merge(st, -5))
+	require.NoError(t, merge(st, 100))
+
+	value, _, err := storage.MVCCGet(ctx, eng, key, hlc.Timestamp{}, storage.MVCCGetOptions{})
+	require.NoError(t, err)
+	i, err := value.GetInt()
+	require.NoError(t, err)
+	require.Equal(t, int64(96), i)
+}
@@ -107,6 +107,11 @@ func RecomputeStats(
 			// which was a boolean, so we should keep it in {0,1} and not reset it
 			// to avoid racing with another command that sets it to true.
 			delta.ContainsEstimates = currentStats.ContainsEstimates
+		} else if storage.CompactionGCActive(ctx, cArgs.EvalCtx.ClusterSettings()) {
+			// The storage engine compactions may still drop versions shadowed
+			// below the GC threshold, so the stats stay marked as estimates.
+			// See storage.CompactionGCer.
+			delta.ContainsEstimates = 0
 		}
 		cArgs.Stats.Add(delta)
 	}
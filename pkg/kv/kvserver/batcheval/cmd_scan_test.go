@@ -110,3 +110,68 @@ func testScanReverseScanInner(
 		require.Len(t, rows, expN)
 	}
 }
+
+// TestScanReverseScanMaxSpanRequestKeys paginates through a key range using
+// the MaxSpanRequestKeys limit and the returned resume spans.
+func TestScanReverseScanMaxSpanRequestKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	ts := hlc.Timestamp{WallTime: 1}
+
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+
+	var keys []roachpb.Key
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		keys = append(keys, roachpb.Key(k))
+		err := storage.MVCCPut(ctx, eng, nil, roachpb.Key(k), ts, roachpb.MakeValueFromString("value-"+k), nil)
+		require.NoError(t, err)
+	}
+
+	const maxKeys = 2
+	testutils.RunTrueAndFalse(t, "reverse", func(t *testing.T, reverse bool) {
+		span := &roachpb.Span{Key: keys[0], EndKey: roachpb.KeyMax}
+		var scanned []roachpb.Key
+		var pages int
+		for span != nil {
+			pages++
+			cArgs := CommandArgs{
+				Header: roachpb.Header{Timestamp: ts, MaxSpanRequestKeys: maxKeys},
+			}
+			var reply roachpb.ResponseHeader
+			var rows []roachpb.KeyValue
+			if !reverse {
+				cArgs.Args = &roachpb.ScanRequest{RequestHeader: roachpb.RequestHeaderFromSpan(*span)}
+				resp := &roachpb.ScanResponse{}
+				_, err := Scan(ctx, eng, cArgs, resp)
+				require.NoError(t, err)
+				reply, rows = resp.ResponseHeader, resp.Rows
+			} else {
+				cArgs.Args = &roachpb.ReverseScanRequest{RequestHeader: roachpb.RequestHeaderFromSpan(*span)}
+				resp := &roachpb.ReverseScanResponse{}
+				_, err := ReverseScan(ctx, eng, cArgs, resp)
+				require.NoError(t, err)
+				reply, rows = resp.ResponseHeader, resp.Rows
+			}
+			require.LessOrEqual(t, reply.NumKeys, int64(maxKeys))
+			require.Len(t, rows, int(reply.NumKeys))
+			for _, kv := range rows {
+				scanned = append(scanned, kv.Key)
+			}
+			if reply.ResumeSpan != nil {
+				require.Equal(t, roachpb.RESUME_KEY_LIMIT, reply.ResumeReason)
+			}
+			span = reply.ResumeSpan
+		}
+
+		expected := append([]roachpb.Key(nil), keys...)
+		if reverse {
+			for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+				expected[i], expected[j] = expected[j], expected[i]
+			}
+		}
+		require.Equal(t, expected, scanned)
+		require.Equal(t, (len(keys)+maxKeys-1)/maxKeys, pages)
+	})
+}
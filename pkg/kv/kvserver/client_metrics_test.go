@@ -143,12 +143,25 @@ func verifyRocksDBStats(t *testing.T, s *kvserver.Store) {
 		{m.RdbFlushes, 1},
 		{m.RdbCompactions, 0},
 		{m.RdbTableReadersMemEstimate, 50},
+		{m.RdbWriteStalls, 0},
 	}
 	for _, tc := range testcases {
 		if a := tc.gauge.Value(); a < tc.min {
 			t.Errorf("gauge %s = %d < min %d", tc.gauge.GetName(), a, tc.min)
 		}
 	}
+
+	// The per-level SSTable counts add up to the total count.
+	var numSSTables int64
+	for _, g := range m.RdbLevelNumSSTables {
+		numSSTables += g.Value()
+	}
+	if e := m.RdbNumSSTables.Value(); numSSTables != e {
+		t.Errorf("per-level SSTable counts add up to %d, expected %d", numSSTables, e)
+	}
+	if r := m.RdbBlockCacheHitRate.Value(); r < 0 || r > 1 {
+		t.Errorf("block cache hit rate %f out of range", r)
+	}
 }
 
 // TestStoreResolveMetrics verifies that metrics related to intent resolution
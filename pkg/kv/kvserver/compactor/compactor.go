@@ -32,7 +32,9 @@ import (
 
 type storeCapacityFunc func() (roachpb.StoreCapacity, error)
 
-type doneCompactingFunc func(ctx context.Context)
+// doneCompactingFunc is invoked after each successful compaction with the time
+// at which the compaction started.
+type doneCompactingFunc func(ctx context.Context, start time.Time)
 
 // A Compactor records suggested compactions and periodically
 // makes requests to the engine to reclaim storage space.
@@ -381,7 +383,7 @@ func (c *Compactor) processCompaction(
 		duration := timeutil.Since(startTime)
 		c.Metrics.CompactingNanos.Inc(int64(duration))
 		if c.doneFn != nil {
-			c.doneFn(ctx)
+			c.doneFn(ctx, startTime)
 		}
 		log.Infof(ctx, "processed compaction %s in %.1fs", aggr, duration.Seconds())
 	} else {
@@ -91,7 +91,7 @@ func testSetup(capFn storeCapacityFunc) (*Compactor, *wrappedEngine, *int32, fun
 	eng := newWrappedEngine()
 	stopper.AddCloser(eng)
 	compactionCount := new(int32)
-	doneFn := func(_ context.Context) { atomic.AddInt32(compactionCount, 1) }
+	doneFn := func(_ context.Context, _ time.Time) { atomic.AddInt32(compactionCount, 1) }
 	st := cluster.MakeTestingClusterSettings()
 	compactor := NewCompactor(st, eng, capFn, doneFn)
 	compactor.Start(context.Background(), stopper)
@@ -617,7 +617,7 @@ func TestCompactorDeadlockOnStart(t *testing.T) {
 	capFn := func() (roachpb.StoreCapacity, error) {
 		return roachpb.StoreCapacity{}, errors.New("never called")
 	}
-	doneFn := func(_ context.Context) {}
+	doneFn := func(_ context.Context, _ time.Time) {}
 	st := cluster.MakeTestingClusterSettings()
 	compactor := NewCompactor(st, eng, capFn, doneFn)
 
@@ -651,7 +651,7 @@ func TestCompactorProcessingInitialization(t *testing.T) {
 	// Create a new fast compactor with a short wait time for processing,
 	// using the same engine so that it sees a non-empty queue.
 	stopper := stop.NewStopper()
-	doneFn := func(_ context.Context) { atomic.AddInt32(compactionCount, 1) }
+	doneFn := func(_ context.Context, _ time.Time) { atomic.AddInt32(compactionCount, 1) }
 	st := cluster.MakeTestingClusterSettings()
 	fastCompactor := NewCompactor(st, we, capacityFn, doneFn)
 	minInterval.Override(&fastCompactor.st.SV, time.Millisecond)
@@ -372,6 +372,10 @@ func (r *replicaGCer) send(ctx context.Context, req roachpb.GCRequest) error {
 	n := atomic.AddInt32(&r.count, 1)
 	log.Eventf(ctx, "sending batch %d (%d keys)", n, len(req.Keys))
 
+	// GC rewrites compete with foreground traffic for the disk, so they draw
+	// from the same per-store budget as snapshots and bulk ingestion.
+	limitBulkIOWrite(ctx, r.repl.store.limiters.BulkIOWriteRate, req.Size())
+
 	var ba roachpb.BatchRequest
 
 	// Technically not needed since we're talking directly to the Replica.
@@ -502,6 +506,7 @@ func updateStoreMetricsWithGCInfo(metrics *StoreMetrics, info gc.Info) {
 	metrics.GCAbortSpanGCNum.Inc(int64(info.AbortSpanGCNum))
 	metrics.GCPushTxn.Inc(int64(info.PushTxn))
 	metrics.GCResolveTotal.Inc(int64(info.ResolveTotal))
+	metrics.GCAffectedVersionsBytes.Inc(info.AffectedVersionsKeyBytes + info.AffectedVersionsValBytes)
 }
 
 // timer returns a constant duration to space out GC processing
@@ -366,6 +366,7 @@ func (ir *IntentResolver) MaybePushTransactions(
 			PushType:  pushType,
 		})
 	}
+	ir.Metrics.IntentResolverPushes.Inc(int64(len(pushTxns)))
 	err := ir.db.Run(ctx, b)
 	cleanupInFlightPushes()
 	if err != nil {
@@ -407,6 +407,7 @@ func TestCleanupMultipleIntentsAsync(t *testing.T) {
 	sort.Strings(reqs.resolved)
 	assert.Equal(t, []string{"a", "c"}, reqs.pushed)
 	assert.Equal(t, []string{"a", "b", "c", "d"}, reqs.resolved)
+	assert.Equal(t, int64(2), ir.Metrics.IntentResolverPushes.Count())
 }
 
 func repeat(f sendFunc, n int) []sendFunc {
@@ -20,17 +20,25 @@ var (
 		Measurement: "Intent Resolutions",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaIntentResolverPushes = metric.Metadata{
+		Name:        "intentresolver.pushes",
+		Help:        "Number of transactions pushed by the intent resolver on behalf of conflicting requests",
+		Measurement: "Pushes",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // Metrics contains the metrics for the IntentResolver.
 type Metrics struct {
 	// Intent resolver metrics.
 	IntentResolverAsyncThrottled *metric.Counter
+	IntentResolverPushes         *metric.Counter
 }
 
 func makeMetrics() Metrics {
 	// Intent resolver metrics.
 	return Metrics{
 		IntentResolverAsyncThrottled: metric.NewCounter(metaIntentResolverAsyncThrottled),
+		IntentResolverPushes:         metric.NewCounter(metaIntentResolverPushes),
 	}
 }
@@ -15,6 +15,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -93,12 +94,10 @@ func (s *Store) insertRangeLogEvent(
 
 // logSplit logs a range split event into the event table. The affected range is
 // the range which previously existed and is being split in half; the "other"
-// range is the new range which is being created.
-//
-// TODO(mrtracy): There are several different reasons that a range split
-// could occur, and that information should be logged.
+// range is the new range which is being created. The reason the split was
+// initiated is recorded in the event's details.
 func (s *Store) logSplit(
-	ctx context.Context, txn *kv.Txn, updatedDesc, newDesc roachpb.RangeDescriptor,
+	ctx context.Context, txn *kv.Txn, updatedDesc, newDesc roachpb.RangeDescriptor, reason string,
 ) error {
 	if !s.cfg.LogRangeEvents {
 		return nil
@@ -112,17 +111,16 @@ func (s *Store) logSplit(
 		Info: &storagepb.RangeLogEvent_Info{
 			UpdatedDesc: &updatedDesc,
 			NewDesc:     &newDesc,
+			Details:     reason,
 		},
 	})
 }
 
-// logMerge logs a range split event into the event table. The affected range is
-// the subsuming range; the "other" range is the subsumed range.
-//
-// TODO(benesch): There are several different reasons that a range merge
-// could occur, and that information should be logged.
+// logMerge logs a range merge event into the event table. The affected range is
+// the subsuming range; the "other" range is the subsumed range. The reason the
+// merge was initiated is recorded in the event's details.
 func (s *Store) logMerge(
-	ctx context.Context, txn *kv.Txn, updatedLHSDesc, rhsDesc roachpb.RangeDescriptor,
+	ctx context.Context, txn *kv.Txn, updatedLHSDesc, rhsDesc roachpb.RangeDescriptor, reason string,
 ) error {
 	if !s.cfg.LogRangeEvents {
 		return nil
@@ -136,6 +134,31 @@ func (s *Store) logMerge(
 		Info: &storagepb.RangeLogEvent_Info{
 			UpdatedDesc: &updatedLHSDesc,
 			RemovedDesc: &rhsDesc,
+			Details:     reason,
+		},
+	})
+}
+
+// logScrubCorruption logs the scrubber finding corrupt values in a replica
+// into the event table. A summary of the corruption is recorded in the event's
+// details. Unlike the other range events, it isn't part of a transaction.
+func (s *Store) logScrubCorruption(
+	ctx context.Context, desc roachpb.RangeDescriptor, details string,
+) error {
+	if !s.cfg.LogRangeEvents {
+		return nil
+	}
+	if !s.ClusterSettings().Version.IsActive(ctx, clusterversion.VersionScrubRangeLogEvent) {
+		return nil
+	}
+	return s.insertRangeLogEvent(ctx, nil /* txn */, storagepb.RangeLogEvent{
+		Timestamp: selectEventTimestamp(s, hlc.Timestamp{}),
+		RangeID:   desc.RangeID,
+		EventType: storagepb.RangeLogEventType_scrub_corruption,
+		StoreID:   s.StoreID(),
+		Info: &storagepb.RangeLogEvent_Info{
+			UpdatedDesc: &desc,
+			Details:     details,
 		},
 	})
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -74,6 +75,7 @@ func TestLogSplits(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	var manualSplits int
 	for rows.Next() {
 		var rangeID int64
 		var otherRangeID gosql.NullInt64
@@ -103,10 +105,20 @@ func TestLogSplits(t *testing.T) {
 		if int64(info.NewDesc.RangeID) != otherRangeID.Int64 {
 			t.Errorf("recorded wrong new descriptor %s for split of range %d", info.NewDesc, rangeID)
 		}
+		if info.Details == "" {
+			t.Errorf("reason not recorded for split of range %d", rangeID)
+		}
+		if info.Details == "manual" {
+			manualSplits++
+		}
 	}
 	if rows.Err() != nil {
 		t.Fatal(rows.Err())
 	}
+	// The explicit split above is logged with its reason.
+	if manualSplits == 0 {
+		t.Error("no manual split recorded")
+	}
 
 	store, pErr := ts.Stores().GetStore(ts.GetFirstStoreID())
 	if pErr != nil {
@@ -393,3 +405,59 @@ func TestLogRebalances(t *testing.T) {
 		t.Errorf("expected %d RemoveReplica events logged, found %d", e, a)
 	}
 }
+
+// TestLogScrubCorruption verifies that the scrubber records the corrupt values
+// it finds in the range log.
+func TestLogScrubCorruption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	ctx := context.Background()
+	defer s.Stopper().Stop(ctx)
+
+	store, err := s.(*server.TestServer).Stores().GetStore(s.GetFirstStoreID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write a value whose checksum doesn't match directly to the engine.
+	key := roachpb.Key("a")
+	value := roachpb.MakeValueFromString("value")
+	value.InitChecksum(key)
+	value.RawBytes[len(value.RawBytes)-1]++
+	if err := store.Engine().Put(
+		storage.MVCCKey{Key: key, Timestamp: s.Clock().Now()}, value.RawBytes,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(`SET CLUSTER SETTING kv.scrub.interval = '1h'`); err != nil {
+		t.Fatal(err)
+	}
+	repl := store.LookupReplica(roachpb.RKey(key))
+	if _, processErr, err := store.ManuallyEnqueue(ctx, "scrubber", repl, true /* skipShouldQueue */); err != nil {
+		t.Fatal(err)
+	} else if processErr != "" {
+		t.Fatal(processErr)
+	}
+	if store.Metrics().ScrubCorruptions.Count() == 0 {
+		t.Fatal("expected the scrubber to find the corrupt value")
+	}
+
+	var infoStr string
+	if err := db.QueryRowContext(ctx,
+		`SELECT info FROM system.rangelog WHERE "eventType" = $1 AND "rangeID" = $2`,
+		storagepb.RangeLogEventType_scrub_corruption.String(), repl.RangeID,
+	).Scan(&infoStr); err != nil {
+		t.Fatal(err)
+	}
+	var info storagepb.RangeLogEvent_Info
+	if err := json.Unmarshal([]byte(infoStr), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.UpdatedDesc.RangeID != repl.RangeID {
+		t.Errorf("recorded wrong descriptor %s for scrub of range %d", info.UpdatedDesc, repl.RangeID)
+	}
+	if info.Details == "" {
+		t.Errorf("details not recorded for scrub of range %d", repl.RangeID)
+	}
+}
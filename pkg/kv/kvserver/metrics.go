@@ -12,6 +12,7 @@ package kvserver
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
@@ -23,6 +24,10 @@ import (
 	"go.etcd.io/etcd/raft/raftpb"
 )
 
+// rdbNumLevels is the number of levels of the LSM tree of both storage
+// engines, for which per-level metrics are exported.
+const rdbNumLevels = 7
+
 var (
 	// Replica metrics.
 	metaReplicaCount = metric.Metadata{
@@ -392,6 +397,18 @@ var (
 		Measurement: "Storage",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaRdbBlockCacheHitRate = metric.Metadata{
+		Name:        "rocksdb.block.cache.hit-rate",
+		Help:        "Ratio of block cache hits to block cache lookups since the previous sample",
+		Measurement: "Hit Rate",
+		Unit:        metric.Unit_PERCENT,
+	}
+	metaRdbWriteStalls = metric.Metadata{
+		Name:        "rocksdb.write-stalls",
+		Help:        "Number of times writes were delayed or stopped to let flushes and compactions catch up",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
 
 	// Range event metrics.
 	metaRangeSplits = metric.Metadata{
@@ -726,6 +743,42 @@ var (
 		Measurement: "Processing Time",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaScrubQueueSuccesses = metric.Metadata{
+		Name:        "queue.scrub.process.success",
+		Help:        "Number of replicas successfully processed by the scrubber queue",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaScrubQueueFailures = metric.Metadata{
+		Name:        "queue.scrub.process.failure",
+		Help:        "Number of replicas which failed processing in the scrubber queue",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaScrubQueuePending = metric.Metadata{
+		Name:        "queue.scrub.pending",
+		Help:        "Number of pending replicas in the scrubber queue",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaScrubQueueProcessingNanos = metric.Metadata{
+		Name:        "queue.scrub.processingnanos",
+		Help:        "Nanoseconds spent processing replicas in the scrubber queue",
+		Measurement: "Processing Time",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaScrubKeys = metric.Metadata{
+		Name:        "queue.scrub.keys",
+		Help:        "Number of keys whose value checksums were verified by the scrubber queue",
+		Measurement: "Keys",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaScrubCorruptions = metric.Metadata{
+		Name:        "queue.scrub.corruptions",
+		Help:        "Number of corrupt values found by the scrubber queue",
+		Measurement: "Keys",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaReplicaGCQueueSuccesses = metric.Metadata{
 		Name:        "queue.replicagc.process.success",
 		Help:        "Number of replicas successfully processed by the replica GC queue",
@@ -920,6 +973,12 @@ var (
 		Measurement: "Intent Resolutions",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaGCAffectedVersionsBytes = metric.Metadata{
+		Name:        "queue.gc.info.affectedversionsbytes",
+		Help:        "Number of key and value bytes of MVCC versions older than the GC TTL",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
 
 	// Slow request metrics.
 	metaLatchRequests = metric.Metadata{
@@ -1075,6 +1134,12 @@ type StoreMetrics struct {
 	RdbReadAmplification        *metric.Gauge
 	RdbNumSSTables              *metric.Gauge
 	RdbPendingCompaction        *metric.Gauge
+	RdbBlockCacheHitRate        *metric.GaugeFloat64
+	RdbWriteStalls              *metric.Gauge
+	// The per-level metrics are registered explicitly, as AddMetricStruct
+	// skips arrays.
+	RdbLevelNumSSTables  [rdbNumLevels]*metric.Gauge
+	RdbLevelSSTablesSize [rdbNumLevels]*metric.Gauge
 
 	// TODO(mrtracy): This should be removed as part of #4465. This is only
 	// maintained to keep the current structure of NodeStatus; it would be
@@ -1151,6 +1216,12 @@ type StoreMetrics struct {
 	ConsistencyQueueFailures                  *metric.Counter
 	ConsistencyQueuePending                   *metric.Gauge
 	ConsistencyQueueProcessingNanos           *metric.Counter
+	ScrubQueueSuccesses                       *metric.Counter
+	ScrubQueueFailures                        *metric.Counter
+	ScrubQueuePending                         *metric.Gauge
+	ScrubQueueProcessingNanos                 *metric.Counter
+	ScrubKeys                                 *metric.Counter
+	ScrubCorruptions                          *metric.Counter
 	ReplicaGCQueueSuccesses                   *metric.Counter
 	ReplicaGCQueueFailures                    *metric.Counter
 	ReplicaGCQueuePending                     *metric.Gauge
@@ -1185,6 +1256,7 @@ type StoreMetrics struct {
 	GCPushTxn                    *metric.Counter
 	GCResolveTotal               *metric.Counter
 	GCResolveSuccess             *metric.Counter
+	GCAffectedVersionsBytes      *metric.Counter
 
 	// Slow request counts.
 	SlowLatchRequests *metric.Gauge
@@ -1290,6 +1362,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RdbReadAmplification:        metric.NewGauge(metaRdbReadAmplification),
 		RdbNumSSTables:              metric.NewGauge(metaRdbNumSSTables),
 		RdbPendingCompaction:        metric.NewGauge(metaRdbPendingCompaction),
+		RdbBlockCacheHitRate:        metric.NewGaugeFloat64(metaRdbBlockCacheHitRate),
+		RdbWriteStalls:              metric.NewGauge(metaRdbWriteStalls),
 
 		// Range event metrics.
 		RangeSplits:                  metric.NewCounter(metaRangeSplits),
@@ -1358,6 +1432,12 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		ConsistencyQueueFailures:                  metric.NewCounter(metaConsistencyQueueFailures),
 		ConsistencyQueuePending:                   metric.NewGauge(metaConsistencyQueuePending),
 		ConsistencyQueueProcessingNanos:           metric.NewCounter(metaConsistencyQueueProcessingNanos),
+		ScrubQueueSuccesses:                       metric.NewCounter(metaScrubQueueSuccesses),
+		ScrubQueueFailures:                        metric.NewCounter(metaScrubQueueFailures),
+		ScrubQueuePending:                         metric.NewGauge(metaScrubQueuePending),
+		ScrubQueueProcessingNanos:                 metric.NewCounter(metaScrubQueueProcessingNanos),
+		ScrubKeys:                                 metric.NewCounter(metaScrubKeys),
+		ScrubCorruptions:                          metric.NewCounter(metaScrubCorruptions),
 		ReplicaGCQueueSuccesses:                   metric.NewCounter(metaReplicaGCQueueSuccesses),
 		ReplicaGCQueueFailures:                    metric.NewCounter(metaReplicaGCQueueFailures),
 		ReplicaGCQueuePending:                     metric.NewGauge(metaReplicaGCQueuePending),
@@ -1392,6 +1472,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		GCPushTxn:                    metric.NewCounter(metaGCPushTxn),
 		GCResolveTotal:               metric.NewCounter(metaGCResolveTotal),
 		GCResolveSuccess:             metric.NewCounter(metaGCResolveSuccess),
+		GCAffectedVersionsBytes:      metric.NewCounter(metaGCAffectedVersionsBytes),
 
 		// Wedge request counters.
 		SlowLatchRequests: metric.NewGauge(metaLatchRequests),
@@ -1431,6 +1512,22 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 	sm.raftRcvdMessages[raftpb.MsgTransferLeader] = sm.RaftRcvdMsgTransferLeader
 	sm.raftRcvdMessages[raftpb.MsgTimeoutNow] = sm.RaftRcvdMsgTimeoutNow
 
+	for level := 0; level < rdbNumLevels; level++ {
+		sm.RdbLevelNumSSTables[level] = metric.NewGauge(metric.Metadata{
+			Name:        fmt.Sprintf("rocksdb.num-sstables.l%d", level),
+			Help:        fmt.Sprintf("Number of rocksdb SSTables in level %d", level),
+			Measurement: "SSTables",
+			Unit:        metric.Unit_COUNT,
+		})
+		sm.RdbLevelSSTablesSize[level] = metric.NewGauge(metric.Metadata{
+			Name:        fmt.Sprintf("rocksdb.sstables-size.l%d", level),
+			Help:        fmt.Sprintf("Total size of the rocksdb SSTables in level %d", level),
+			Measurement: "Storage",
+			Unit:        metric.Unit_BYTES,
+		})
+		storeRegistry.AddMetric(sm.RdbLevelNumSSTables[level])
+		storeRegistry.AddMetric(sm.RdbLevelSSTablesSize[level])
+	}
 	storeRegistry.AddMetricStruct(sm)
 
 	return sm
@@ -1471,6 +1568,11 @@ func (sm *StoreMetrics) updateRocksDBStats(stats storage.Stats) {
 	// We do not grab a lock here, because it's not possible to get a point-in-
 	// time snapshot of RocksDB stats. Retrieving RocksDB stats doesn't grab any
 	// locks, and there's no way to retrieve multiple stats in a single operation.
+	hits := stats.BlockCacheHits - sm.RdbBlockCacheHits.Value()
+	lookups := hits + stats.BlockCacheMisses - sm.RdbBlockCacheMisses.Value()
+	if lookups > 0 {
+		sm.RdbBlockCacheHitRate.Update(float64(hits) / float64(lookups))
+	}
 	sm.RdbBlockCacheHits.Update(stats.BlockCacheHits)
 	sm.RdbBlockCacheMisses.Update(stats.BlockCacheMisses)
 	sm.RdbBlockCacheUsage.Update(stats.BlockCacheUsage)
@@ -1485,6 +1587,26 @@ func (sm *StoreMetrics) updateRocksDBStats(stats storage.Stats) {
 	sm.RdbCompactedBytesRead.Update(stats.CompactedBytesRead)
 	sm.RdbCompactedBytesWritten.Update(stats.CompactedBytesWritten)
 	sm.RdbTableReadersMemEstimate.Update(stats.TableReadersMemEstimate)
+	sm.RdbPendingCompaction.Update(stats.PendingCompactionBytesEstimate)
+	sm.RdbWriteStalls.Update(stats.WriteStalls)
+}
+
+// updateSSTablesStats updates the per-level SSTable metrics. Tables in levels
+// beyond the last tracked level, if any, are accounted for in it.
+func (sm *StoreMetrics) updateSSTablesStats(sstables storage.SSTableInfos) {
+	var counts, sizes [rdbNumLevels]int64
+	for _, t := range sstables {
+		level := t.Level
+		if level >= rdbNumLevels {
+			level = rdbNumLevels - 1
+		}
+		counts[level]++
+		sizes[level] += t.Size
+	}
+	for level := 0; level < rdbNumLevels; level++ {
+		sm.RdbLevelNumSSTables[level].Update(counts[level])
+		sm.RdbLevelSSTablesSize[level].Update(sizes[level])
+	}
 }
 
 func (sm *StoreMetrics) updateEnvStats(stats storage.EnvStats) {
@@ -970,6 +970,19 @@ func (nl *NodeLiveness) livenessGossipUpdate(key string, content roachpb.Value)
 		log.Error(context.TODO(), err)
 		return
 	}
+	// Ignore records which are gossiped under another node's key; accepting
+	// them would let a single bad record clobber the liveness of an unrelated
+	// node.
+	nodeID, err := gossip.NodeIDFromKey(key, gossip.KeyNodeLivenessPrefix)
+	if err != nil {
+		log.Error(context.TODO(), err)
+		return
+	}
+	if nodeID != liveness.NodeID {
+		log.Errorf(context.TODO(), "ignoring liveness record for n%d gossiped under key %q",
+			liveness.NodeID, key)
+		return
+	}
 
 	nl.maybeUpdate(liveness)
 }
@@ -13,6 +13,7 @@ package kvserver_test
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 	"sync/atomic"
@@ -383,6 +384,44 @@ func TestNodeLivenessRestart(t *testing.T) {
 	})
 }
 
+// TestNodeLivenessIgnoresMismatchedKey verifies that a liveness record
+// gossiped under another node's liveness key is not applied.
+func TestNodeLivenessIgnoresMismatchedKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	mtc := &multiTestContext{}
+	defer mtc.Stop()
+	mtc.Start(t, 1)
+	g := mtc.gossips[0]
+
+	const bogusNodeID = roachpb.NodeID(5)
+	key := gossip.MakeNodeLivenessKey(g.NodeID.Get())
+	// Callbacks are run in registration order, so once this callback has
+	// fired the node liveness callback has processed the record as well.
+	processed := make(chan struct{}, 1)
+	g.RegisterCallback(key, func(_ string, val roachpb.Value) {
+		var l storagepb.Liveness
+		if err := val.GetProto(&l); err == nil && l.NodeID == bogusNodeID {
+			select {
+			case processed <- struct{}{}:
+			default:
+			}
+		}
+	})
+	bogus := storagepb.Liveness{
+		NodeID:     bogusNodeID,
+		Epoch:      1,
+		Expiration: hlc.LegacyTimestamp{WallTime: math.MaxInt64},
+	}
+	if err := g.AddInfoProto(key, &bogus, 0); err != nil {
+		t.Fatal(err)
+	}
+	<-processed
+
+	if l, err := mtc.nodeLivenesses[0].GetLiveness(bogusNodeID); err != kvserver.ErrNoLivenessRecord {
+		t.Fatalf("expected no liveness record for n%d; got %+v, %v", bogusNodeID, l, err)
+	}
+}
+
 // TestNodeLivenessSelf verifies that a node keeps its own most recent liveness
 // heartbeat info in preference to anything which might be received belatedly
 // through gossip.
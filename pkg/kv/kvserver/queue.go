@@ -311,7 +311,7 @@ type queueConfig struct {
 	// processDestroyedReplicas controls whether or not we want to process replicas
 	// that have been destroyed but not GCed.
 	processDestroyedReplicas bool
-	// processTimeout returns the timeout for processing a replica.
+	// processTimeoutFunc returns the timeout for processing a replica.
 	processTimeoutFunc queueProcessTimeoutFunc
 	// successes is a counter of replicas processed successfully.
 	successes *metric.Counter
@@ -731,7 +731,10 @@ func (bq *baseQueue) addInternal(
 	// If adding this replica has pushed the queue past its maximum size,
 	// remove the lowest priority element.
 	if pqLen := bq.mu.priorityQ.Len(); pqLen > bq.maxSize {
-		bq.removeLocked(bq.mu.priorityQ.sl[pqLen-1])
+		dropped := bq.mu.priorityQ.sl[pqLen-1]
+		log.VEventf(ctx, 1, "queue full; dropping r%d with priority %0.3f",
+			dropped.rangeID, dropped.priority)
+		bq.removeLocked(dropped)
 	}
 	// Signal the processLoop that a replica has been added.
 	select {
@@ -132,6 +132,9 @@ func (s *Store) setRaftSnapshotQueueActive(active bool) {
 func (s *Store) setConsistencyQueueActive(active bool) {
 	s.consistencyQueue.SetDisabled(!active)
 }
+func (s *Store) setScrubQueueActive(active bool) {
+	s.scrubQueue.SetDisabled(!active)
+}
 func (s *Store) setScannerActive(active bool) {
 	s.scanner.SetDisabled(!active)
 }
@@ -236,6 +236,15 @@ type Replica struct {
 	// centerpiece of transaction contention handling.
 	concMgr concurrency.Manager
 
+	// lastScrubbed is the time at which the scrub queue last verified the
+	// checksums of this replica's data. Unlike the last processed times of
+	// the other queues, it isn't replicated, as each replica of a range is
+	// scrubbed independently of the others. It is thus reset by a restart.
+	lastScrubbed struct {
+		syncutil.Mutex
+		ts hlc.Timestamp
+	}
+
 	mu struct {
 		// Protects all fields in the mu struct.
 		syncutil.RWMutex
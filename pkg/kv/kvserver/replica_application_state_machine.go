@@ -823,9 +823,11 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// before ensuring that the replica's data has been synchronously removed.
 	// See handleChangeReplicasResult().
 	sync := b.changeRemovesReplica
+	commitStart := timeutil.Now()
 	if err := b.batch.Commit(sync); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to commit Raft entry batch")
 	}
+	r.store.maybeRecordSlowStorageOp(ctx, slowOpApplyCommit, commitStart)
 	b.batch.Close()
 	b.batch = nil
 
@@ -169,6 +169,7 @@ func splitTxnAttempt(
 	splitKey roachpb.RKey,
 	expiration hlc.Timestamp,
 	oldDesc *roachpb.RangeDescriptor,
+	reason string,
 ) error {
 	txn.SetDebugName(splitTxnName)
 
@@ -206,7 +207,7 @@ func splitTxnAttempt(
 	}
 
 	// Log the split into the range event log.
-	if err := store.logSplit(ctx, txn, *leftDesc, *rightDesc); err != nil {
+	if err := store.logSplit(ctx, txn, *leftDesc, *rightDesc, reason); err != nil {
 		return err
 	}
 
@@ -398,7 +399,7 @@ func (r *Replica) adminSplitWithDescriptor(
 		splitKey.StringWithDirs(nil /* valDirs */, 50 /* maxLen */), rightRangeID, reason, extra)
 
 	if err := r.store.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-		return splitTxnAttempt(ctx, r.store, txn, rightRangeID, splitKey, args.ExpirationTime, desc)
+		return splitTxnAttempt(ctx, r.store, txn, rightRangeID, splitKey, args.ExpirationTime, desc, reason)
 	}); err != nil {
 		// The ConditionFailedError can occur because the descriptors acting
 		// as expected values in the CPuts used to update the left or right
@@ -687,7 +688,7 @@ func (r *Replica) AdminMerge(
 		// instead of a transaction; there's no reason this logging
 		// shouldn't be done in parallel via the batch with the updated
 		// range addressing.
-		if err := r.store.logMerge(ctx, txn, updatedLeftDesc, rightDesc); err != nil {
+		if err := r.store.logMerge(ctx, txn, updatedLeftDesc, rightDesc, reason); err != nil {
 			return err
 		}
 
@@ -1880,7 +1881,7 @@ func (r *Replica) sendSnapshot(
 	); err != nil {
 		if errors.Cause(err) == errMalformedSnapshot {
 			tag := fmt.Sprintf("r%d_%s", r.RangeID, snap.SnapUUID.Short())
-			if dir, err := r.store.checkpoint(ctx, tag); err != nil {
+			if dir, err := r.store.Checkpoint(ctx, tag); err != nil {
 				log.Warningf(ctx, "unable to create checkpoint %s: %+v", dir, err)
 			} else {
 				log.Warningf(ctx, "created checkpoint %s", dir)
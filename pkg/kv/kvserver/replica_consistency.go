@@ -22,6 +22,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
@@ -78,6 +79,23 @@ type ReplicaChecksum struct {
 	notify chan struct{}
 }
 
+// checksumVersion returns the version of the checksums requested by the
+// consistency checks of the range. The versions shadowed below the GC
+// threshold are excluded while the compactions may drop them, and as long as
+// the range stats are marked as estimates since they last could: the
+// versions dropped on some of the replicas before the compactions stopped
+// dropping them are only removed from the others by the next GC.
+func (r *Replica) checksumVersion(ctx context.Context) uint32 {
+	st := r.ClusterSettings()
+	if !st.Version.IsActive(ctx, clusterversion.VersionCompactionGCFilter) {
+		return batcheval.ReplicaChecksumVersion
+	}
+	if storage.CompactionGCEnabled.Get(&st.SV) || r.GetMVCCStats().ContainsEstimates != 0 {
+		return batcheval.ReplicaChecksumVersionGCShadowed
+	}
+	return batcheval.ReplicaChecksumVersion
+}
+
 // CheckConsistency runs a consistency check on the range. It first applies a
 // ComputeChecksum through Raft and then issues CollectChecksum commands to the
 // other replicas. These are inspected and a CheckConsistencyResponse is assembled.
@@ -93,7 +111,7 @@ func (r *Replica) CheckConsistency(
 
 	checkArgs := roachpb.ComputeChecksumRequest{
 		RequestHeader: roachpb.RequestHeader{Key: startKey},
-		Version:       batcheval.ReplicaChecksumVersion,
+		Version:       r.checksumVersion(ctx),
 		Snapshot:      args.WithDiff,
 		Mode:          args.Mode,
 		Checkpoint:    args.Checkpoint,
@@ -498,13 +516,17 @@ type replicaHash struct {
 }
 
 // sha512 computes the SHA512 hash of all the replica data at the snapshot.
-// It will dump all the kv data into snapshot if it is provided.
+// It will dump all the kv data into snapshot if it is provided. If
+// excludeGCShadowed is set, the versions of the user keys which are shadowed
+// below the GC threshold are excluded, as they may have been dropped by the
+// compactions of some of the replicas only.
 func (r *Replica) sha512(
 	ctx context.Context,
 	desc roachpb.RangeDescriptor,
 	snap storage.Reader,
 	snapshot *roachpb.RaftSnapshotData,
 	mode roachpb.ChecksumMode,
+	excludeGCShadowed bool,
 ) (*replicaHash, error) {
 	statsOnly := mode == roachpb.ChecksumMode_CHECK_STATS
 
@@ -518,7 +540,24 @@ func (r *Replica) sha512(
 	var timestampBuf []byte
 	hasher := sha512.New()
 
+	userKeys := rditer.MakeUserKeyRange(&desc)
+	var gcThreshold *hlc.Timestamp
+	if excludeGCShadowed {
+		var err error
+		if gcThreshold, err = stateloader.Make(desc.RangeID).LoadGCThreshold(ctx, snap); err != nil {
+			return nil, err
+		}
+	}
+	var gcShadows *storage.GCShadowTracker
+
 	visitor := func(unsafeKey storage.MVCCKey, unsafeValue []byte) error {
+		if gcShadows != nil {
+			if shadowed, err := gcShadows.Shadowed(unsafeKey, unsafeValue); err != nil {
+				return err
+			} else if shadowed {
+				return nil
+			}
+		}
 		if snapshot != nil {
 			// Add (a copy of) the kv pair into the debug message.
 			kv := roachpb.RaftSnapshotData_KeyValue{
@@ -562,6 +601,11 @@ func (r *Replica) sha512(
 	// all of the replicated key space.
 	if !statsOnly {
 		for _, span := range rditer.MakeReplicatedKeyRanges(&desc) {
+			gcShadows = nil
+			if excludeGCShadowed && span.Start.Equal(userKeys.Start) {
+				tracker := storage.MakeGCShadowTracker(*gcThreshold)
+				gcShadows = &tracker
+			}
 			spanMS, err := storage.ComputeStatsGo(
 				iter, span.Start.Key, span.End.Key, 0 /* nowNanos */, visitor,
 			)
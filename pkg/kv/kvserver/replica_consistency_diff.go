@@ -23,7 +23,7 @@ import (
 // ReplicaSnapshotDiff is a part of a []ReplicaSnapshotDiff which represents a diff between
 // two replica snapshots. For now it's only a diff between their KV pairs.
 type ReplicaSnapshotDiff struct {
-	// LeaseHolder is set to true of this kv pair is only present on the lease
+	// LeaseHolder is set to true if this kv pair is only present on the lease
 	// holder.
 	LeaseHolder bool
 	Key         roachpb.Key
@@ -44,7 +44,7 @@ func (rsds ReplicaSnapshotDiffSlice) WriteTo(w io.Writer) (int64, error) {
 	for _, d := range rsds {
 		prefix := "+"
 		if d.LeaseHolder {
-			// Lease holder (RHS) has something follower (LHS) does not have.
+			// Lease holder (LHS) has something follower (RHS) does not have.
 			prefix = "-"
 		}
 		ts := d.Timestamp
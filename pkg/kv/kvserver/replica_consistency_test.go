@@ -12,11 +12,15 @@ package kvserver
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -33,26 +37,64 @@ func TestReplicaChecksumVersion(t *testing.T) {
 	defer stopper.Stop(ctx)
 	tc.Start(t, stopper)
 
-	testutils.RunTrueAndFalse(t, "matchingVersion", func(t *testing.T, matchingVersion bool) {
-		cc := storagepb.ComputeChecksum{
-			ChecksumID: uuid.FastMakeV4(),
-			Mode:       roachpb.ChecksumMode_CHECK_FULL,
-		}
-		if matchingVersion {
-			cc.Version = batcheval.ReplicaChecksumVersion
-		} else {
-			cc.Version = 1
-		}
-		tc.repl.computeChecksumPostApply(ctx, cc)
-		rc, err := tc.repl.getChecksum(ctx, cc.ChecksumID)
-		if !matchingVersion {
-			if !testutils.IsError(err, "no checksum found") {
-				t.Fatal(err)
+	for _, version := range []uint32{
+		1, batcheval.ReplicaChecksumVersion, batcheval.ReplicaChecksumVersionGCShadowed,
+	} {
+		t.Run(fmt.Sprintf("version=%d", version), func(t *testing.T) {
+			cc := storagepb.ComputeChecksum{
+				ChecksumID: uuid.FastMakeV4(),
+				Mode:       roachpb.ChecksumMode_CHECK_FULL,
+				Version:    version,
 			}
-			require.Nil(t, rc.Checksum)
-		} else {
-			require.NoError(t, err)
-			require.NotNil(t, rc.Checksum)
-		}
+			tc.repl.computeChecksumPostApply(ctx, cc)
+			rc, err := tc.repl.getChecksum(ctx, cc.ChecksumID)
+			if version == 1 {
+				if !testutils.IsError(err, "no checksum found") {
+					t.Fatal(err)
+				}
+				require.Nil(t, rc.Checksum)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, rc.Checksum)
+			}
+		})
+	}
+}
+
+// TestReplicaChecksumVersionGCShadowed verifies that the checksums exclude
+// the versions shadowed below the GC threshold only once all of the nodes
+// support it, and while the compactions may drop them.
+func TestReplicaChecksumVersionGCShadowed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+
+	t.Run("pre-VersionCompactionGCFilter", func(t *testing.T) {
+		stopper := stop.NewStopper()
+		defer stopper.Stop(ctx)
+		cfg := TestStoreConfig(nil)
+		version := clusterversion.VersionByKey(clusterversion.VersionCompactionGCFilter - 1)
+		cfg.Settings = cluster.MakeTestingClusterSettingsWithVersions(version, version, false /* initializeVersion */)
+		var tc testContext
+		tc.StartWithStoreConfigAndVersion(t, stopper, cfg, version)
+
+		storage.CompactionGCEnabled.Override(&cfg.Settings.SV, true)
+		require.False(t, storage.CompactionGCActive(ctx, cfg.Settings))
+		require.Equal(t, uint32(batcheval.ReplicaChecksumVersion), tc.repl.checksumVersion(ctx))
+	})
+
+	t.Run("VersionCompactionGCFilter", func(t *testing.T) {
+		stopper := stop.NewStopper()
+		defer stopper.Stop(ctx)
+		var tc testContext
+		tc.Start(t, stopper)
+		st := tc.store.ClusterSettings()
+
+		require.Zero(t, tc.repl.GetMVCCStats().ContainsEstimates)
+		storage.CompactionGCEnabled.Override(&st.SV, false)
+		require.Equal(t, uint32(batcheval.ReplicaChecksumVersion), tc.repl.checksumVersion(ctx))
+		storage.CompactionGCEnabled.Override(&st.SV, true)
+		require.True(t, storage.CompactionGCActive(ctx, st))
+		require.Equal(t, uint32(batcheval.ReplicaChecksumVersionGCShadowed), tc.repl.checksumVersion(ctx))
 	})
 }
@@ -311,5 +311,8 @@ func (r *Replica) setDescLockedRaftMuLocked(ctx context.Context, desc *roachpb.R
 	r.rangeStr.store(r.mu.replicaID, desc)
 	r.connectionClass.set(rpc.ConnectionClassForKey(desc.StartKey))
 	r.concMgr.OnRangeDescUpdated(desc)
+	if r.mu.state.Desc.GetGeneration() != desc.GetGeneration() {
+		r.store.invalidateCompactionGCThreshold(ctx, r.RangeID, desc)
+	}
 	r.mu.state.Desc = desc
 }
@@ -202,12 +202,14 @@ func (r *Replica) computeChecksumPostApply(ctx context.Context, cc storagepb.Com
 	desc := *r.mu.state.Desc
 	r.mu.Unlock()
 
-	if cc.Version != batcheval.ReplicaChecksumVersion {
+	if cc.Version != batcheval.ReplicaChecksumVersion &&
+		cc.Version != batcheval.ReplicaChecksumVersionGCShadowed {
 		r.computeChecksumDone(ctx, cc.ChecksumID, nil, nil)
-		log.Infof(ctx, "incompatible ComputeChecksum versions (requested: %d, have: %d)",
-			cc.Version, batcheval.ReplicaChecksumVersion)
+		log.Infof(ctx, "incompatible ComputeChecksum versions (requested: %d, have: %d and %d)",
+			cc.Version, batcheval.ReplicaChecksumVersion, batcheval.ReplicaChecksumVersionGCShadowed)
 		return
 	}
+	excludeGCShadowed := cc.Version == batcheval.ReplicaChecksumVersionGCShadowed
 
 	// Caller is holding raftMu, so an engine snapshot is automatically
 	// Raft-consistent (i.e. not in the middle of an AddSSTable).
@@ -220,7 +222,7 @@ func (r *Replica) computeChecksumPostApply(ctx context.Context, cc storagepb.Com
 		}
 		// NB: the names here will match on all nodes, which is nice for debugging.
 		tag := fmt.Sprintf("r%d_at_%d", r.RangeID, rai)
-		if dir, err := r.store.checkpoint(ctx, tag); err != nil {
+		if dir, err := r.store.Checkpoint(ctx, tag); err != nil {
 			log.Warningf(ctx, "unable to create checkpoint %s: %+v", dir, err)
 		} else {
 			log.Warningf(ctx, "created checkpoint %s", dir)
@@ -235,7 +237,7 @@ func (r *Replica) computeChecksumPostApply(ctx context.Context, cc storagepb.Com
 			if cc.SaveSnapshot {
 				snapshot = &roachpb.RaftSnapshotData{}
 			}
-			result, err := r.sha512(ctx, desc, snap, snapshot, cc.Mode)
+			result, err := r.sha512(ctx, desc, snap, snapshot, cc.Mode, excludeGCShadowed)
 			if err != nil {
 				log.Errorf(ctx, "%v", err)
 				result = nil
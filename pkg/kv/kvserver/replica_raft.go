@@ -682,6 +682,9 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	if rd.MustSync {
 		elapsed := timeutil.Since(commitStart)
 		r.store.metrics.RaftLogCommitLatency.RecordValue(elapsed.Nanoseconds())
+		r.store.maybeRecordSlowStorageOp(ctx, slowOpRaftLogSync, commitStart)
+	} else {
+		r.store.maybeRecordSlowStorageOp(ctx, slowOpRaftLogCommit, commitStart)
 	}
 
 	if len(rd.Entries) > 0 {
@@ -878,7 +878,7 @@ func (r *Replica) applySnapshot(
 		}
 
 		tag := fmt.Sprintf("r%d_%s", r.RangeID, inSnap.SnapUUID.String())
-		dir, err := r.store.checkpoint(ctx, tag)
+		dir, err := r.store.Checkpoint(ctx, tag)
 		if err != nil {
 			log.Warningf(ctx, "unable to create checkpoint %s: %+v", dir, err)
 		} else {
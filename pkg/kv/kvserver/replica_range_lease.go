@@ -834,17 +834,16 @@ func (r *Replica) leaseGoodToGo(ctx context.Context) (storagepb.LeaseStatus, boo
 // current timestamp. If it does, returns the lease and its status. If
 // another replica currently holds the lease, redirects by returning
 // NotLeaseHolderError. If the lease is expired, a renewal is synchronously
-// requested. Leases are eagerly renewed when a request with a timestamp
-// within rangeLeaseRenewalDuration of the lease expiration is served.
+// requested. Expiration-based leases are eagerly (and asynchronously) renewed
+// when a request with a timestamp within RaftConfig.RangeLeaseRenewalDuration
+// of the lease expiration is served. Epoch-based leases are instead extended
+// through the heartbeats of the node liveness record.
 //
 // TODO(spencer): for write commands, don't wait while requesting
 //  the range lease. If the lease acquisition fails, the write cmd
 //  will fail as well. If it succeeds, as is likely, then the write
 //  will not incur latency waiting for the command to complete.
 //  Reads, however, must wait.
-//
-// TODO(rangeLeaseRenewalDuration): what is rangeLeaseRenewalDuration
-//  referring to? It appears to have rotted.
 func (r *Replica) redirectOnOrAcquireLease(
 	ctx context.Context,
 ) (storagepb.LeaseStatus, *roachpb.Error) {
@@ -0,0 +1,211 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+var scrubInterval = settings.RegisterNonNegativeDurationSetting(
+	"kv.scrub.interval",
+	"the time between verifications of the value checksums of each replica stored"+
+		" on a node; set to 0 to disable scrubbing",
+	0,
+)
+
+var scrubRate = settings.RegisterValidatedByteSizeSetting(
+	"kv.scrub.max_rate",
+	"the rate limit (bytes/sec) at which each store reads replica data when scrubbing",
+	8<<20,
+	validatePositive,
+)
+
+var scrubQuarantine = settings.RegisterBoolSetting(
+	"kv.scrub.quarantine.enabled",
+	"if set, a replica in which the scrubber finds a corrupt value is marked as corrupt,"+
+		" which terminates the node",
+	false,
+)
+
+const (
+	// scrubBatchSize is the number of bytes read in between two waits on the
+	// scrubber's rate limiter.
+	scrubBatchSize = 256 << 10 // 256 KB
+	// scrubMaxReportedKeys bounds the number of corrupt keys logged for a
+	// single replica.
+	scrubMaxReportedKeys = 10
+)
+
+// scrubQueue is a low-priority queue which verifies the checksums of the
+// values of the replicas on a store, independently of the other replicas of
+// each range. Unlike the consistency checker, which compares the replicas
+// of a range against each other, it detects the corruption of a store's
+// local copy of the data, such as bit rot on disk.
+type scrubQueue struct {
+	*baseQueue
+	interval       func() time.Duration
+	replicaCountFn func() int
+}
+
+// newScrubQueue returns a new instance of scrubQueue.
+func newScrubQueue(store *Store, gossip *gossip.Gossip) *scrubQueue {
+	q := &scrubQueue{
+		interval: func() time.Duration {
+			return scrubInterval.Get(&store.ClusterSettings().SV)
+		},
+		replicaCountFn: store.ReplicaCount,
+	}
+	q.baseQueue = newBaseQueue(
+		"scrubber", q, store, gossip,
+		queueConfig{
+			maxSize:              defaultQueueMaxSize,
+			needsLease:           false,
+			needsSystemConfig:    false,
+			acceptsUnsplitRanges: true,
+			// The data of a replica is read at the same rate-limited pace as
+			// it is streamed by a snapshot.
+			processTimeoutFunc: makeQueueSnapshotTimeoutFunc(scrubRate),
+			successes:          store.metrics.ScrubQueueSuccesses,
+			failures:           store.metrics.ScrubQueueFailures,
+			pending:            store.metrics.ScrubQueuePending,
+			processingNanos:    store.metrics.ScrubQueueProcessingNanos,
+		},
+	)
+	return q
+}
+
+func (q *scrubQueue) shouldQueue(
+	ctx context.Context, now hlc.Timestamp, repl *Replica, _ *config.SystemConfig,
+) (bool, float64) {
+	interval := q.interval()
+	if interval <= 0 {
+		return false, 0
+	}
+	if repl.store.cfg.TestingKnobs.DisableLastProcessedCheck {
+		return true, 0
+	}
+	repl.lastScrubbed.Lock()
+	lpTS := repl.lastScrubbed.ts
+	repl.lastScrubbed.Unlock()
+	return shouldQueueAgain(now, lpTS, interval)
+}
+
+// process verifies the checksums of all of the replicated values of the
+// replica, as of a snapshot of the store.
+func (q *scrubQueue) process(ctx context.Context, repl *Replica, _ *config.SystemConfig) error {
+	if q.interval() <= 0 {
+		return nil
+	}
+	// Like the consistency checker, the scrubber targets a much longer cycle
+	// time than other queues and doesn't retry failures.
+	repl.lastScrubbed.Lock()
+	repl.lastScrubbed.ts = repl.store.Clock().Now()
+	repl.lastScrubbed.Unlock()
+
+	snap := repl.store.Engine().NewSnapshot()
+	defer snap.Close()
+	// As for snapshots, convert the bytes/sec rate limit to batches/sec.
+	targetRate := rate.Limit(scrubRate.Get(&repl.store.ClusterSettings().SV))
+	limiter := rate.NewLimiter(targetRate/scrubBatchSize, 1 /* burst size */)
+
+	var keys, corrupt, batchBytes int64
+	var firstErr error
+	iter := rditer.NewReplicaDataIterator(repl.Desc(), snap, true /* replicatedOnly */, false /* seekEnd */)
+	defer iter.Close()
+	for ; ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		key, value := iter.UnsafeKey(), iter.UnsafeValue()
+		keys++
+		if err := verifyValueChecksum(key, value); err != nil {
+			corrupt++
+			if firstErr == nil {
+				firstErr = err
+			}
+			if corrupt <= scrubMaxReportedKeys {
+				log.Errorf(ctx, "scrubber found corrupt value: %v", err)
+			}
+		}
+		if batchBytes += int64(len(key.Key) + len(value)); batchBytes >= scrubBatchSize {
+			batchBytes = 0
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	repl.store.metrics.ScrubKeys.Inc(keys)
+	if corrupt == 0 {
+		return nil
+	}
+	repl.store.metrics.ScrubCorruptions.Inc(corrupt)
+	details := fmt.Sprintf("found %d corrupt values out of %d: %v", corrupt, keys, firstErr)
+	log.Errorf(ctx, "scrubber %s", details)
+	if err := repl.store.logScrubCorruption(ctx, *repl.Desc(), details); err != nil {
+		log.Warningf(ctx, "unable to log scrub corruption: %+v", err)
+	}
+	if scrubQuarantine.Get(&repl.store.ClusterSettings().SV) {
+		return repl.maybeSetCorrupt(ctx, roachpb.NewError(roachpb.NewReplicaCorruptionError(firstErr))).GoError()
+	}
+	return nil
+}
+
+// verifyValueChecksum verifies the checksum of a versioned or inline value
+// read from the engine. Intents and other MVCC metadata carry no checksum.
+func verifyValueChecksum(key storage.MVCCKey, value []byte) error {
+	if key.IsValue() {
+		return roachpb.Value{RawBytes: value}.Verify(key.Key)
+	}
+	var meta enginepb.MVCCMetadata
+	if err := protoutil.Unmarshal(value, &meta); err != nil {
+		return errors.Wrapf(err, "%s: unable to decode MVCC metadata", key)
+	}
+	if meta.IsInline() {
+		return roachpb.Value{RawBytes: meta.RawBytes}.Verify(key.Key)
+	}
+	return nil
+}
+
+func (q *scrubQueue) timer(duration time.Duration) time.Duration {
+	// An interval between replicas to space the scrubbing out over the scrub
+	// interval.
+	replicaCount := q.replicaCountFn()
+	if replicaCount == 0 {
+		return 0
+	}
+	replInterval := q.interval() / time.Duration(replicaCount)
+	if replInterval < duration {
+		return 0
+	}
+	return replInterval - duration
+}
+
+// purgatoryChan returns nil.
+func (*scrubQueue) purgatoryChan() <-chan time.Time {
+	return nil
+}
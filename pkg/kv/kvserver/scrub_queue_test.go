@@ -0,0 +1,74 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScrubQueue verifies that the scrubber detects values whose checksum
+// doesn't match and reports them through the store metrics.
+func TestScrubQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	testCtx := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	testCtx.Start(t, stopper)
+
+	q := newScrubQueue(testCtx.store, testCtx.gossip)
+	now := testCtx.store.Clock().Now()
+
+	// The scrubber is disabled by default.
+	shouldQ, _ := q.shouldQueue(ctx, now, testCtx.repl, nil)
+	require.False(t, shouldQ)
+	scrubInterval.Override(&testCtx.store.ClusterSettings().SV, time.Hour)
+	shouldQ, _ = q.shouldQueue(ctx, now, testCtx.repl, nil)
+	require.True(t, shouldQ)
+
+	ts := hlc.Timestamp{WallTime: 1}
+	put := func(key string, corrupt bool) {
+		value := roachpb.MakeValueFromString("value")
+		value.InitChecksum(roachpb.Key(key))
+		if corrupt {
+			value.RawBytes[len(value.RawBytes)-1]++
+		}
+		require.NoError(t, testCtx.engine.Put(storage.MVCCKey{Key: roachpb.Key(key), Timestamp: ts}, value.RawBytes))
+	}
+	put("a", false)
+	put("b", true)
+	put("c", false)
+
+	metrics := testCtx.store.metrics
+	require.NoError(t, q.process(ctx, testCtx.repl, nil))
+	require.Equal(t, int64(1), metrics.ScrubCorruptions.Count())
+	require.Less(t, int64(3), metrics.ScrubKeys.Count())
+
+	// After being processed, the replica isn't scrubbed again before the
+	// interval has passed.
+	shouldQ, _ = q.shouldQueue(ctx, testCtx.store.Clock().Now(), testCtx.repl, nil)
+	require.False(t, shouldQ)
+	// The time of the scrub is local to the replica, and not written to the
+	// replicated last processed key shared by the replicas of the range.
+	lpTS, err := testCtx.repl.getQueueLastProcessed(ctx, q.name)
+	require.NoError(t, err)
+	require.Equal(t, hlc.Timestamp{}, lpTS)
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// slowStorageOpLogSize is the number of slow operations retained per store.
+const slowStorageOpLogSize = 64
+
+// slowStorageOpKind is a kind of storage operation recorded in the slow
+// storage operation log, along with the latency above which it is recorded.
+// Each kind has its own threshold since their normal latencies differ by
+// orders of magnitude: a compaction routinely takes longer than a raft log
+// sync ever should.
+type slowStorageOpKind struct {
+	name      string
+	threshold *settings.DurationSetting
+}
+
+// registerSlowStorageOp registers the threshold cluster setting of a kind of
+// slow storage operation.
+func registerSlowStorageOp(
+	name, setting, desc string, defaultThreshold time.Duration,
+) slowStorageOpKind {
+	return slowStorageOpKind{
+		name: name,
+		threshold: settings.RegisterNonNegativeDurationSetting(
+			setting,
+			desc+" taking longer than this are logged and retained for the "+
+				"engine stats status endpoint (0 disables)",
+			defaultThreshold,
+		),
+	}
+}
+
+// The kinds of operations recorded in the slow storage operation log.
+var (
+	slowOpRaftLogCommit = registerSlowStorageOp(
+		"raft log commit", "storage.slow_op_threshold.raft_log_commit",
+		"unsynced commits of raft log batches", 500*time.Millisecond,
+	)
+	slowOpRaftLogSync = registerSlowStorageOp(
+		"raft log sync", "storage.slow_op_threshold.raft_log_sync",
+		"synced commits of raft log batches", time.Second,
+	)
+	slowOpApplyCommit = registerSlowStorageOp(
+		"apply batch commit", "storage.slow_op_threshold.apply_commit",
+		"commits of raft command application batches", 500*time.Millisecond,
+	)
+	slowOpCompaction = registerSlowStorageOp(
+		"compaction", "storage.slow_op_threshold.compaction",
+		"manual compactions suggested by the compactor", 10*time.Minute,
+	)
+)
+
+// SlowStorageOp describes a single storage engine operation which exceeded
+// the storage.slow_op_threshold.* cluster setting of its kind.
+type SlowStorageOp struct {
+	Op       string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// slowStorageOpLog is a bounded log of the most recent slow storage
+// operations on a store. The zero value is ready for use.
+type slowStorageOpLog struct {
+	syncutil.Mutex
+	// ops is used as a ring buffer once it reaches slowStorageOpLogSize, with
+	// next pointing at the oldest entry.
+	ops  []SlowStorageOp
+	next int
+}
+
+func (l *slowStorageOpLog) add(op SlowStorageOp) {
+	l.Lock()
+	defer l.Unlock()
+	if len(l.ops) < slowStorageOpLogSize {
+		l.ops = append(l.ops, op)
+		return
+	}
+	l.ops[l.next] = op
+	l.next = (l.next + 1) % slowStorageOpLogSize
+}
+
+// snapshot returns a copy of the log, oldest entry first.
+func (l *slowStorageOpLog) snapshot() []SlowStorageOp {
+	l.Lock()
+	defer l.Unlock()
+	res := make([]SlowStorageOp, 0, len(l.ops))
+	res = append(res, l.ops[l.next:]...)
+	return append(res, l.ops[:l.next]...)
+}
+
+// maybeRecordSlowStorageOp records the operation which started at the given
+// time in the store's slow operation log if it took longer than the threshold
+// of its kind.
+func (s *Store) maybeRecordSlowStorageOp(
+	ctx context.Context, op slowStorageOpKind, start time.Time,
+) {
+	threshold := op.threshold.Get(&s.cfg.Settings.SV)
+	if threshold == 0 {
+		return
+	}
+	if d := timeutil.Since(start); d > threshold {
+		log.Warningf(ctx, "slow storage operation: %s took %s (>%s)", op.name, d, threshold)
+		s.slowStorageOps.add(SlowStorageOp{Op: op.name, Start: start, Duration: d})
+	}
+}
+
+// SlowStorageOps returns the most recent storage operations on the store
+// which exceeded the threshold of their kind, oldest first.
+func (s *Store) SlowStorageOps() []SlowStorageOp {
+	return s.slowStorageOps.snapshot()
+}
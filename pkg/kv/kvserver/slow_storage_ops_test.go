@@ -0,0 +1,49 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowStorageOpLog(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var l slowStorageOpLog
+	require.Empty(t, l.snapshot())
+
+	op := func(i int) SlowStorageOp {
+		return SlowStorageOp{Op: fmt.Sprintf("op%d", i), Duration: time.Duration(i)}
+	}
+	for i := 0; i < slowStorageOpLogSize/2; i++ {
+		l.add(op(i))
+	}
+	ops := l.snapshot()
+	require.Len(t, ops, slowStorageOpLogSize/2)
+	require.Equal(t, op(0), ops[0])
+
+	// Once the log is full, the oldest entries are overwritten and the snapshot
+	// stays in insertion order.
+	const total = 2*slowStorageOpLogSize + 3
+	for i := slowStorageOpLogSize / 2; i < total; i++ {
+		l.add(op(i))
+	}
+	ops = l.snapshot()
+	require.Len(t, ops, slowStorageOpLogSize)
+	for i, o := range ops {
+		require.Equal(t, op(total-slowStorageOpLogSize+i), o)
+	}
+}
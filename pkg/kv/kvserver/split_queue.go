@@ -103,8 +103,10 @@ func shouldSplitRange(
 	}
 
 	// Add priority based on the size of range compared to the max
-	// size for the zone it's in.
-	if ratio := float64(ms.Total()) / float64(maxBytes); ratio > 1 {
+	// size for the zone it's in. As in processAttempt, a maxBytes of 0
+	// disables size-based splitting; without this check such a range would be
+	// queued with infinite priority and then never split.
+	if ratio := float64(ms.Total()) / float64(maxBytes); maxBytes > 0 && ratio > 1 {
 		priority += ratio
 		shouldQ = true
 	}
@@ -204,14 +206,16 @@ func (sq *splitQueue) processAttempt(
 	size := r.GetMVCCStats().Total()
 	maxBytes := r.GetMaxBytes()
 	if maxBytes > 0 && float64(size)/float64(maxBytes) > 1 {
-		_, err := r.adminSplitWithDescriptor(
+		if _, err := r.adminSplitWithDescriptor(
 			ctx,
 			roachpb.AdminSplitRequest{},
 			desc,
 			false, /* delayable */
 			fmt.Sprintf("%s above threshold size %s", humanizeutil.IBytes(size), humanizeutil.IBytes(maxBytes)),
-		)
-		return err
+		); err != nil {
+			return errors.Wrapf(err, "unable to split %s by size", r)
+		}
+		return nil
 	}
 
 	now := timeutil.Now()
@@ -65,6 +65,8 @@ func TestSplitQueueShouldQueue(t *testing.T) {
 		{keys.MakeTablePrefix(2000), roachpb.RKeyMax, 32<<20 + 1, 32 << 20, true, 2},
 		// Split needed at table boundary, but no zone config, no load.
 		{keys.MakeTablePrefix(2001), roachpb.RKeyMax, 32<<20 + 1, 64 << 20, true, 1},
+		// No intersection, size-based splitting disabled, no load.
+		{roachpb.RKeyMin, roachpb.RKey(keys.MetaMax), 64 << 20, 0, false, 0},
 	}
 
 	cfg := tc.gossip.GetSystemConfig()
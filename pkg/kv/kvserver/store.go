@@ -108,7 +108,7 @@ var logSSTInfoTicks = envutil.EnvOrDefaultInt(
 // bulkIOWriteLimit is defined here because it is used by BulkIOWriteLimiter.
 var bulkIOWriteLimit = settings.RegisterPublicByteSizeSetting(
 	"kv.bulk_io_write.max_rate",
-	"the rate limit (bytes/sec) to use for writes to disk on behalf of bulk io ops",
+	"the rate limit (bytes/sec) to use for writes to disk on behalf of bulk io ops, snapshots and garbage collection",
 	1<<40,
 )
 
@@ -404,6 +404,7 @@ type Store struct {
 	tsMaintenanceQueue *timeSeriesMaintenanceQueue // Time series maintenance queue
 	scanner            *replicaScanner             // Replica scanner
 	consistencyQueue   *consistencyQueue           // Replica consistency check queue
+	scrubQueue         *scrubQueue                 // Replica value checksum verification queue
 	metrics            *StoreMetrics
 	intentResolver     *intentresolver.IntentResolver
 	recoveryMgr        txnrecovery.Manager
@@ -412,6 +413,7 @@ type Store struct {
 	txnWaitMetrics     *txnwait.Metrics
 	sstSnapshotStorage SSTSnapshotStorage
 	protectedtsCache   protectedts.Cache
+	slowStorageOps     slowStorageOpLog // Recent slow engine operations
 
 	// gossipRangeCountdown and leaseRangeCountdown are countdowns of
 	// changes to range and leaseholder counts, after which the store
@@ -578,6 +580,18 @@ type Store struct {
 		m map[roachpb.RangeID]struct{}
 	}
 
+	// The GC thresholds published to the compactions of the engine, see
+	// startCompactionGCThresholdsUpdater.
+	compactionGC struct {
+		syncutil.Mutex
+		// published is keyed by the range ID of the replica each threshold
+		// was computed from.
+		published map[roachpb.RangeID]storage.GCThreshold
+		// invalidated is the set of ranges whose thresholds were invalidated
+		// since the updater last collected the thresholds of the replicas.
+		invalidated map[roachpb.RangeID]struct{}
+	}
+
 	// replicaQueues is a map of per-Replica incoming request queues. These
 	// queues might more naturally belong in Replica, but are kept separate to
 	// avoid reworking the locking in getOrCreateReplica which requires
@@ -835,6 +849,11 @@ func NewStore(
 	s.rangefeedReplicas.m = map[roachpb.RangeID]struct{}{}
 	s.rangefeedReplicas.Unlock()
 
+	s.compactionGC.Lock()
+	s.compactionGC.published = map[roachpb.RangeID]storage.GCThreshold{}
+	s.compactionGC.invalidated = map[roachpb.RangeID]struct{}{}
+	s.compactionGC.Unlock()
+
 	s.tsCache = tscache.New(cfg.Clock, cfg.TimestampCachePageSize)
 	s.metrics.registry.AddMetricStruct(s.tsCache.Metrics())
 
@@ -847,7 +866,8 @@ func NewStore(
 		func() (roachpb.StoreCapacity, error) {
 			return s.Capacity(false /* useCached */)
 		},
-		func(ctx context.Context) {
+		func(ctx context.Context, start time.Time) {
+			s.maybeRecordSlowStorageOp(ctx, slowOpCompaction, start)
 			s.asyncGossipStore(ctx, "compactor-initiated rocksdb compaction", false /* useCached */)
 		},
 	)
@@ -922,12 +942,13 @@ func NewStore(
 		s.raftLogQueue = newRaftLogQueue(s, s.db, s.cfg.Gossip)
 		s.raftSnapshotQueue = newRaftSnapshotQueue(s, s.cfg.Gossip)
 		s.consistencyQueue = newConsistencyQueue(s, s.cfg.Gossip)
+		s.scrubQueue = newScrubQueue(s, s.cfg.Gossip)
 		// NOTE: If more queue types are added, please also add them to the list of
 		// queues on the EnqueueRange debug page as defined in
 		// pkg/ui/src/views/reports/containers/enqueueRange/index.tsx
 		s.scanner.AddQueues(
 			s.gcQueue, s.mergeQueue, s.splitQueue, s.replicateQueue, s.replicaGCQueue,
-			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue)
+			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue, s.scrubQueue)
 
 		if s.cfg.TimeSeriesDataStore != nil {
 			s.tsMaintenanceQueue = newTimeSeriesMaintenanceQueue(
@@ -964,6 +985,9 @@ func NewStore(
 	if cfg.TestingKnobs.DisableConsistencyQueue {
 		s.setConsistencyQueueActive(false)
 	}
+	if cfg.TestingKnobs.DisableScrubQueue {
+		s.setScrubQueueActive(false)
+	}
 	if cfg.TestingKnobs.DisableScanner {
 		s.setScannerActive(false)
 	}
@@ -1531,6 +1555,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	// Connect rangefeeds to closed timestamp updates.
 	s.startClosedTimestampRangefeedSubscriber(ctx)
 
+	// Publish the GC thresholds to compactions.
+	s.startCompactionGCThresholdsUpdater(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings)
@@ -2422,10 +2449,10 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	return nil
 }
 
-// checkpoint creates a RocksDB checkpoint in the auxiliary directory with the
+// Checkpoint creates a RocksDB checkpoint in the auxiliary directory with the
 // provided tag used in the filepath. The filepath for the checkpoint directory
 // is returned.
-func (s *Store) checkpoint(ctx context.Context, tag string) (string, error) {
+func (s *Store) Checkpoint(ctx context.Context, tag string) (string, error) {
 	checkpointBase := filepath.Join(s.engine.GetAuxiliaryDir(), "checkpoints")
 	_ = os.MkdirAll(checkpointBase, 0700)
 
@@ -2470,7 +2497,7 @@ func (s *Store) ComputeMetrics(ctx context.Context, tick int) error {
 	s.metrics.RdbNumSSTables.Update(int64(sstables.Len()))
 	readAmp := sstables.ReadAmplification()
 	s.metrics.RdbReadAmplification.Update(int64(readAmp))
-	s.metrics.RdbPendingCompaction.Update(stats.PendingCompactionBytesEstimate)
+	s.metrics.updateSSTablesStats(sstables)
 	// Log this metric infrequently (with current configurations,
 	// every 10 minutes). Trigger on tick 1 instead of tick 0 so that
 	// non-periodic callers of this method don't trigger expensive
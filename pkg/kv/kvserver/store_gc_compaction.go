@@ -0,0 +1,152 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// compactionGCThresholdsInterval is the interval at which the GC thresholds
+// of the replicas are published to the storage engine.
+const compactionGCThresholdsInterval = time.Minute
+
+// startCompactionGCThresholdsUpdater periodically publishes the GC thresholds
+// of the replicas to the storage engine, if it supports dropping the
+// versions below them during compactions.
+//
+// A threshold only applies to the span of the replica it was computed from
+// as long as the replica keeps the same descriptor generation: it is
+// withdrawn from the engine as soon as the replica splits, merges, or is
+// removed (see invalidateCompactionGCThreshold), since the span may then
+// hold the data of a range with a lower GC threshold.
+func (s *Store) startCompactionGCThresholdsUpdater(ctx context.Context) {
+	if _, ok := s.engine.(storage.CompactionGCer); !ok {
+		return
+	}
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(compactionGCThresholdsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-s.stopper.ShouldStop():
+				return
+			}
+			s.compactionGC.Lock()
+			if !storage.CompactionGCActive(ctx, s.ClusterSettings()) &&
+				len(s.compactionGC.published) == 0 {
+				s.compactionGC.Unlock()
+				continue
+			}
+			s.compactionGC.invalidated = map[roachpb.RangeID]struct{}{}
+			s.compactionGC.Unlock()
+
+			// The thresholds are collected without holding compactionGC, which
+			// is acquired by the replicas while holding their own mutex.
+			var thresholds map[roachpb.RangeID]storage.GCThreshold
+			if storage.CompactionGCActive(ctx, s.ClusterSettings()) {
+				thresholds = s.compactionGCThresholds()
+			}
+
+			start := timeutil.Now()
+			s.compactionGC.Lock()
+			// Drop the thresholds of the replicas whose descriptor changed while
+			// they were being collected.
+			for rangeID := range s.compactionGC.invalidated {
+				delete(thresholds, rangeID)
+			}
+			err := s.publishCompactionGCThresholdsLocked(thresholds)
+			s.compactionGC.Unlock()
+			if err != nil {
+				log.Warningf(ctx, "unable to publish GC thresholds to the storage engine: %+v", err)
+				continue
+			}
+			log.VEventf(ctx, 2, "published %d GC thresholds in %s", len(thresholds), timeutil.Since(start))
+		}
+	})
+}
+
+// compactionGCThresholds returns the GC thresholds of the user keys of the
+// initialized replicas of the store.
+func (s *Store) compactionGCThresholds() map[roachpb.RangeID]storage.GCThreshold {
+	thresholds := map[roachpb.RangeID]storage.GCThreshold{}
+	newStoreReplicaVisitor(s).Visit(func(repl *Replica) bool {
+		if !repl.IsInitialized() {
+			return true
+		}
+		threshold := repl.GetGCThreshold()
+		if threshold.IsEmpty() {
+			return true
+		}
+		desc := repl.Desc()
+		userKeys := rditer.MakeUserKeyRange(desc)
+		thresholds[desc.RangeID] = storage.GCThreshold{
+			RangeID:    desc.RangeID,
+			Generation: desc.GetGeneration(),
+			Span:       roachpb.Span{Key: userKeys.Start.Key, EndKey: userKeys.End.Key},
+			Threshold:  threshold,
+		}
+		return true
+	})
+	return thresholds
+}
+
+// invalidateCompactionGCThreshold withdraws the GC threshold published for
+// the given range, unless it was computed from a replica with the given
+// descriptor generation. A nil descriptor withdraws it unconditionally, as
+// when the replica is removed. It may be called while holding the replica's
+// mutex.
+func (s *Store) invalidateCompactionGCThreshold(
+	ctx context.Context, rangeID roachpb.RangeID, desc *roachpb.RangeDescriptor,
+) {
+	s.compactionGC.Lock()
+	defer s.compactionGC.Unlock()
+	s.compactionGC.invalidated[rangeID] = struct{}{}
+	t, ok := s.compactionGC.published[rangeID]
+	if !ok || (desc != nil && t.Generation == desc.GetGeneration()) {
+		return
+	}
+	thresholds := make(map[roachpb.RangeID]storage.GCThreshold, len(s.compactionGC.published))
+	for id, t := range s.compactionGC.published {
+		if id != rangeID {
+			thresholds[id] = t
+		}
+	}
+	if err := s.publishCompactionGCThresholdsLocked(thresholds); err != nil {
+		// The engine keeps using the stale threshold, which could drop the
+		// versions of a range with a lower one.
+		log.Fatalf(ctx, "unable to withdraw the GC threshold of r%d from the storage engine: %+v",
+			rangeID, err)
+	}
+}
+
+// publishCompactionGCThresholdsLocked replaces the GC thresholds used by the
+// compactions of the engine. It requires compactionGC to be held.
+func (s *Store) publishCompactionGCThresholdsLocked(
+	thresholds map[roachpb.RangeID]storage.GCThreshold,
+) error {
+	list := make([]storage.GCThreshold, 0, len(thresholds))
+	for _, t := range thresholds {
+		list = append(list, t)
+	}
+	if err := s.engine.(storage.CompactionGCer).SetCompactionGCThresholds(list); err != nil {
+		return err
+	}
+	s.compactionGC.published = thresholds
+	return nil
+}
@@ -141,6 +141,10 @@ func (s *Store) removeInitializedReplicaRaftMuLocked(
 		roachpb.RangeFeedRetryError_REASON_REPLICA_REMOVED,
 	)
 
+	// The span of the replica may be taken over by another range, to which
+	// its GC threshold doesn't apply.
+	s.invalidateCompactionGCThreshold(ctx, rep.RangeID, nil /* desc */)
+
 	// Mark the replica as destroyed and (optionally) destroy the on-disk data
 	// while not holding Store.mu. This is safe because we're holding
 	// Replica.raftMu and the replica is present in Store.mu.replicasByKey
@@ -101,6 +101,9 @@ type kvBatchSnapshotStrategy struct {
 	limiter *rate.Limiter
 	// Only used on the sender side.
 	newBatch func() storage.Batch
+	// The number of bytes of KV batches and log entries sent so far. Only used
+	// on the sender side.
+	bytesSent int64
 
 	// The approximate size of the SST chunk to buffer in memory on the receiver
 	// before flushing to disk. Only used on the receiver side.
@@ -368,12 +371,10 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 	}
 	logEntries := make([][]byte, 0, preallocSize)
 
-	var raftLogBytes int64
 	scanFunc := func(kv roachpb.KeyValue) (bool, error) {
 		bytes, err := kv.Value.GetBytes()
 		if err == nil {
 			logEntries = append(logEntries, bytes)
-			raftLogBytes += int64(len(bytes))
 		}
 		return false, err
 	}
@@ -458,7 +459,11 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 			}
 		}
 	}
-	kvSS.status = fmt.Sprintf("kv pairs: %d, log entries: %d", n, len(logEntries))
+	for i := range logEntries {
+		kvSS.bytesSent += int64(len(logEntries[i]))
+	}
+	kvSS.status = fmt.Sprintf("kv pairs: %d, log entries: %d, %s",
+		n, len(logEntries), humanizeutil.IBytes(kvSS.bytesSent))
 	return stream.Send(&SnapshotRequest{LogEntries: logEntries})
 }
 
@@ -470,6 +475,7 @@ func (kvSS *kvBatchSnapshotStrategy) sendBatch(
 	}
 	repr := batch.Repr()
 	batch.Close()
+	kvSS.bytesSent += int64(len(repr))
 	return stream.Send(&SnapshotRequest{KVBatch: repr})
 }
 
@@ -832,7 +838,7 @@ var rebalanceSnapshotRate = settings.RegisterPublicValidatedByteSizeSetting(
 	validatePositive,
 )
 
-// recoverySnapshotRate is the rate at which Raft-initiated spanshots can be
+// recoverySnapshotRate is the rate at which Raft-initiated snapshots can be
 // sent. Ideally, one would never see a Raft-initiated snapshot; we'd like all
 // the snapshots to be preemptive. However, it has proved unfeasible to
 // completely get rid of them.
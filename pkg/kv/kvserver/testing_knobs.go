@@ -117,6 +117,8 @@ type StoreTestingKnobs struct {
 	DisableRaftSnapshotQueue bool
 	// DisableConsistencyQueue disables the consistency checker.
 	DisableConsistencyQueue bool
+	// DisableScrubQueue disables the scrubber.
+	DisableScrubQueue bool
 	// DisableScanner disables the replica scanner.
 	DisableScanner bool
 	// DisablePeriodicGossips disables periodic gossiping.
@@ -424,6 +424,53 @@ func TestTimestampCacheEqualTimestamps(t *testing.T) {
 	})
 }
 
+// TestTimestampCacheSetLowWater verifies that raising the low water mark over
+// a span ratchets the timestamps within that span without affecting the rest
+// of the cache.
+func TestTimestampCacheSetLowWater(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	forEachCacheImpl(t, func(t *testing.T, tc Cache, clock *hlc.Clock, manual *hlc.ManualClock) {
+		baseTS := manual.UnixNano()
+		txn1 := uuid.MakeV4()
+
+		manual.Increment(100)
+		ts1 := clock.Now()
+		tc.Add(roachpb.Key("a"), roachpb.Key("c"), ts1, txn1)
+
+		manual.Increment(100)
+		ts2 := clock.Now()
+		tc.SetLowWater(roachpb.Key("b"), roachpb.Key("d"), ts2)
+
+		// Keys below the span are untouched.
+		if ts, txn := tc.GetMax(roachpb.Key("a"), nil); ts != ts1 || txn != txn1 {
+			t.Errorf("expected 'a' to have timestamp %s and txn id %s, but found %s, %s", ts1, txn1, ts, txn)
+		}
+		// Keys within the span are ratcheted and no longer owned by a transaction.
+		for _, key := range []string{"b", "bb", "c", "cc"} {
+			if ts, txn := tc.GetMax(roachpb.Key(key), nil); ts != ts2 || txn != noTxnID {
+				t.Errorf("expected %q to have timestamp %s and no txn id, but found %s, %s", key, ts2, ts, txn)
+			}
+		}
+		// Keys above the span fall back to the global low water mark.
+		if ts, _ := tc.GetMax(roachpb.Key("d"), nil); ts.WallTime != baseTS {
+			t.Errorf("expected 'd' to have the low water timestamp, but found %s", ts)
+		}
+		if ts, txn := tc.GetMax(roachpb.Key("a"), roachpb.Key("e")); ts != ts2 || txn != noTxnID {
+			t.Errorf("expected 'a'-'e' to have timestamp %s and no txn id, but found %s, %s", ts2, ts, txn)
+		}
+
+		// Setting a lower low water mark never regresses existing timestamps.
+		tc.SetLowWater(roachpb.Key("a"), roachpb.Key("c"), hlc.Timestamp{WallTime: baseTS})
+		if ts, txn := tc.GetMax(roachpb.Key("a"), nil); ts != ts1 || txn != txn1 {
+			t.Errorf("expected 'a' to have timestamp %s and txn id %s, but found %s, %s", ts1, txn1, ts, txn)
+		}
+		if ts, _ := tc.GetMax(roachpb.Key("b"), nil); ts != ts2 {
+			t.Errorf("expected 'b' to have timestamp %s, but found %s", ts2, ts)
+		}
+	})
+}
+
 // TestTimestampCacheLargeKeys verifies that the timestamp cache implementations
 // can support arbitrarily large keys lengths. This is important because we don't
 // place a hard limit on this anywhere else.
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -405,7 +406,8 @@ func (txn *Txn) Put(ctx context.Context, key, value interface{}) error {
 // pass nil for expValue. Note that this must be an interface{}(nil), not a
 // typed nil value (e.g. []byte(nil)).
 //
-// Returns an error if the existing value is not equal to expValue.
+// Returns a *roachpb.ConditionFailedError carrying the existing value, if any,
+// when it is not equal to expValue.
 //
 // key can be either a byte slice or a string. value can be any key type, a
 // protoutil.Message or any Go primitive type (bool, int, etc).
@@ -778,6 +780,21 @@ func (e *AutoCommitError) Error() string {
 	return e.cause.Error()
 }
 
+// warnRetriesEvery is the number of automatic retries of a transaction after
+// which exec logs a warning, so that transactions which are making no progress
+// are visible.
+const warnRetriesEvery = 10
+
+// txnRetryOptions are the backoff options between the automatic retries of a
+// transaction in exec. The backoff starts out short, since most retries
+// succeed right away, but slows down transactions which keep conflicting with
+// others so that they don't starve them.
+var txnRetryOptions = retry.Options{
+	InitialBackoff: 2 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
 // exec executes fn in the context of a distributed transaction. The closure is
 // retried on retriable errors.
 // If no error is returned by the closure, an attempt to commit the txn is made.
@@ -789,7 +806,13 @@ func (e *AutoCommitError) Error() string {
 func (txn *Txn) exec(ctx context.Context, fn func(context.Context, *Txn) error) (err error) {
 	// Run fn in a retry loop until we encounter a success or
 	// error condition this loop isn't capable of handling.
-	for {
+	r := retry.StartWithCtx(ctx, txnRetryOptions)
+	for retries := 0; r.Next(); retries++ {
+		if retries > 0 && retries%warnRetriesEvery == 0 {
+			log.Warningf(ctx, "have retried transaction %s %d times, most recently because of the "+
+				"retryable error: %s; is the transaction stuck in a retry loop?",
+				txn.DebugName(), retries, err)
+		}
 		if err := ctx.Err(); err != nil {
 			return err
 		}
@@ -835,13 +858,13 @@ func (txn *Txn) exec(ctx context.Context, fn func(context.Context, *Txn) error)
 		}
 
 		if !retryable {
-			break
+			return err
 		}
 
 		txn.PrepareForRetry(ctx, err)
 	}
-
-	return err
+	// The context was canceled while backing off.
+	return ctx.Err()
 }
 
 // PrepareForRetry needs to be called before an retry to perform some
@@ -164,6 +164,46 @@ func TestBatchRequestGetArg(t *testing.T) {
 	}
 }
 
+func TestBatchRequestFlags(t *testing.T) {
+	get := &GetRequest{}
+	put := &PutRequest{}
+	revScan := &ReverseScanRequest{}
+	lockingRevScan := &ReverseScanRequest{KeyLocking: lock.Exclusive}
+	rangeStats := &RangeStatsRequest{}
+	split := &AdminSplitRequest{}
+
+	testCases := []struct {
+		reqs                                   []Request
+		readOnly, write, admin, reverse        bool
+		locking, transactional, allTransaction bool
+	}{
+		{reqs: nil},
+		{reqs: []Request{get}, readOnly: true, transactional: true, allTransaction: true},
+		{reqs: []Request{get, put}, write: true, locking: true, transactional: true, allTransaction: true},
+		{reqs: []Request{revScan}, readOnly: true, reverse: true, transactional: true, allTransaction: true},
+		{reqs: []Request{lockingRevScan}, readOnly: true, reverse: true, locking: true,
+			transactional: true, allTransaction: true},
+		{reqs: []Request{get, rangeStats}, readOnly: true, transactional: true},
+		{reqs: []Request{rangeStats}, readOnly: true},
+		{reqs: []Request{split}, admin: true},
+	}
+	for _, tc := range testCases {
+		var ba BatchRequest
+		for _, req := range tc.reqs {
+			ba.Add(req)
+		}
+		t.Run(ba.Summary(), func(t *testing.T) {
+			require.Equal(t, tc.readOnly, ba.IsReadOnly(), "IsReadOnly")
+			require.Equal(t, tc.write, ba.IsWrite(), "IsWrite")
+			require.Equal(t, tc.admin, ba.IsAdmin(), "IsAdmin")
+			require.Equal(t, tc.reverse, ba.IsReverse(), "IsReverse")
+			require.Equal(t, tc.locking, ba.IsLocking(), "IsLocking")
+			require.Equal(t, tc.transactional, ba.IsTransactional(), "IsTransactional")
+			require.Equal(t, tc.allTransaction, ba.IsAllTransactional(), "IsAllTransactional")
+		})
+	}
+}
+
 func TestBatchRequestSummary(t *testing.T) {
 	// The Summary function is generated automatically, so the tests don't need to
 	// be exhaustive.
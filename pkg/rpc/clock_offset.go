@@ -113,6 +113,15 @@ func (r *RemoteClockMonitor) Latency(addr string) (time.Duration, bool) {
 	return 0, false
 }
 
+// Offset returns the latest clock offset measurement for the given node
+// address, and whether there is one.
+func (r *RemoteClockMonitor) Offset(addr string) (RemoteOffset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	offset, ok := r.mu.offsets[addr]
+	return offset, ok
+}
+
 // AllLatencies returns a map of all currently valid latency measurements.
 func (r *RemoteClockMonitor) AllLatencies() map[string]time.Duration {
 	r.mu.RLock()
@@ -13,17 +13,21 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"math"
 	"net"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	circuit "github.com/cockroachdb/circuitbreaker"
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -112,32 +116,124 @@ func spanInclusionFuncForClient(
 	return parentSpanCtx != nil && !tracing.IsNoopContext(parentSpanCtx)
 }
 
+// rpcsAllowedForUsers are the RPCs which may be called with a client
+// certificate for any user, not just the node or root users. Callers must
+// still present a verified client certificate. The handler of the KV service
+// authorizes each request itself. Heartbeats are needed by every client to
+// establish a connection, but only heartbeats from the node or root users
+// take part in clock offset tracking, see HeartbeatService.Ping.
+var rpcsAllowedForUsers = map[string]struct{}{
+	"/cockroach.rpc.Heartbeat/Ping":       {},
+	"/cockroach.server.serverpb.KV/Batch": {},
+}
+
+// requireSuperUserUnlessAllowed is like requireSuperUser, but lets through
+// users of any kind with a verified client certificate for the RPCs in
+// rpcsAllowedForUsers.
+func requireSuperUserUnlessAllowed(ctx context.Context, fullMethod string) error {
+	if _, ok := rpcsAllowedForUsers[fullMethod]; ok {
+		_, _, err := isSuperUserRequest(ctx)
+		return err
+	}
+	return requireSuperUser(ctx)
+}
+
 func requireSuperUser(ctx context.Context) error {
+	superUser, certUsers, err := isSuperUserRequest(ctx)
+	if err != nil {
+		return err
+	}
+	if !superUser {
+		return errors.Errorf("user %s is not allowed to perform this RPC", certUsers)
+	}
+	return nil
+}
+
+// isSuperUserRequest returns whether a request comes from the node or root
+// user, along with the users of its client certificate. In-process requests,
+// and requests to insecure servers, count as coming from the node. An error is
+// returned if a request to a secure server does not come with a verified
+// client certificate.
+func isSuperUserRequest(ctx context.Context) (bool, []string, error) {
 	// TODO(marc): grpc's authentication model (which gives credential access in
 	// the request handler) doesn't really fit with the current design of the
 	// security package (which assumes that TLS state is only given at connection
 	// time) - that should be fixed.
 	if grpcutil.IsLocalRequestContext(ctx) {
 		// This is an in-process request. Bypass authentication check.
-	} else if peer, ok := peer.FromContext(ctx); ok {
-		if tlsInfo, ok := peer.AuthInfo.(credentials.TLSInfo); ok {
-			certUsers, err := security.GetCertificateUsers(&tlsInfo.State)
-			if err != nil {
-				return err
-			}
-			// TODO(benesch): the vast majority of RPCs should be limited to just
-			// NodeUser. This is not a security concern, as RootUser has access to
-			// read and write all data, merely good hygiene. For example, there is
-			// no reason to permit the root user to send raw Raft RPCs.
-			if !security.ContainsUser(security.NodeUser, certUsers) &&
-				!security.ContainsUser(security.RootUser, certUsers) {
-				return errors.Errorf("user %s is not allowed to perform this RPC", certUsers)
-			}
-		}
-	} else {
-		return errors.New("internal authentication error: TLSInfo is not available in request context")
+		return true, nil, nil
 	}
-	return nil
+	peer, ok := peer.FromContext(ctx)
+	if !ok {
+		return false, nil, errors.New("internal authentication error: TLSInfo is not available in request context")
+	}
+	tlsInfo, ok := peer.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return true, nil, nil
+	}
+	// The server accepts connections without a client certificate, so that
+	// clients can authenticate by other means over HTTP and SQL. RPCs always
+	// require one: GetCertificateUsers fails if none was given, and the TLS
+	// handshake verified the ones which were.
+	certUsers, err := security.GetCertificateUsers(&tlsInfo.State)
+	if err != nil {
+		return false, nil, err
+	}
+	// TODO(benesch): the vast majority of RPCs should be limited to just
+	// NodeUser. This is not a security concern, as RootUser has access to
+	// read and write all data, merely good hygiene. For example, there is
+	// no reason to permit the root user to send raw Raft RPCs.
+	return security.ContainsUser(security.NodeUser, certUsers) ||
+		security.ContainsUser(security.RootUser, certUsers), certUsers, nil
+}
+
+// revocableCredentials are TLS transport credentials which register the
+// connections they establish with the certificate manager, so that the
+// connections are closed if the certificate of the peer is later revoked.
+type revocableCredentials struct {
+	credentials.TransportCredentials
+	cm *security.CertificateManager
+}
+
+func newRevocableCredentials(
+	cm *security.CertificateManager, tlsConfig *tls.Config,
+) credentials.TransportCredentials {
+	return revocableCredentials{TransportCredentials: credentials.NewTLS(tlsConfig), cm: cm}
+}
+
+// ClientHandshake implements the credentials.TransportCredentials interface.
+func (c revocableCredentials) ClientHandshake(
+	ctx context.Context, authority string, rawConn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := c.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.track(conn, authInfo), authInfo, nil
+}
+
+// ServerHandshake implements the credentials.TransportCredentials interface.
+func (c revocableCredentials) ServerHandshake(
+	rawConn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.track(conn, authInfo), authInfo, nil
+}
+
+// Clone implements the credentials.TransportCredentials interface.
+func (c revocableCredentials) Clone() credentials.TransportCredentials {
+	return revocableCredentials{TransportCredentials: c.TransportCredentials.Clone(), cm: c.cm}
+}
+
+func (c revocableCredentials) track(conn net.Conn, authInfo credentials.AuthInfo) net.Conn {
+	tlsInfo, ok := authInfo.(credentials.TLSInfo)
+	if !ok {
+		return conn
+	}
+	return c.cm.TrackConn(conn, tlsInfo.State.VerifiedChains)
 }
 
 // NewServer is a thin wrapper around grpc.NewServer that registers a heartbeat
@@ -180,7 +276,11 @@ func NewServerWithInterceptor(
 		if err != nil {
 			panic(err)
 		}
-		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		cm, err := ctx.GetCertificateManager()
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, grpc.Creds(newRevocableCredentials(cm, tlsConfig)))
 	}
 
 	var unaryInterceptor grpc.UnaryServerInterceptor
@@ -247,7 +347,7 @@ func NewServerWithInterceptor(
 		unaryInterceptor = func(
 			ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
 		) (interface{}, error) {
-			if err := requireSuperUser(ctx); err != nil {
+			if err := requireSuperUserUnlessAllowed(ctx, info.FullMethod); err != nil {
 				return nil, err
 			}
 			if prevUnaryInterceptor != nil {
@@ -259,7 +359,7 @@ func NewServerWithInterceptor(
 		streamInterceptor = func(
 			srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
 		) error {
-			if err := requireSuperUser(stream.Context()); err != nil {
+			if err := requireSuperUserUnlessAllowed(stream.Context(), info.FullMethod); err != nil {
 				return err
 			}
 			if prevStreamInterceptor != nil {
@@ -290,9 +390,40 @@ func NewServerWithInterceptor(
 	return s
 }
 
+// errNoTLSMessage is part of the error of a TLS handshake with a peer which
+// answered in plaintext.
+const errNoTLSMessage = "tls: first record does not look like a TLS handshake"
+
+// errConnClosedMessage is part of the error of an RPC to a peer which closed
+// the connection before it was established, as a secure node does when it is
+// dialed in plaintext.
+const errConnClosedMessage = "connection closed"
+
+// securityMismatchHint explains heartbeat errors caused by a secure node
+// dialing an insecure one, or vice versa. TLS prevents such nodes from
+// interoperating, but the resulting error does not tell the operator why. A
+// secure node simply closes plaintext connections, so on the insecure side
+// the hint is only a likely explanation. It returns the empty string for all
+// other errors.
+func securityMismatchHint(insecure bool, err error) string {
+	msg := err.Error()
+	if !insecure && strings.Contains(msg, errNoTLSMessage) {
+		return "the remote node does not use TLS. Nodes started with --insecure " +
+			"cannot join a secure cluster, and vice versa"
+	}
+	if insecure && strings.Contains(msg, errConnClosedMessage) {
+		return "the remote node closed the connection. If it requires secure " +
+			"connections, note that nodes started with --insecure cannot join a " +
+			"secure cluster, and vice versa"
+	}
+	return ""
+}
+
 type heartbeatResult struct {
-	everSucceeded bool  // true if the heartbeat has ever succeeded
-	err           error // heartbeat error, initialized to ErrNotHeartbeated
+	everSucceeded bool      // true if the heartbeat has ever succeeded
+	err           error     // heartbeat error, initialized to ErrNotHeartbeated
+	succeededAt   time.Time // time of the latest successful heartbeat
+	buildTag      string    // build tag of the remote node, if known
 }
 
 // state is a helper to return the heartbeatState implied by a heartbeatResult.
@@ -316,6 +447,7 @@ type Connection struct {
 	heartbeatResult      atomic.Value  // result of latest heartbeat
 	initialHeartbeatDone chan struct{} // closed after first heartbeat
 	stopper              *stop.Stopper
+	createdAt            time.Time
 
 	// remoteNodeID implies checking the remote node ID. 0 when unknown,
 	// non-zero to check with remote node. This is constant throughout
@@ -329,6 +461,7 @@ func newConnectionToNodeID(stopper *stop.Stopper, remoteNodeID roachpb.NodeID) *
 	c := &Connection{
 		initialHeartbeatDone: make(chan struct{}),
 		stopper:              stopper,
+		createdAt:            timeutil.Now(),
 		remoteNodeID:         remoteNodeID,
 	}
 	c.heartbeatResult.Store(heartbeatResult{err: ErrNotHeartbeated})
@@ -367,6 +500,72 @@ func (c *Connection) Health() error {
 	return c.heartbeatResult.Load().(heartbeatResult).err
 }
 
+// ConnectionStatus describes a connection in the Context's connection cache,
+// along with the latest clock measurements for its remote address.
+type ConnectionStatus struct {
+	TargetAddr string
+	NodeID     roachpb.NodeID
+	Class      ConnectionClass
+	// Healthy is set if the latest heartbeat succeeded. Otherwise, Error
+	// holds the heartbeat error.
+	Healthy bool
+	Error   string
+	// Age is the time since the connection was first dialed.
+	Age time.Duration
+	// LastHeartbeat is the time of the latest successful heartbeat, or the
+	// zero time if there never was one.
+	LastHeartbeat time.Time
+	Latency       time.Duration
+	RemoteOffset  RemoteOffset
+	// BuildTag is the build tag reported by the remote node in its latest
+	// heartbeat response.
+	BuildTag string
+}
+
+// ConnectionStatuses returns the status of every connection in the Context's
+// connection cache, ordered by target address, node ID and class.
+func (ctx *Context) ConnectionStatuses() []ConnectionStatus {
+	now := timeutil.Now()
+	var statuses []ConnectionStatus
+	ctx.conns.Range(func(k, v interface{}) bool {
+		key, conn := k.(connKey), v.(*Connection)
+		s := ConnectionStatus{
+			TargetAddr: key.targetAddr,
+			NodeID:     key.nodeID,
+			Class:      key.class,
+			Age:        now.Sub(conn.createdAt),
+		}
+		// conn.dialErr is written, without synchronization, while the
+		// connection is being dialed, so it can't be read here. A connection
+		// which failed to dial reports ErrNotHeartbeated until it is removed
+		// from the cache, which happens right after the failure.
+		hr := conn.heartbeatResult.Load().(heartbeatResult)
+		err := hr.err
+		s.LastHeartbeat = hr.succeededAt
+		s.BuildTag = hr.buildTag
+		if err != nil {
+			s.Error = err.Error()
+		} else {
+			s.Healthy = true
+		}
+		s.Latency, _ = ctx.RemoteClocks.Latency(key.targetAddr)
+		s.RemoteOffset, _ = ctx.RemoteClocks.Offset(key.targetAddr)
+		statuses = append(statuses, s)
+		return true
+	})
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if a.TargetAddr != b.TargetAddr {
+			return a.TargetAddr < b.TargetAddr
+		}
+		if a.NodeID != b.NodeID {
+			return a.NodeID < b.NodeID
+		}
+		return a.Class < b.Class
+	})
+	return statuses
+}
+
 // Context contains the fields required by the rpc framework.
 type Context struct {
 	*base.Config
@@ -378,9 +577,11 @@ type Context struct {
 	RemoteClocks *RemoteClockMonitor
 	masterCtx    context.Context
 
-	heartbeatInterval time.Duration
-	heartbeatTimeout  time.Duration
-	HeartbeatCB       func()
+	// heartbeatIntervalNanos is accessed atomically, since it can be changed
+	// with SetHeartbeatInterval while the connections are heartbeating.
+	heartbeatIntervalNanos int64
+	heartbeatTimeout       time.Duration
+	HeartbeatCB            func()
 
 	rpcCompression bool
 
@@ -465,10 +666,10 @@ func NewContextWithTestingKnobs(
 	var cancel context.CancelFunc
 	ctx.masterCtx, cancel = context.WithCancel(ambient.AnnotateCtx(context.Background()))
 	ctx.Stopper = stopper
-	ctx.heartbeatInterval = baseCtx.RPCHeartbeatInterval
+	ctx.heartbeatIntervalNanos = int64(baseCtx.RPCHeartbeatInterval)
 	ctx.RemoteClocks = newRemoteClockMonitor(
-		ctx.LocalClock, 10*ctx.heartbeatInterval, baseCtx.HistogramWindowInterval)
-	ctx.heartbeatTimeout = 2 * ctx.heartbeatInterval
+		ctx.LocalClock, 10*baseCtx.RPCHeartbeatInterval, baseCtx.HistogramWindowInterval)
+	ctx.heartbeatTimeout = 2 * baseCtx.RPCHeartbeatInterval
 	ctx.metrics = makeMetrics()
 
 	stopper.RunWorker(ctx.masterCtx, func(context.Context) {
@@ -504,6 +705,25 @@ func (ctx *Context) ClusterName() string {
 	return ctx.clusterName
 }
 
+// Settings returns the cluster settings of the node.
+func (ctx *Context) Settings() *cluster.Settings {
+	return ctx.settings
+}
+
+// heartbeatInterval returns the interval between the heartbeats of each
+// connection.
+func (ctx *Context) heartbeatInterval() time.Duration {
+	return time.Duration(atomic.LoadInt64(&ctx.heartbeatIntervalNanos))
+}
+
+// SetHeartbeatInterval changes the interval between the heartbeats of each
+// connection, taking effect after the next heartbeat of the existing
+// connections. The heartbeat timeout and the lifetime of the measured clock
+// offsets keep the values derived from the initial interval.
+func (ctx *Context) SetHeartbeatInterval(d time.Duration) {
+	atomic.StoreInt64(&ctx.heartbeatIntervalNanos, int64(d))
+}
+
 // GetStatsMap returns a map of network statistics maintained by the
 // internal stats handler. The map is from the remote network address
 // (in string form) to an rpc.Stats object.
@@ -668,7 +888,11 @@ func (ctx *Context) grpcDialOptions(
 		if err != nil {
 			return nil, err
 		}
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		cm, err := ctx.GetCertificateManager()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(newRevocableCredentials(cm, tlsConfig)))
 	}
 
 	// The limiting factor for lowering the max message size is the fact
@@ -1061,6 +1285,10 @@ func (ctx *Context) runHeartbeat(
 	// Give the first iteration a wait-free heartbeat attempt.
 	heartbeatTimer.Reset(0)
 	everSucceeded := false
+	var succeededAt time.Time
+	var remoteBuildTag string
+	localBuildTag := build.GetInfo().Tag
+	loggedSecurityMismatch := false
 	for {
 		select {
 		case <-redialChan:
@@ -1117,8 +1345,16 @@ func (ctx *Context) runHeartbeat(
 					"version compatibility check failed on ping response")
 			}
 
+			if err != nil && !loggedSecurityMismatch {
+				if hint := securityMismatchHint(ctx.Insecure, err); hint != "" {
+					log.Errorf(ctx.masterCtx, "cannot connect to node at %s: %s", target, hint)
+					loggedSecurityMismatch = true
+				}
+			}
+
 			if err == nil {
 				everSucceeded = true
+				succeededAt = timeutil.Now()
 				receiveTime := ctx.LocalClock.PhysicalTime()
 
 				// Only update the clock offset measurement if we actually got a
@@ -1140,6 +1376,17 @@ func (ctx *Context) runHeartbeat(
 				}
 				ctx.RemoteClocks.UpdateOffset(ctx.masterCtx, target, request.Offset, pingDuration)
 
+				// Report version skew once per connection and whenever the
+				// remote node is restarted with a different binary. Servers
+				// running older versions don't report a build tag.
+				if response.BuildTag != remoteBuildTag {
+					remoteBuildTag = response.BuildTag
+					if remoteBuildTag != "" && remoteBuildTag != localBuildTag {
+						log.Warningf(ctx.masterCtx, "node at %s is running build %s, which differs from the local build %s",
+							target, remoteBuildTag, localBuildTag)
+					}
+				}
+
 				if cb := ctx.HeartbeatCB; cb != nil {
 					cb()
 				}
@@ -1148,6 +1395,8 @@ func (ctx *Context) runHeartbeat(
 			hr := heartbeatResult{
 				everSucceeded: everSucceeded,
 				err:           err,
+				succeededAt:   succeededAt,
+				buildTag:      remoteBuildTag,
 			}
 			state = updateHeartbeatState(&ctx.metrics, state, hr.state())
 			conn.heartbeatResult.Store(hr)
@@ -1157,6 +1406,6 @@ func (ctx *Context) runHeartbeat(
 			return err
 		}
 
-		heartbeatTimer.Reset(ctx.heartbeatInterval)
+		heartbeatTimer.Reset(ctx.heartbeatInterval())
 	}
 }
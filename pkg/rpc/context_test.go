@@ -16,11 +16,13 @@ import (
 	"math"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -160,6 +162,58 @@ func TestHeartbeatCB(t *testing.T) {
 	})
 }
 
+// TestConnectionStatuses verifies that ConnectionStatuses reports the
+// connections in the connection cache along with their heartbeat state.
+func TestConnectionStatuses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	clusterID := uuid.MakeV4()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	serverCtx := newTestContext(clusterID, clock, stopper)
+	const serverNodeID = 1
+	serverCtx.NodeID.Set(context.TODO(), serverNodeID)
+	s := newTestServer(t, serverCtx)
+	RegisterHeartbeatServer(s, &HeartbeatService{
+		clock:              clock,
+		remoteClockMonitor: serverCtx.RemoteClocks,
+		clusterID:          &serverCtx.ClusterID,
+		nodeID:             &serverCtx.NodeID,
+		settings:           serverCtx.settings,
+	})
+
+	ln, err := netutil.ListenAndServeGRPC(serverCtx.Stopper, s, util.TestAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteAddr := ln.Addr().String()
+
+	clientCtx := newTestContext(clusterID, clock, stopper)
+	if len(clientCtx.ConnectionStatuses()) != 0 {
+		t.Fatalf("expected no connections, got %+v", clientCtx.ConnectionStatuses())
+	}
+	if _, err := clientCtx.GRPCDialNode(remoteAddr, serverNodeID, SystemClass).Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := clientCtx.ConnectionStatuses()
+	if len(statuses) != 1 {
+		t.Fatalf("expected one connection, got %+v", statuses)
+	}
+	status := statuses[0]
+	if status.TargetAddr != remoteAddr || status.NodeID != serverNodeID || status.Class != SystemClass {
+		t.Fatalf("unexpected connection %+v", status)
+	}
+	if !status.Healthy || status.Error != "" || status.LastHeartbeat.IsZero() {
+		t.Fatalf("expected healthy connection, got %+v", status)
+	}
+	if status.BuildTag != build.GetInfo().Tag {
+		t.Fatalf("expected build tag %s, got %+v", build.GetInfo().Tag, status)
+	}
+}
+
 type internalServer struct{}
 
 func (*internalServer) Batch(
@@ -273,7 +327,7 @@ func TestHeartbeatHealth(t *testing.T) {
 	clientCtx.Addr = lisNotLocalServer.Addr().String()
 	clientCtx.AdvertiseAddr = lisLocalServer.Addr().String()
 	// Make the interval shorter to speed up the test.
-	clientCtx.heartbeatInterval = 1 * time.Millisecond
+	clientCtx.SetHeartbeatInterval(1 * time.Millisecond)
 
 	ln, err := netutil.ListenAndServeGRPC(serverCtx.Stopper, s, util.TestAddr)
 	if err != nil {
@@ -532,7 +586,7 @@ func TestHeartbeatHealthTransport(t *testing.T) {
 
 	clientCtx := newTestContext(clusterID, clock, stopper)
 	// Make the interval shorter to speed up the test.
-	clientCtx.heartbeatInterval = 1 * time.Millisecond
+	clientCtx.SetHeartbeatInterval(1 * time.Millisecond)
 	if _, err := clientCtx.GRPCDialNode(remoteAddr, serverNodeID, DefaultClass).Connect(context.Background()); err != nil {
 		t.Fatal(err)
 	}
@@ -643,7 +697,7 @@ func TestHeartbeatHealthTransport(t *testing.T) {
 	})
 
 	// Should stay unhealthy despite reconnection attempts.
-	for then := timeutil.Now(); timeutil.Since(then) < 50*clientCtx.heartbeatInterval; {
+	for then := timeutil.Now(); timeutil.Since(then) < 50*clientCtx.heartbeatInterval(); {
 		err := clientCtx.TestingConnHealth(remoteAddr, serverNodeID)
 		if !isUnhealthy(err) {
 			t.Fatal(err)
@@ -686,7 +740,7 @@ func TestOffsetMeasurement(t *testing.T) {
 	clientClock := hlc.NewClock(clientAdvancing.UnixNano, time.Nanosecond)
 	clientCtx := newTestContext(clusterID, clientClock, stopper)
 	// Make the interval shorter to speed up the test.
-	clientCtx.heartbeatInterval = 1 * time.Millisecond
+	clientCtx.SetHeartbeatInterval(1 * time.Millisecond)
 	clientCtx.RemoteClocks.offsetTTL = 5 * clientAdvancing.getAdvancementInterval()
 	if _, err := clientCtx.GRPCDialNode(remoteAddr, serverNodeID, DefaultClass).Connect(context.Background()); err != nil {
 		t.Fatal(err)
@@ -843,7 +897,7 @@ func TestRemoteOffsetUnhealthy(t *testing.T) {
 		clock := hlc.NewClock(start.Add(nodeCtxs[i].offset).UnixNano, maxOffset)
 		nodeCtxs[i].errChan = make(chan error, 1)
 		nodeCtxs[i].ctx = newTestContext(clusterID, clock, stopper)
-		nodeCtxs[i].ctx.heartbeatInterval = maxOffset
+		nodeCtxs[i].ctx.SetHeartbeatInterval(maxOffset)
 		nodeCtxs[i].ctx.NodeID.Set(context.TODO(), roachpb.NodeID(i+1))
 
 		s := newTestServer(t, nodeCtxs[i].ctx)
@@ -1062,7 +1116,7 @@ func grpcRunKeepaliveTestCase(testCtx context.Context, c grpcKeepaliveTestCase)
 	log.Infof(ctx, "setting up client")
 	clientCtx := newTestContext(clusterID, clock, stopper)
 	// Disable automatic heartbeats. We'll send them by hand.
-	clientCtx.heartbeatInterval = math.MaxInt64
+	clientCtx.SetHeartbeatInterval(math.MaxInt64)
 
 	var firstConn int32 = 1
 
@@ -1271,6 +1325,84 @@ func TestClusterIDMismatch(t *testing.T) {
 	wg.Wait()
 }
 
+// TestInsecureMismatch verifies that secure and insecure nodes refuse to
+// talk to each other, and that both sides log why, once per connection.
+func TestInsecureMismatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	clusterID := uuid.MakeV4()
+	clock := hlc.NewClock(timeutil.Unix(0, 20).UnixNano, time.Nanosecond)
+	serve := func(serverCtx *Context) string {
+		const serverNodeID = 1
+		serverCtx.NodeID.Set(context.TODO(), serverNodeID)
+		s := NewServer(serverCtx)
+		ln, err := netutil.ListenAndServeGRPC(serverCtx.Stopper, s, util.TestAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ln.Addr().String()
+	}
+
+	insecureServerCtx := newTestContext(clusterID, clock, stopper)
+	insecureServerCtx.Insecure = true
+	insecureAddr := serve(insecureServerCtx)
+	secureAddr := serve(newTestContext(clusterID, clock, stopper))
+
+	ctx := context.Background()
+	var mu syncutil.Mutex
+	var hints []string
+	log.Intercept(ctx, func(entry log.Entry) {
+		if strings.HasPrefix(entry.Message, "cannot connect to node at ") {
+			mu.Lock()
+			defer mu.Unlock()
+			hints = append(hints, entry.Message)
+		}
+	})
+	defer log.Intercept(ctx, nil)
+
+	testCases := []struct {
+		name     string
+		insecure bool
+		addr     string
+		hint     string
+	}{
+		{"secure client", false, insecureAddr, "does not use TLS"},
+		{"insecure client", true, secureAddr, "closed the connection"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			hints = nil
+			mu.Unlock()
+
+			clientCtx := newTestContext(clusterID, clock, stopper)
+			clientCtx.Insecure = tc.insecure
+			// Heartbeat often, so that several heartbeats fail on the
+			// connection before it is torn down.
+			clientCtx.SetHeartbeatInterval(time.Millisecond)
+			if _, err := clientCtx.GRPCDialNode(tc.addr, 1, DefaultClass).Connect(ctx); err == nil {
+				t.Fatal("expected the connection to fail")
+			}
+			// Wait for the heartbeat loop to give up on the connection.
+			testutils.SucceedsSoon(t, func() error {
+				if _, ok := clientCtx.conns.Load(connKey{tc.addr, 1, DefaultClass}); ok {
+					return errors.New("connection still open")
+				}
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(hints) != 1 || !strings.Contains(hints[0], tc.hint) {
+				t.Fatalf("expected a single hint containing %q, got %q", tc.hint, hints)
+			}
+		})
+	}
+}
+
 func TestClusterNameMismatch(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
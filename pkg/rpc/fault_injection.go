@@ -0,0 +1,285 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// Faults describes the faults injected by a FaultInjector into the traffic
+// sent from one node to another.
+type Faults struct {
+	// Partitioned fails every RPC, and every stream operation, with an
+	// Unavailable error.
+	Partitioned bool
+	// DropProbability is the probability of dropping a message. A dropped
+	// unary RPC fails with an Unavailable error, while a dropped stream
+	// message is silently discarded.
+	DropProbability float64
+	// Delay is added before each message is sent.
+	Delay time.Duration
+	// ReorderProbability is the probability of holding back a stream message
+	// until the next one has been sent.
+	ReorderProbability float64
+	// DuplicateProbability is the probability of sending a message twice.
+	// The response to the second copy of a unary RPC is discarded.
+	DuplicateProbability float64
+}
+
+type faultKey struct {
+	from, to string
+}
+
+// FaultInjector injects network faults between the nodes of an in-process
+// cluster, so that the behavior of heartbeats, gossip and Raft under
+// partitions and slow networks can be tested deterministically. Nodes are
+// identified by their RPC address, and the faults between each ordered pair
+// of nodes can be changed at any time.
+//
+// The injector plugs into the client side of the connections through
+// ContextTestingKnobs, see TestingKnobs. Faults from a node A to a node B
+// apply to the requests and stream messages A sends to B, and faults from B
+// to A to the responses and stream messages A receives from B. Random
+// decisions use a seeded source so that failures can be replayed.
+type FaultInjector struct {
+	mu struct {
+		syncutil.Mutex
+		rng    *rand.Rand
+		faults map[faultKey]Faults
+	}
+}
+
+// NewFaultInjector creates a FaultInjector which does not inject any
+// fault until one is set.
+func NewFaultInjector(seed int64) *FaultInjector {
+	fi := &FaultInjector{}
+	fi.mu.rng = rand.New(rand.NewSource(seed))
+	fi.mu.faults = make(map[faultKey]Faults)
+	return fi
+}
+
+// SetFaults replaces the faults injected into the traffic from one node to
+// another.
+func (fi *FaultInjector) SetFaults(from, to string, f Faults) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if f == (Faults{}) {
+		delete(fi.mu.faults, faultKey{from, to})
+		return
+	}
+	fi.mu.faults[faultKey{from, to}] = f
+}
+
+// Partition cuts the connections between two nodes in both directions.
+func (fi *FaultInjector) Partition(a, b string) {
+	fi.SetFaults(a, b, Faults{Partitioned: true})
+	fi.SetFaults(b, a, Faults{Partitioned: true})
+}
+
+// Heal removes all the faults between two nodes, in both directions.
+func (fi *FaultInjector) Heal(a, b string) {
+	fi.SetFaults(a, b, Faults{})
+	fi.SetFaults(b, a, Faults{})
+}
+
+// Reset removes all the faults.
+func (fi *FaultInjector) Reset() {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.mu.faults = make(map[faultKey]Faults)
+}
+
+// TestingKnobs returns the knobs to pass to the rpc.Context of the node
+// with the given RPC address. The address must be known before the node
+// starts, for example by passing a listener to the server.
+func (fi *FaultInjector) TestingKnobs(local string) ContextTestingKnobs {
+	return ContextTestingKnobs{
+		UnaryClientInterceptor: func(target string, _ ConnectionClass) grpc.UnaryClientInterceptor {
+			return fi.unaryInterceptor(local, target)
+		},
+		StreamClientInterceptor: func(target string, _ ConnectionClass) grpc.StreamClientInterceptor {
+			return fi.streamInterceptor(local, target)
+		},
+	}
+}
+
+// action is what to do with one message.
+type action struct {
+	fail      bool
+	drop      bool
+	delay     time.Duration
+	reorder   bool
+	duplicate bool
+}
+
+// decide draws the fate of a message sent from one node to another.
+func (fi *FaultInjector) decide(from, to string) action {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	f, ok := fi.mu.faults[faultKey{from, to}]
+	if !ok {
+		return action{}
+	}
+	chance := func(p float64) bool {
+		return p > 0 && fi.mu.rng.Float64() < p
+	}
+	return action{
+		fail:      f.Partitioned,
+		drop:      chance(f.DropProbability),
+		delay:     f.Delay,
+		reorder:   chance(f.ReorderProbability),
+		duplicate: chance(f.DuplicateProbability),
+	}
+}
+
+func errPartitioned(from, to string) error {
+	return grpcstatus.Errorf(codes.Unavailable, "injected fault: %s cannot reach %s", from, to)
+}
+
+// sleep waits for the delay of the action, or until the context is done.
+func (a action) sleep(ctx context.Context) error {
+	if a.delay == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(a.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (fi *FaultInjector) unaryInterceptor(local, target string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		a := fi.decide(local, target)
+		if a.fail || a.drop {
+			return errPartitioned(local, target)
+		}
+		if err := a.sleep(ctx); err != nil {
+			return err
+		}
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+		if a.duplicate {
+			dup := protoutil.Clone(reply.(protoutil.Message))
+			_ = invoker(ctx, method, req, dup, cc, opts...)
+		}
+		// Apply the faults of the reverse direction to the response.
+		ra := fi.decide(target, local)
+		if ra.fail || ra.drop {
+			return errPartitioned(target, local)
+		}
+		return ra.sleep(ctx)
+	}
+}
+
+func (fi *FaultInjector) streamInterceptor(local, target string) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if a := fi.decide(local, target); a.fail {
+			return nil, errPartitioned(local, target)
+		}
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &faultyClientStream{ClientStream: cs, fi: fi, local: local, target: target}, nil
+	}
+}
+
+// faultyClientStream applies the faults of a FaultInjector to the messages
+// of a stream. Messages are cloned before being held back or duplicated,
+// since callers may reuse them once SendMsg returns.
+type faultyClientStream struct {
+	grpc.ClientStream
+	fi            *FaultInjector
+	local, target string
+	// held is a message held back to be sent after the next one.
+	held interface{}
+}
+
+func (s *faultyClientStream) SendMsg(m interface{}) error {
+	a := s.fi.decide(s.local, s.target)
+	if a.fail {
+		return errPartitioned(s.local, s.target)
+	}
+	if a.drop {
+		return nil
+	}
+	if err := a.sleep(s.Context()); err != nil {
+		return err
+	}
+	if a.reorder && s.held == nil {
+		s.held = protoutil.Clone(m.(protoutil.Message))
+		return nil
+	}
+	if err := s.ClientStream.SendMsg(m); err != nil {
+		return err
+	}
+	if a.duplicate {
+		if err := s.ClientStream.SendMsg(m); err != nil {
+			return err
+		}
+	}
+	if held := s.held; held != nil {
+		s.held = nil
+		return s.ClientStream.SendMsg(held)
+	}
+	return nil
+}
+
+func (s *faultyClientStream) CloseSend() error {
+	if held := s.held; held != nil {
+		s.held = nil
+		if err := s.ClientStream.SendMsg(held); err != nil {
+			return err
+		}
+	}
+	return s.ClientStream.CloseSend()
+}
+
+func (s *faultyClientStream) RecvMsg(m interface{}) error {
+	for {
+		a := s.fi.decide(s.target, s.local)
+		if a.fail {
+			return errPartitioned(s.target, s.local)
+		}
+		if err := s.ClientStream.RecvMsg(m); err != nil {
+			return err
+		}
+		if a.drop {
+			continue
+		}
+		return a.sleep(s.Context())
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/netutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const (
+	faultTestUnaryMethod  = "/cockroach.rpc.Testing/Foo"
+	faultTestStreamMethod = "/cockroach.rpc.Testing/Bar"
+)
+
+func TestFaultInjector(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+	clusterID := uuid.MakeV4()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+
+	// The server records the sequence numbers, carried in MaxSpanRequestKeys,
+	// of the requests it receives, and answers each stream once the client is
+	// done sending.
+	var mu syncutil.Mutex
+	var received []int64
+	serverCtx := newTestContext(clusterID, clock, stopper)
+	s := newTestServer(t, serverCtx, grpc.UnknownServiceHandler(
+		func(srv interface{}, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			for {
+				var ba roachpb.BatchRequest
+				if err := stream.RecvMsg(&ba); err == io.EOF {
+					break
+				} else if err != nil {
+					return err
+				}
+				mu.Lock()
+				received = append(received, ba.MaxSpanRequestKeys)
+				mu.Unlock()
+				if method == faultTestUnaryMethod {
+					break
+				}
+			}
+			return stream.SendMsg(&roachpb.BatchResponse{})
+		},
+	))
+	ln, err := netutil.ListenAndServeGRPC(serverCtx.Stopper, s, util.TestAddr)
+	require.NoError(t, err)
+	remoteAddr := ln.Addr().String()
+
+	const local = "client"
+	fi := NewFaultInjector(0 /* seed */)
+	knobs := fi.TestingKnobs(local)
+	knobs.ClusterID = &clusterID
+	clientCtx := newTestContextWithKnobs(clock, stopper, knobs)
+	conn, _, err := clientCtx.GRPCDialRaw(remoteAddr)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	ctx := context.Background()
+	takeReceived := func() []int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		r := received
+		received = nil
+		return r
+	}
+	unary := func(seq int64) error {
+		ba := roachpb.BatchRequest{Header: roachpb.Header{MaxSpanRequestKeys: seq}}
+		return conn.Invoke(ctx, faultTestUnaryMethod, &ba, &roachpb.BatchResponse{})
+	}
+	stream := func(seqs ...int64) error {
+		desc := grpc.StreamDesc{StreamName: "bar", ClientStreams: true}
+		cs, err := conn.NewStream(ctx, &desc, faultTestStreamMethod)
+		if err != nil {
+			return err
+		}
+		for _, seq := range seqs {
+			if err := cs.SendMsg(&roachpb.BatchRequest{
+				Header: roachpb.Header{MaxSpanRequestKeys: seq},
+			}); err != nil {
+				return err
+			}
+		}
+		if err := cs.CloseSend(); err != nil {
+			return err
+		}
+		return cs.RecvMsg(&roachpb.BatchResponse{})
+	}
+	requireUnavailable := func(err error) {
+		t.Helper()
+		require.Equal(t, codes.Unavailable, grpcstatus.Code(err), "%v", err)
+	}
+
+	require.NoError(t, unary(1))
+	require.NoError(t, stream(2, 3))
+	require.Equal(t, []int64{1, 2, 3}, takeReceived())
+
+	// A partition fails RPCs in either direction.
+	fi.Partition(local, remoteAddr)
+	requireUnavailable(unary(1))
+	requireUnavailable(stream(1))
+	require.Empty(t, takeReceived())
+	fi.SetFaults(local, remoteAddr, Faults{})
+	requireUnavailable(unary(1))
+	require.Equal(t, []int64{1}, takeReceived())
+	fi.Heal(local, remoteAddr)
+	require.NoError(t, unary(1))
+	require.Equal(t, []int64{1}, takeReceived())
+
+	fi.SetFaults(local, remoteAddr, Faults{DuplicateProbability: 1})
+	require.NoError(t, stream(1, 2))
+	require.Equal(t, []int64{1, 1, 2, 2}, takeReceived())
+
+	fi.SetFaults(local, remoteAddr, Faults{ReorderProbability: 1})
+	require.NoError(t, stream(1, 2, 3, 4, 5))
+	require.Equal(t, []int64{2, 1, 4, 3, 5}, takeReceived())
+
+	fi.SetFaults(local, remoteAddr, Faults{DropProbability: 1})
+	require.NoError(t, stream(1, 2))
+	requireUnavailable(unary(3))
+	require.Empty(t, takeReceived())
+
+	const delay = 50 * time.Millisecond
+	fi.SetFaults(local, remoteAddr, Faults{Delay: delay})
+	start := timeutil.Now()
+	require.NoError(t, unary(1))
+	require.True(t, timeutil.Since(start) >= delay)
+
+	// Faults between other nodes do not apply to this connection.
+	fi.Reset()
+	fi.Partition("n2", remoteAddr)
+	require.NoError(t, unary(1))
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"google.golang.org/grpc/encoding"
+	encodingproto "google.golang.org/grpc/encoding/proto"
+)
+
+// maxFuzzMessageSize bounds the size of decompressed messages, like the
+// default maximum message size of gRPC servers.
+const maxFuzzMessageSize = 4 << 20
+
+var (
+	// fuzzCodec is the growStackCodec installed over the default proto codec.
+	fuzzCodec = encoding.GetCodec(encodingproto.Name)
+	fuzzCtx   = context.Background()
+
+	fuzzHeartbeatOnce sync.Once
+	fuzzHeartbeat     *HeartbeatService
+)
+
+// getFuzzHeartbeat returns the heartbeat service answering fuzzed pings,
+// created on first use so that binaries which don't fuzz never build it.
+// Its clock has no maximum offset, since a mismatch between the offsets of
+// two nodes deliberately crashes the server.
+func getFuzzHeartbeat() *HeartbeatService {
+	fuzzHeartbeatOnce.Do(func() {
+		clock := hlc.NewClock(hlc.UnixNano, 0 /* maxOffset */)
+		clusterID := &base.ClusterIDContainer{}
+		clusterID.Set(fuzzCtx, uuid.MakeV4())
+		nodeID := &base.NodeIDContainer{}
+		nodeID.Set(fuzzCtx, 1)
+		fuzzHeartbeat = &HeartbeatService{
+			clock:              clock,
+			remoteClockMonitor: newRemoteClockMonitor(clock, time.Hour, 0 /* histogramWindowInterval */),
+			clusterID:          clusterID,
+			nodeID:             nodeID,
+			settings:           cluster.MakeTestingClusterSettings(),
+		}
+	})
+	return fuzzHeartbeat
+}
+
+// decodeFuzzMessage decodes data as the body of a gRPC message. The first
+// byte is the compressed flag of the message, and the remainder its
+// payload, snappy compressed if the flag is set.
+func decodeFuzzMessage(data []byte, msg interface{}) bool {
+	if len(data) == 0 {
+		return false
+	}
+	var r io.Reader = bytes.NewReader(data[1:])
+	if data[0] != 0 {
+		var err error
+		if r, err = (snappyCompressor{}).Decompress(r); err != nil {
+			return false
+		}
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, maxFuzzMessageSize+1))
+	if err != nil || len(b) > maxFuzzMessageSize {
+		return false
+	}
+	return fuzzCodec.Unmarshal(b, msg) == nil
+}
+
+// FuzzDecodeBatchRequest decodes data as a BatchRequest received by a node.
+func FuzzDecodeBatchRequest(data []byte) int {
+	var ba roachpb.BatchRequest
+	if !decodeFuzzMessage(data, &ba) {
+		return 0
+	}
+	return 1
+}
+
+// FuzzPing decodes data as the PingRequest which starts every connection and
+// answers it.
+func FuzzPing(data []byte) int {
+	var args PingRequest
+	if !decodeFuzzMessage(data, &args) {
+		return 0
+	}
+	if _, err := getFuzzHeartbeat().Ping(fuzzCtx, &args); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzPingResponse decodes data as the PingResponse received by the node
+// dialing a connection, and validates it the way the heartbeat loop does.
+func FuzzPingResponse(data []byte) int {
+	var resp PingResponse
+	if !decodeFuzzMessage(data, &resp) {
+		return 0
+	}
+	if !resp.DisableClusterNameVerification {
+		if err := checkClusterName("" /* clusterName */, resp.ClusterName); err != nil {
+			return 0
+		}
+	}
+	if err := checkVersion(fuzzCtx, getFuzzHeartbeat().settings, resp.ServerVersion); err != nil {
+		return 0
+	}
+	return 1
+}
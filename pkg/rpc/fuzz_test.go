@@ -0,0 +1,60 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestFuzzSeeds feeds the checked in seed inputs to their Fuzz funcs, so
+// that changes to the wire format which break the seeds, or the harnesses,
+// are caught without running the fuzzer. Every seed is a valid message.
+func TestFuzzSeeds(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fuzzFuncs := map[string]func([]byte) int{
+		"FuzzDecodeBatchRequest": FuzzDecodeBatchRequest,
+		"FuzzPing":               FuzzPing,
+		"FuzzPingResponse":       FuzzPingResponse,
+	}
+	dirs, err := ioutil.ReadDir(filepath.Join("testdata", "fuzz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range dirs {
+		fn, ok := fuzzFuncs[dir.Name()]
+		if !ok {
+			t.Fatalf("no Fuzz func for the seeds of %s", dir.Name())
+		}
+		t.Run(dir.Name(), func(t *testing.T) {
+			seeds, err := filepath.Glob(filepath.Join("testdata", "fuzz", dir.Name(), "*"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(seeds) == 0 {
+				t.Fatal("no seeds")
+			}
+			for _, seed := range seeds {
+				data, err := ioutil.ReadFile(seed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res := fn(data); res != 1 {
+					t.Errorf("%s: expected 1, got %d", filepath.Base(seed), res)
+				}
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/peer"
 )
 
 func (r RemoteOffset) measuredAt() time.Time {
@@ -95,6 +97,19 @@ func checkVersion(ctx context.Context, st *cluster.Settings, peerVersion roachpb
 	return nil
 }
 
+// isPingFromNode returns whether a ping comes from another node, rather than
+// from a client of the KV API. Pings which did not come over the network,
+// such as the ones sent directly to the service by tests, count as coming
+// from a node. Callers without a verified client certificate were already
+// turned away by the server's interceptor.
+func isPingFromNode(ctx context.Context) bool {
+	if _, ok := peer.FromContext(ctx); !ok {
+		return true
+	}
+	superUser, _, err := isSuperUserRequest(ctx)
+	return err == nil && superUser
+}
+
 // Ping echos the contents of the request to the response, and returns the
 // server's current clock value, allowing the requester to measure its clock.
 // The requester should also estimate its offset from this server along
@@ -141,26 +156,32 @@ func (hs *HeartbeatService) Ping(ctx context.Context, args *PingRequest) (*PingR
 		return nil, errors.Wrap(err, "version compatibility check failed on ping request")
 	}
 
-	// Enforce that clock max offsets are identical between nodes.
-	// Commit suicide in the event that this is ever untrue.
-	// This check is ignored if either offset is set to 0 (for unittests).
-	// Note that we validated this connection already. Different clusters
-	// could very well have different max offsets.
-	mo, amo := hs.clock.MaxOffset(), time.Duration(args.MaxOffsetNanos)
-	if mo != 0 && amo != 0 && mo != amo {
-		panic(fmt.Sprintf("locally configured maximum clock offset (%s) "+
-			"does not match that of node %s (%s)", mo, args.Addr, amo))
-	}
+	// Only other nodes, which authenticate as the node or root user, take
+	// part in clock offset tracking. Heartbeats from the clients of the KV
+	// API must not be able to crash the node or skew its view of the clocks.
+	if isPingFromNode(ctx) {
+		// Enforce that clock max offsets are identical between nodes.
+		// Commit suicide in the event that this is ever untrue.
+		// This check is ignored if either offset is set to 0 (for unittests).
+		// Note that we validated this connection already. Different clusters
+		// could very well have different max offsets.
+		mo, amo := hs.clock.MaxOffset(), time.Duration(args.MaxOffsetNanos)
+		if mo != 0 && amo != 0 && mo != amo {
+			panic(fmt.Sprintf("locally configured maximum clock offset (%s) "+
+				"does not match that of node %s (%s)", mo, args.Addr, amo))
+		}
 
-	serverOffset := args.Offset
-	// The server offset should be the opposite of the client offset.
-	serverOffset.Offset = -serverOffset.Offset
-	hs.remoteClockMonitor.UpdateOffset(ctx, args.Addr, serverOffset, 0 /* roundTripLatency */)
+		serverOffset := args.Offset
+		// The server offset should be the opposite of the client offset.
+		serverOffset.Offset = -serverOffset.Offset
+		hs.remoteClockMonitor.UpdateOffset(ctx, args.Addr, serverOffset, 0 /* roundTripLatency */)
+	}
 	return &PingResponse{
 		Pong:                           args.Ping,
 		ServerTime:                     hs.clock.PhysicalNow(),
 		ServerVersion:                  hs.settings.Version.BinaryVersion(),
 		ClusterName:                    hs.clusterName,
 		DisableClusterNameVerification: hs.disableClusterNameVerification,
+		BuildTag:                       build.GetInfo().Tag,
 	}, nil
 }
@@ -12,12 +12,16 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -25,6 +29,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 )
 
 func TestRemoteOffsetString(t *testing.T) {
@@ -68,6 +74,10 @@ func TestHeartbeatReply(t *testing.T) {
 	if response.ServerTime != 5 {
 		t.Errorf("expected server time 5, instead %d", response.ServerTime)
 	}
+
+	if tag := build.GetInfo().Tag; response.BuildTag != tag {
+		t.Errorf("expected build tag %s, instead %s", tag, response.BuildTag)
+	}
 }
 
 // A ManualHeartbeatService allows manual control of when heartbeats occur.
@@ -181,6 +191,45 @@ func TestClockOffsetMismatch(t *testing.T) {
 	t.Fatalf("should not have reached but got response=%v err=%v", response, err)
 }
 
+// TestHeartbeatFromUserIgnoresClockOffset verifies that the heartbeats of
+// clients authenticated as users other than node or root do not take part in
+// clock offset tracking, so that they can neither crash the node nor skew its
+// view of the clocks.
+func TestHeartbeatFromUserIgnoresClockOffset(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	clock := hlc.NewClock(hlc.UnixNano, 250*time.Millisecond)
+	st := cluster.MakeTestingClusterSettings()
+	hs := &HeartbeatService{
+		clock:              clock,
+		remoteClockMonitor: newRemoteClockMonitor(clock, time.Hour, 0),
+		clusterID:          &base.ClusterIDContainer{},
+		settings:           st,
+	}
+
+	userCert := &x509.Certificate{Subject: pkix.Name{CommonName: "testuser"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{userCert}},
+		},
+	})
+	request := &PingRequest{
+		Ping:           "testUser",
+		Addr:           "test",
+		Offset:         RemoteOffset{Offset: int64(time.Hour), MeasuredAt: clock.PhysicalNow()},
+		MaxOffsetNanos: (500 * time.Millisecond).Nanoseconds(),
+		ServerVersion:  st.Version.BinaryVersion(),
+	}
+	if _, err := hs.Ping(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	hs.remoteClockMonitor.mu.Lock()
+	defer hs.remoteClockMonitor.mu.Unlock()
+	if n := len(hs.remoteClockMonitor.mu.offsets); n != 0 {
+		t.Fatalf("expected no offset to be recorded, found %d", n)
+	}
+}
+
 func TestClusterIDCompare(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	uuid1, uuid2 := uuid.MakeV4(), uuid.MakeV4()
@@ -124,7 +124,7 @@ func TestStatsHandlerWithHeartbeats(t *testing.T) {
 
 	clientCtx := newTestContext(clusterID, clock, stopper)
 	// Make the interval shorter to speed up the test.
-	clientCtx.heartbeatInterval = 1 * time.Millisecond
+	clientCtx.SetHeartbeatInterval(1 * time.Millisecond)
 	go func() { heartbeat.ready <- nil }()
 	if _, err := clientCtx.GRPCDialNode(remoteAddr, serverNodeID, DefaultClass).
 		Connect(context.Background()); err != nil {
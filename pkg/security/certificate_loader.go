@@ -60,6 +60,11 @@ func SetAssetLoader(al AssetLoader) {
 	assetLoaderImpl = al
 }
 
+// GetAssetLoader returns the current asset loader.
+func GetAssetLoader() AssetLoader {
+	return assetLoaderImpl
+}
+
 // ResetAssetLoader restores the asset loader to the default value.
 func ResetAssetLoader() {
 	assetLoaderImpl = defaultAssetLoader
@@ -221,6 +226,7 @@ func CertInfoFromFilename(filename string) (*CertInfo, error) {
 // CertificateLoader searches for certificates and keys in the certs directory.
 type CertificateLoader struct {
 	certsDir             string
+	assets               AssetLoader
 	skipPermissionChecks bool
 	certificates         []*CertInfo
 }
@@ -234,6 +240,7 @@ func (cl *CertificateLoader) Certificates() []*CertInfo {
 func NewCertificateLoader(certsDir string) *CertificateLoader {
 	return &CertificateLoader{
 		certsDir:             certsDir,
+		assets:               assetLoaderImpl,
 		skipPermissionChecks: skipPermissionChecks,
 		certificates:         make([]*CertInfo, 0),
 	}
@@ -270,7 +277,7 @@ func (cl *CertificateLoader) TestDisablePermissionChecks() {
 // usage, and looks for their keys.
 // It populates the certificates field.
 func (cl *CertificateLoader) Load() error {
-	fileInfos, err := assetLoaderImpl.ReadDir(cl.certsDir)
+	fileInfos, err := cl.assets.ReadDir(cl.certsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Directory does not exist.
@@ -315,7 +322,7 @@ func (cl *CertificateLoader) Load() error {
 
 		// Read the cert file contents.
 		fullCertPath := filepath.Join(cl.certsDir, filename)
-		certPEMBlock, err := assetLoaderImpl.ReadFile(fullCertPath)
+		certPEMBlock, err := cl.assets.ReadFile(fullCertPath)
 		if err != nil {
 			log.Warningf(context.Background(), "could not read certificate file %s: %v", fullPath, err)
 		}
@@ -351,7 +358,7 @@ func (cl *CertificateLoader) findKey(ci *CertInfo) error {
 	fullKeyPath := filepath.Join(cl.certsDir, keyFilename)
 
 	// Stat the file. This follows symlinks.
-	info, err := assetLoaderImpl.Stat(fullKeyPath)
+	info, err := cl.assets.Stat(fullKeyPath)
 	if err != nil {
 		return errors.Errorf("could not stat key file %s: %v", fullKeyPath, err)
 	}
@@ -372,7 +379,7 @@ func (cl *CertificateLoader) findKey(ci *CertInfo) error {
 	}
 
 	// Read key file.
-	keyPEMBlock, err := assetLoaderImpl.ReadFile(fullKeyPath)
+	keyPEMBlock, err := cl.assets.ReadFile(fullKeyPath)
 	if err != nil {
 		return errors.Errorf("could not read key file %s: %v", fullKeyPath, err)
 	}
@@ -13,15 +13,18 @@ package security
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/sysutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/pkg/errors"
 )
 
@@ -62,6 +65,42 @@ var (
 		Measurement: "Certificate Expiration",
 		Unit:        metric.Unit_TIMESTAMP_SEC,
 	}
+	metaCADaysUntilExpiration = metric.Metadata{
+		Name:        "security.certificate.days_until_expiration.ca",
+		Help:        "Days until the CA certificate expires, negative once expired. 0 means no certificate or error.",
+		Measurement: "Days",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaClientCADaysUntilExpiration = metric.Metadata{
+		Name:        "security.certificate.days_until_expiration.client-ca",
+		Help:        "Days until the client CA certificate expires, negative once expired. 0 means no certificate or error.",
+		Measurement: "Days",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaUICADaysUntilExpiration = metric.Metadata{
+		Name:        "security.certificate.days_until_expiration.ui-ca",
+		Help:        "Days until the UI CA certificate expires, negative once expired. 0 means no certificate or error.",
+		Measurement: "Days",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaNodeDaysUntilExpiration = metric.Metadata{
+		Name:        "security.certificate.days_until_expiration.node",
+		Help:        "Days until the node certificate expires, negative once expired. 0 means no certificate or error.",
+		Measurement: "Days",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaNodeClientDaysUntilExpiration = metric.Metadata{
+		Name:        "security.certificate.days_until_expiration.node-client",
+		Help:        "Days until the node's client certificate expires, negative once expired. 0 means no certificate or error.",
+		Measurement: "Days",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaUIDaysUntilExpiration = metric.Metadata{
+		Name:        "security.certificate.days_until_expiration.ui",
+		Help:        "Days until the UI certificate expires, negative once expired. 0 means no certificate or error.",
+		Measurement: "Days",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // CertificateManager lives for the duration of the process and manages certificates and keys.
@@ -84,9 +123,16 @@ var (
 // - client.<user>.crt  client certificate for 'user'. Verified using 'ca.crt', or 'ca-client.crt'.
 // - client.node.crt    client certificate for the 'node' user. If it does not exist,
 //                      fall back on 'node.crt'.
+// - ca.crl             optional: PEM-encoded revocation lists signed by 'ca.crt' or
+//                      'ca-client.crt'. Peer certificates listed in it are rejected.
 type CertificateManager struct {
 	// Certificate directory is not modified after initialization.
 	certsDir string
+	// Source of the certificate and key files. If nil, the package-level
+	// asset loader is used.
+	assets *AssetLoader
+	// Source of the current time when checking for expiring certificates.
+	now func() time.Time
 	// The metrics struct is initialized at init time and metrics do their
 	// own locking.
 	certMetrics CertificateMetrics
@@ -97,6 +143,9 @@ type CertificateManager struct {
 	// If false, this is the first load. Needed to ensure we do not drop certain certs.
 	initialized bool
 
+	// Overrides applied to every TLS config built by the manager.
+	tlsSettings TLSSettings
+
 	// Set of certs. These are swapped in during Load(), and never mutated afterwards.
 	caCert         *CertInfo // default CA certificate
 	clientCACert   *CertInfo // optional: certificate to verify client certificates
@@ -106,6 +155,15 @@ type CertificateManager struct {
 	uiCert         *CertInfo // optional: server certificate for the admin UI.
 	clientCerts    map[string]*CertInfo
 
+	// Called after every successful reload of the certificates requested
+	// through SIGHUP. See SetOnReload.
+	onReload func()
+
+	// Revoked certificates. Nil if there is no CRL file. Consulted on every
+	// handshake, so unlike the TLS configs it can be swapped without a reload,
+	// except when it appears or disappears (see getEmbeddedServerTLSConfig).
+	crl revocationList
+
 	// TLS configs. Initialized lazily. Wiped on every successful Load().
 	// Server-side config.
 	serverConfig *tls.Config
@@ -114,6 +172,13 @@ type CertificateManager struct {
 	// Client-side config for the cockroach node.
 	// All other client tls.Config objects are built as requested and not cached.
 	clientConfig *tls.Config
+
+	// The connections registered with TrackConn, which are closed when the
+	// revocation list revokes the certificate of their peer.
+	conns struct {
+		syncutil.Mutex
+		m map[*revocableConn]struct{}
+	}
 }
 
 // CertificateMetrics holds metrics about the various certificates.
@@ -126,10 +191,19 @@ type CertificateMetrics struct {
 	NodeExpiration       *metric.Gauge
 	NodeClientExpiration *metric.Gauge
 	UIExpiration         *metric.Gauge
+
+	// The days until each certificate expires, refreshed on reload and by the
+	// daily expiry check.
+	CADaysUntilExpiration         *metric.Gauge
+	ClientCADaysUntilExpiration   *metric.Gauge
+	UICADaysUntilExpiration       *metric.Gauge
+	NodeDaysUntilExpiration       *metric.Gauge
+	NodeClientDaysUntilExpiration *metric.Gauge
+	UIDaysUntilExpiration         *metric.Gauge
 }
 
 func makeCertificateManager(certsDir string) *CertificateManager {
-	cm := &CertificateManager{certsDir: os.ExpandEnv(certsDir)}
+	cm := &CertificateManager{certsDir: os.ExpandEnv(certsDir), now: timeutil.Now}
 	// Initialize metrics:
 	cm.certMetrics = CertificateMetrics{
 		CAExpiration:         metric.NewGauge(metaCAExpiration),
@@ -138,16 +212,54 @@ func makeCertificateManager(certsDir string) *CertificateManager {
 		NodeExpiration:       metric.NewGauge(metaNodeExpiration),
 		NodeClientExpiration: metric.NewGauge(metaNodeClientExpiration),
 		UIExpiration:         metric.NewGauge(metaUIExpiration),
+
+		CADaysUntilExpiration:         metric.NewGauge(metaCADaysUntilExpiration),
+		ClientCADaysUntilExpiration:   metric.NewGauge(metaClientCADaysUntilExpiration),
+		UICADaysUntilExpiration:       metric.NewGauge(metaUICADaysUntilExpiration),
+		NodeDaysUntilExpiration:       metric.NewGauge(metaNodeDaysUntilExpiration),
+		NodeClientDaysUntilExpiration: metric.NewGauge(metaNodeClientDaysUntilExpiration),
+		UIDaysUntilExpiration:         metric.NewGauge(metaUIDaysUntilExpiration),
 	}
 	return cm
 }
 
+// Option is an option to NewCertificateManager.
+type Option func(*CertificateManager)
+
+// WithAssetLoader makes the certificate manager read the certificates, keys
+// and revocation list through the given loader instead of the filesystem.
+// See SecretStoreAssetLoader.
+func WithAssetLoader(al AssetLoader) Option {
+	return func(cm *CertificateManager) {
+		cm.assets = &al
+	}
+}
+
+// WithClock makes the certificate manager read the current time from the
+// given function when checking for expiring certificates.
+func WithClock(now func() time.Time) Option {
+	return func(cm *CertificateManager) {
+		cm.now = now
+	}
+}
+
 // NewCertificateManager creates a new certificate manager.
-func NewCertificateManager(certsDir string) (*CertificateManager, error) {
+func NewCertificateManager(certsDir string, opts ...Option) (*CertificateManager, error) {
 	cm := makeCertificateManager(certsDir)
+	for _, o := range opts {
+		o(cm)
+	}
 	return cm, cm.LoadCertificates()
 }
 
+// assetLoader returns the loader used to read certificates and keys.
+func (cm *CertificateManager) assetLoader() AssetLoader {
+	if cm.assets != nil {
+		return *cm.assets
+	}
+	return assetLoaderImpl
+}
+
 // NewCertificateManagerFirstRun creates a new certificate manager.
 // The certsDir is created if it does not exist.
 // This should only be called when generating certificates, the server has
@@ -161,37 +273,130 @@ func NewCertificateManagerFirstRun(certsDir string) (*CertificateManager, error)
 	return cm, cm.LoadCertificates()
 }
 
+// SetTLSSettings overrides the TLS version and cipher suites of all TLS
+// configs returned from now on.
+func (cm *CertificateManager) SetTLSSettings(s TLSSettings) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.tlsSettings = s
+	cm.serverConfig = nil
+	cm.uiServerConfig = nil
+	cm.clientConfig = nil
+}
+
 // Metrics returns the metrics struct.
 func (cm *CertificateManager) Metrics() CertificateMetrics {
 	return cm.certMetrics
 }
 
 // RegisterSignalHandler registers a signal handler for SIGHUP, triggering a
-// refresh of the certificates directory on notification.
+// refresh of the certificates directory on notification. The certificate
+// revocation list is also reloaded periodically, and certificates close to
+// expiry are reported in the logs (see CheckCertificateExpiry).
 func (cm *CertificateManager) RegisterSignalHandler(stopper *stop.Stopper) {
 	go func() {
 		ch := sysutil.RefreshSignaledChan()
+		crlTicker := time.NewTicker(crlRefreshInterval)
+		defer crlTicker.Stop()
+		expiryTicker := time.NewTicker(certExpiryCheckInterval)
+		defer expiryTicker.Stop()
+		cm.CheckCertificateExpiry(context.Background())
 		for {
 			select {
 			case <-stopper.ShouldStop():
 				return
+			case <-expiryTicker.C:
+				cm.CheckCertificateExpiry(context.Background())
+			case <-crlTicker.C:
+				if err := cm.reloadRevocationList(); err != nil {
+					log.Warningf(context.Background(), "could not reload certificate revocation list: %v", err)
+				}
 			case sig := <-ch:
 				log.Infof(context.Background(), "received signal %q, triggering certificate reload", sig)
 				if err := cm.LoadCertificates(); err != nil {
 					log.Warningf(context.Background(), "could not reload certificates: %v", err)
 				} else {
 					log.Info(context.Background(), "successfully reloaded certificates")
+					cm.CheckCertificateExpiry(context.Background())
+					cm.mu.RLock()
+					onReload := cm.onReload
+					cm.mu.RUnlock()
+					if onReload != nil {
+						onReload()
+					}
 				}
 			}
 		}
 	}()
 }
 
+// SetOnReload installs a function to call after every successful reload of
+// the certificates requested through SIGHUP. It replaces any previous one.
+func (cm *CertificateManager) SetOnReload(fn func()) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.onReload = fn
+}
+
+const (
+	// certExpiryCheckInterval is how often certificates are checked for
+	// upcoming expiry.
+	certExpiryCheckInterval = 24 * time.Hour
+	// Certificates expiring within certExpiryWarningPeriod are logged as a
+	// warning, and as an error within certExpiryErrorPeriod.
+	certExpiryWarningPeriod = 30 * 24 * time.Hour
+	certExpiryErrorPeriod   = 7 * 24 * time.Hour
+)
+
+// CheckCertificateExpiry refreshes the days-until-expiry metrics and logs
+// each loaded certificate which expires soon, with a severity that increases
+// as expiry approaches.
+func (cm *CertificateManager) CheckCertificateExpiry(ctx context.Context) {
+	now := cm.now()
+	cm.mu.RLock()
+	cm.updateDaysUntilExpirationLocked(now)
+	cm.mu.RUnlock()
+	for _, ci := range cm.ExpiringCertificates(now.Add(certExpiryWarningPeriod)) {
+		remaining := ci.ExpirationTime.Sub(now)
+		switch {
+		case remaining <= 0:
+			log.Errorf(ctx, "certificate %s expired at %s", ci.Filename, ci.ExpirationTime)
+		case remaining < certExpiryErrorPeriod:
+			log.Errorf(ctx, "certificate %s expires in %s, at %s", ci.Filename,
+				remaining.Round(time.Minute), ci.ExpirationTime)
+		default:
+			log.Warningf(ctx, "certificate %s expires in %s, at %s", ci.Filename,
+				remaining.Round(time.Hour), ci.ExpirationTime)
+		}
+	}
+}
+
+// ExpiringCertificates returns the loaded certificates which expire before
+// the given time, including those which have already expired.
+func (cm *CertificateManager) ExpiringCertificates(before time.Time) []*CertInfo {
+	certs, err := cm.ListCertificates()
+	if err != nil {
+		return nil
+	}
+	var expiring []*CertInfo
+	for _, ci := range certs {
+		if ci.Error == nil && ci.ExpirationTime.Before(before) {
+			expiring = append(expiring, ci)
+		}
+	}
+	return expiring
+}
+
 // CACertPath returns the expected file path for the CA certificate.
 func (cm *CertificateManager) CACertPath() string {
 	return filepath.Join(cm.certsDir, CACertFilename())
 }
 
+// CRLPath returns the expected file path for the certificate revocation list.
+func (cm *CertificateManager) CRLPath() string {
+	return filepath.Join(cm.certsDir, "ca"+crlExtension)
+}
+
 // CACertFilename returns the expected file name for the CA certificate.
 func CACertFilename() string { return "ca" + certExtension }
 
@@ -325,6 +530,7 @@ func makeError(err error, s string) *Error { return makeErrorf(err, "%s", s) }
 // Upon success, it swaps the existing certificates for the new ones.
 func (cm *CertificateManager) LoadCertificates() error {
 	cl := NewCertificateLoader(cm.certsDir)
+	cl.assets = cm.assetLoader()
 	if err := cl.Load(); err != nil {
 		return makeErrorf(err, "problem loading certs directory %s", cm.certsDir)
 	}
@@ -351,6 +557,10 @@ func (cm *CertificateManager) LoadCertificates() error {
 		}
 	}
 
+	// Connections established before the reload may have been revoked. This
+	// runs once cm.mu is released.
+	defer cm.closeRevokedConns(context.Background())
+
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 	if cm.initialized {
@@ -383,6 +593,11 @@ func (cm *CertificateManager) LoadCertificates() error {
 		}
 	}
 
+	crl, err := loadRevocationList(cm.assetLoader(), cm.CRLPath(), caCert, clientCACert)
+	if err != nil {
+		return makeError(err, "problem loading certificate revocation list")
+	}
+
 	// Swap everything.
 	cm.caCert = caCert
 	cm.clientCACert = clientCACert
@@ -392,6 +607,7 @@ func (cm *CertificateManager) LoadCertificates() error {
 	cm.nodeClientCert = nodeClientCert
 	cm.uiCert = uiCert
 	cm.clientCerts = clientCerts
+	cm.crl = crl
 
 	cm.initialized = true
 
@@ -403,6 +619,41 @@ func (cm *CertificateManager) LoadCertificates() error {
 	return nil
 }
 
+// reloadRevocationList reloads the certificate revocation list, verifying it
+// against the currently loaded CA certificates.
+func (cm *CertificateManager) reloadRevocationList() error {
+	cm.mu.RLock()
+	caCert, clientCACert := cm.caCert, cm.clientCACert
+	cm.mu.RUnlock()
+
+	crl, err := loadRevocationList(cm.assetLoader(), cm.CRLPath(), caCert, clientCACert)
+	if err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	if (cm.crl == nil) != (crl == nil) {
+		// The server config enables session tickets only without a CRL.
+		cm.serverConfig = nil
+	}
+	cm.crl = crl
+	cm.mu.Unlock()
+
+	cm.closeRevokedConns(context.Background())
+	return nil
+}
+
+// verifyPeerCertificate implements tls.Config.VerifyPeerCertificate. It
+// rejects peers presenting a revoked certificate.
+func (cm *CertificateManager) verifyPeerCertificate(
+	_ [][]byte, verifiedChains [][]*x509.Certificate,
+) error {
+	cm.mu.RLock()
+	crl := cm.crl
+	cm.mu.RUnlock()
+	return crl.checkNotRevoked(verifiedChains)
+}
+
 // updateMetricsLocked updates the values on the certificate metrics.
 // The metrics may not exist (eg: in tests that build their own CertificateManager).
 // If the corresponding certificate is missing or invalid (Error != nil), we reset the
@@ -439,6 +690,32 @@ func (cm *CertificateManager) updateMetricsLocked() {
 
 	// UI certificate expiration.
 	maybeSetMetric(cm.certMetrics.UIExpiration, cm.uiCert)
+
+	cm.updateDaysUntilExpirationLocked(cm.now())
+}
+
+// updateDaysUntilExpirationLocked updates the days-until-expiry metrics as of
+// the given time. Like the expiration metrics, they are reset to zero when the
+// certificate is missing or invalid.
+// cm.mu must be held (for reading at least).
+func (cm *CertificateManager) updateDaysUntilExpirationLocked(now time.Time) {
+	maybeSetMetric := func(m *metric.Gauge, ci *CertInfo) {
+		if m == nil {
+			return
+		}
+		if ci != nil && ci.Error == nil {
+			m.Update(int64(ci.ExpirationTime.Sub(now) / (24 * time.Hour)))
+		} else {
+			m.Update(0)
+		}
+	}
+
+	maybeSetMetric(cm.certMetrics.CADaysUntilExpiration, cm.caCert)
+	maybeSetMetric(cm.certMetrics.ClientCADaysUntilExpiration, cm.clientCACert)
+	maybeSetMetric(cm.certMetrics.UICADaysUntilExpiration, cm.uiCACert)
+	maybeSetMetric(cm.certMetrics.NodeDaysUntilExpiration, cm.nodeCert)
+	maybeSetMetric(cm.certMetrics.NodeClientDaysUntilExpiration, cm.nodeClientCert)
+	maybeSetMetric(cm.certMetrics.UIDaysUntilExpiration, cm.uiCert)
 }
 
 // GetServerTLSConfig returns a server TLS config with a callback to fetch the
@@ -489,6 +766,11 @@ func (cm *CertificateManager) getEmbeddedServerTLSConfig(
 	if err != nil {
 		return nil, err
 	}
+	cfg.VerifyPeerCertificate = cm.verifyPeerCertificate
+	// Resumed sessions skip the verification of the peer certificate, which
+	// would let a client whose certificate was revoked keep reconnecting.
+	cfg.SessionTicketsDisabled = cm.crl != nil
+	cm.tlsSettings.apply(cfg)
 
 	cm.serverConfig = cfg
 	return cfg, nil
@@ -530,6 +812,7 @@ func (cm *CertificateManager) getEmbeddedUIServerTLSConfig(
 	if err != nil {
 		return nil, err
 	}
+	cm.tlsSettings.apply(cfg)
 
 	cm.uiServerConfig = cfg
 	return cfg, nil
@@ -651,6 +934,8 @@ func (cm *CertificateManager) GetClientTLSConfig(user string) (*tls.Config, erro
 		if err != nil {
 			return nil, err
 		}
+		cfg.VerifyPeerCertificate = cm.verifyPeerCertificate
+		cm.tlsSettings.apply(cfg)
 
 		return cfg, nil
 	}
@@ -673,6 +958,8 @@ func (cm *CertificateManager) GetClientTLSConfig(user string) (*tls.Config, erro
 	if err != nil {
 		return nil, err
 	}
+	cfg.VerifyPeerCertificate = cm.verifyPeerCertificate
+	cm.tlsSettings.apply(cfg)
 
 	// Cache the config.
 	cm.clientConfig = cfg
@@ -695,6 +982,7 @@ func (cm *CertificateManager) GetUIClientTLSConfig() (*tls.Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	cm.tlsSettings.apply(cfg)
 
 	return cfg, nil
 }
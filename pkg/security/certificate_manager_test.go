@@ -11,6 +11,7 @@
 package security_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/stretchr/testify/require"
 )
 
@@ -141,3 +143,111 @@ func TestManagerWithPrincipalMap(t *testing.T) {
 	setCertPrincipalMap("testuser:foo,node.crdb.io:node")
 	require.NoError(t, loadUserCert("foo"))
 }
+
+func TestExpiringCertificates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	security.ResetAssetLoader()
+	defer ResetTest()
+
+	certsDir, err := ioutil.TempDir("", "expiring_certs_test")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(certsDir))
+	}()
+	// The CA is valid for 96 hours, the node and client certificates for 48.
+	require.NoError(t, generateBaseCerts(certsDir))
+
+	cm, err := security.NewCertificateManager(certsDir)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.Empty(t, cm.ExpiringCertificates(now.Add(24*time.Hour)))
+
+	var names []string
+	for _, ci := range cm.ExpiringCertificates(now.Add(72 * time.Hour)) {
+		names = append(names, ci.Filename)
+	}
+	require.ElementsMatch(t, []string{security.EmbeddedNodeCert, security.EmbeddedRootCert}, names)
+
+	require.Len(t, cm.ExpiringCertificates(now.Add(120*time.Hour)), 3)
+}
+
+func TestCheckCertificateExpiry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	security.ResetAssetLoader()
+	defer ResetTest()
+
+	certsDir, err := ioutil.TempDir("", "cert_expiry_test")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(certsDir))
+	}()
+	// The CA is valid for 96 hours, the node and client certificates for 48.
+	require.NoError(t, generateBaseCerts(certsDir))
+
+	var now time.Time
+	cm, err := security.NewCertificateManager(certsDir, security.WithClock(func() time.Time {
+		return now
+	}))
+	require.NoError(t, err)
+	nodeExpiration := cm.NodeCert().ExpirationTime
+
+	ctx := context.Background()
+	severities := make(map[string]log.Severity)
+	log.Intercept(ctx, func(entry log.Entry) {
+		for _, name := range []string{
+			security.EmbeddedCACert, security.EmbeddedNodeCert, security.EmbeddedRootCert,
+		} {
+			if strings.HasPrefix(entry.Message, "certificate "+name+" ") {
+				severities[name] = entry.Severity
+			}
+		}
+	})
+	defer log.Intercept(ctx, nil)
+
+	metrics := cm.Metrics()
+	testCases := []struct {
+		now        time.Time
+		severities map[string]log.Severity
+		caDays     int64
+		nodeDays   int64
+	}{
+		// Nothing expires within the warning period.
+		{nodeExpiration.Add(-(40*24 + 12) * time.Hour), map[string]log.Severity{}, 42, 40},
+		// Everything expires within the warning period.
+		{nodeExpiration.Add(-(10*24 + 12) * time.Hour), map[string]log.Severity{
+			security.EmbeddedCACert:   log.Severity_WARNING,
+			security.EmbeddedNodeCert: log.Severity_WARNING,
+			security.EmbeddedRootCert: log.Severity_WARNING,
+		}, 12, 10},
+		// Everything expires within the error period.
+		{nodeExpiration.Add(-36 * time.Hour), map[string]log.Severity{
+			security.EmbeddedCACert:   log.Severity_ERROR,
+			security.EmbeddedNodeCert: log.Severity_ERROR,
+			security.EmbeddedRootCert: log.Severity_ERROR,
+		}, 3, 1},
+		// The node and client certificates have expired.
+		{nodeExpiration.Add(36 * time.Hour), map[string]log.Severity{
+			security.EmbeddedCACert:   log.Severity_ERROR,
+			security.EmbeddedNodeCert: log.Severity_ERROR,
+			security.EmbeddedRootCert: log.Severity_ERROR,
+		}, 0, -1},
+	}
+	// The clock is set half a day off whole days before the expiry of the node
+	// certificate, since the CA expires slightly less than two days after it.
+	for _, tc := range testCases {
+		t.Run(tc.now.String(), func(t *testing.T) {
+			now = tc.now
+			for name := range severities {
+				delete(severities, name)
+			}
+			cm.CheckCertificateExpiry(ctx)
+			require.Equal(t, tc.severities, severities)
+			require.Equal(t, tc.caDays, metrics.CADaysUntilExpiration.Value())
+			require.Equal(t, tc.nodeDays, metrics.NodeDaysUntilExpiration.Value())
+			require.Equal(t, tc.nodeDays, metrics.NodeClientDaysUntilExpiration.Value())
+		})
+	}
+}
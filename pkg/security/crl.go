@@ -0,0 +1,219 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+const crlExtension = ".crl"
+
+// crlRefreshInterval is how often the certificate revocation list is
+// reloaded from disk. This lets operators publish new lists without sending
+// SIGHUP to every node.
+var crlRefreshInterval = envutil.EnvOrDefaultDuration("COCKROACH_CRL_REFRESH_INTERVAL", 5*time.Minute)
+
+// revocationList holds the serial numbers of revoked certificates, indexed
+// by the raw subject of the CA which revoked them.
+type revocationList map[string]map[string]struct{}
+
+// isRevoked returns true if the certificate was revoked by its issuer.
+func (r revocationList) isRevoked(c *x509.Certificate) bool {
+	serials, ok := r[string(c.RawIssuer)]
+	if !ok {
+		return false
+	}
+	_, ok = serials[c.SerialNumber.String()]
+	return ok
+}
+
+// checkNotRevoked returns an error if any certificate in the verified chains
+// has been revoked. It is meant for tls.Config.VerifyPeerCertificate, which
+// runs after the standard chain verification.
+func (r revocationList) checkNotRevoked(verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		for _, c := range chain {
+			if r.isRevoked(c) {
+				return errors.Errorf("certificate %q with serial number %s has been revoked",
+					c.Subject.CommonName, c.SerialNumber)
+			}
+		}
+	}
+	return nil
+}
+
+// loadRevocationList reads the PEM-encoded CRLs in the given file. Each CRL
+// must be signed by one of the given CA certificates. A missing file is not
+// an error: revocation checking is disabled and a nil list is returned.
+func loadRevocationList(
+	assets AssetLoader, path string, cas ...*CertInfo,
+) (revocationList, error) {
+	if _, err := assets.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	contents, err := assets.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var issuers []*x509.Certificate
+	for _, ca := range cas {
+		if ca == nil || ca.Error != nil {
+			continue
+		}
+		certs, err := PEMContentsToX509(ca.FileContents)
+		if err != nil {
+			return nil, err
+		}
+		issuers = append(issuers, certs...)
+	}
+
+	r := revocationList{}
+	for len(contents) > 0 {
+		var block *pem.Block
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			return nil, errors.Errorf("%s: unexpected PEM block type %q", path, block.Type)
+		}
+		crl, err := x509.ParseDERCRL(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: failed to parse CRL", path)
+		}
+		issuer := findCRLIssuer(crl, issuers)
+		if issuer == nil {
+			return nil, errors.Errorf("%s: CRL is not signed by a known CA certificate", path)
+		}
+		if crl.HasExpired(timeutil.Now()) {
+			log.Warningf(context.Background(), "%s: CRL issued by %q was due to be updated at %s",
+				path, issuer.Subject.CommonName, crl.TBSCertList.NextUpdate)
+		}
+		serials := r[string(issuer.RawSubject)]
+		if serials == nil {
+			serials = make(map[string]struct{})
+			r[string(issuer.RawSubject)] = serials
+		}
+		for _, rc := range crl.TBSCertList.RevokedCertificates {
+			serials[rc.SerialNumber.String()] = struct{}{}
+		}
+	}
+	if len(r) == 0 {
+		return nil, errors.Errorf("%s: no CRL found", path)
+	}
+	return r, nil
+}
+
+// findCRLIssuer returns the certificate whose key signed the CRL, or nil.
+func findCRLIssuer(crl *pkix.CertificateList, issuers []*x509.Certificate) *x509.Certificate {
+	for _, c := range issuers {
+		if c.CheckCRLSignature(crl) == nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// revocableConn is a connection registered with TrackConn.
+type revocableConn struct {
+	net.Conn
+	cm     *CertificateManager
+	chains [][]*x509.Certificate
+}
+
+// Close implements the net.Conn interface.
+func (c *revocableConn) Close() error {
+	c.cm.conns.Lock()
+	delete(c.cm.conns.m, c)
+	c.cm.conns.Unlock()
+	return c.Conn.Close()
+}
+
+// ConnectionState returns the TLS state of the tracked connection, if it is
+// a *tls.Conn.
+func (c *revocableConn) ConnectionState() tls.ConnectionState {
+	if tlsConn, ok := c.Conn.(*tls.Conn); ok {
+		return tlsConn.ConnectionState()
+	}
+	return tls.ConnectionState{}
+}
+
+// TrackConn registers a connection whose peer presented the given verified
+// certificate chains during the TLS handshake, so that the connection is
+// closed if the revocation list is later changed to revoke one of them. The
+// verification of the handshake only applies to new connections otherwise.
+// The returned connection must be used, and closed, in place of conn.
+func (cm *CertificateManager) TrackConn(conn net.Conn, verifiedChains [][]*x509.Certificate) net.Conn {
+	if len(verifiedChains) == 0 {
+		// The peer didn't present a certificate.
+		return conn
+	}
+	c := &revocableConn{Conn: conn, cm: cm, chains: verifiedChains}
+	cm.conns.Lock()
+	if cm.conns.m == nil {
+		cm.conns.m = make(map[*revocableConn]struct{})
+	}
+	cm.conns.m[c] = struct{}{}
+	cm.conns.Unlock()
+
+	// The revocation list may have changed since the handshake.
+	cm.mu.RLock()
+	crl := cm.crl
+	cm.mu.RUnlock()
+	if err := crl.checkNotRevoked(verifiedChains); err != nil {
+		log.Infof(context.Background(), "closing connection to %s: %v", conn.RemoteAddr(), err)
+		_ = c.Close()
+	}
+	return c
+}
+
+// closeRevokedConns closes the connections registered with TrackConn whose
+// peer presented a certificate revoked by the current revocation list.
+func (cm *CertificateManager) closeRevokedConns(ctx context.Context) {
+	cm.mu.RLock()
+	crl := cm.crl
+	cm.mu.RUnlock()
+	if crl == nil {
+		return
+	}
+
+	var revoked []*revocableConn
+	var errs []error
+	cm.conns.Lock()
+	for c := range cm.conns.m {
+		if err := crl.checkNotRevoked(c.chains); err != nil {
+			revoked = append(revoked, c)
+			errs = append(errs, err)
+		}
+	}
+	cm.conns.Unlock()
+
+	for i, c := range revoked {
+		log.Infof(ctx, "closing connection to %s: %v", c.RemoteAddr(), errs[i])
+		_ = c.Close()
+	}
+}
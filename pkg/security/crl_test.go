@@ -0,0 +1,137 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateRevocation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	security.ResetAssetLoader()
+	defer ResetTest()
+
+	certsDir, err := ioutil.TempDir("", "crl_test")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(certsDir))
+	}()
+	require.NoError(t, generateBaseCerts(certsDir))
+
+	cm, err := security.NewCertificateManager(certsDir)
+	require.NoError(t, err)
+
+	readCert := func(name string) *x509.Certificate {
+		contents, err := ioutil.ReadFile(filepath.Join(certsDir, name))
+		require.NoError(t, err)
+		certs, err := security.PEMContentsToX509(contents)
+		require.NoError(t, err)
+		return certs[0]
+	}
+	caCert := readCert(security.EmbeddedCACert)
+	nodeCert := readCert(security.EmbeddedNodeCert)
+	rootCert := readCert(security.EmbeddedRootCert)
+
+	keyPEM, err := ioutil.ReadFile(filepath.Join(certsDir, security.EmbeddedCAKey))
+	require.NoError(t, err)
+	caKey, err := security.PEMToPrivateKey(keyPEM)
+	require.NoError(t, err)
+
+	writeCRL := func(signer interface{}, revoked ...*x509.Certificate) {
+		var entries []pkix.RevokedCertificate
+		for _, c := range revoked {
+			entries = append(entries, pkix.RevokedCertificate{
+				SerialNumber:   c.SerialNumber,
+				RevocationTime: timeutil.Now(),
+			})
+		}
+		der, err := caCert.CreateCRL(rand.Reader, signer, entries, timeutil.Now(), timeutil.Now().Add(time.Hour))
+		require.NoError(t, err)
+		contents := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+		require.NoError(t, ioutil.WriteFile(cm.CRLPath(), contents, 0644))
+	}
+
+	// Verify the client certificate the way the server does during the
+	// handshake.
+	serverCfg, err := cm.GetServerTLSConfig()
+	require.NoError(t, err)
+	verify := func(c *x509.Certificate) error {
+		cfg, err := serverCfg.GetConfigForClient(nil)
+		require.NoError(t, err)
+		return cfg.VerifyPeerCertificate(nil, [][]*x509.Certificate{{c, caCert}})
+	}
+
+	// Without a CRL, all certificates are accepted, and TLS sessions can be
+	// resumed.
+	require.NoError(t, verify(rootCert))
+	cfg, err := serverCfg.GetConfigForClient(nil)
+	require.NoError(t, err)
+	require.False(t, cfg.SessionTicketsDisabled)
+
+	// Connections established by peers presenting a certificate which later
+	// gets revoked are closed.
+	track := func(c *x509.Certificate) net.Conn {
+		conn, peerConn := net.Pipe()
+		go func() { _, _ = ioutil.ReadAll(peerConn) }()
+		return cm.TrackConn(conn, [][]*x509.Certificate{{c, caCert}})
+	}
+	rootConn, nodeConn := track(rootCert), track(nodeCert)
+	defer nodeConn.Close()
+
+	writeCRL(caKey, rootCert)
+	require.NoError(t, cm.LoadCertificates())
+	if err := verify(rootCert); !testutils.IsError(err, "has been revoked") {
+		t.Fatalf("expected revoked certificate error, got %v", err)
+	}
+	require.NoError(t, verify(nodeCert))
+	cfg, err = serverCfg.GetConfigForClient(nil)
+	require.NoError(t, err)
+	require.True(t, cfg.SessionTicketsDisabled)
+	_, err = rootConn.Write([]byte("x"))
+	require.Equal(t, io.ErrClosedPipe, err)
+	_, err = nodeConn.Write([]byte("x"))
+	require.NoError(t, err)
+
+	// A CRL which is not signed by the CA is rejected, and the previous list
+	// remains in effect.
+	otherKey, err := rsa.GenerateKey(rand.Reader, testKeySize)
+	require.NoError(t, err)
+	writeCRL(otherKey)
+	if err := cm.LoadCertificates(); !testutils.IsError(err, "not signed by a known CA certificate") {
+		t.Fatalf("expected invalid CRL error, got %v", err)
+	}
+	if err := verify(rootCert); !testutils.IsError(err, "has been revoked") {
+		t.Fatalf("expected revoked certificate error, got %v", err)
+	}
+
+	// Removing the CRL disables revocation checking.
+	require.NoError(t, os.Remove(cm.CRLPath()))
+	require.NoError(t, cm.LoadCertificates())
+	require.NoError(t, verify(rootCert))
+}
@@ -73,3 +73,28 @@ func PromptForPassword() (string, error) {
 
 	return string(password), nil
 }
+
+// PromptForPasswordTwice prompts for a password twice, returning the read
+// string if they match, or an error.
+// This is meant to be used when setting a password.
+func PromptForPasswordTwice() (string, error) {
+	fmt.Print("Enter password: ")
+	one, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	if len(one) == 0 {
+		return "", ErrEmptyPassword
+	}
+	fmt.Print("\nConfirm password: ")
+	two, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	// Make sure stdout moves on to the next line.
+	fmt.Print("\n")
+	if string(one) != string(two) {
+		return "", errors.New("password mismatch")
+	}
+	return string(one), nil
+}
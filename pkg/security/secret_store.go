@@ -0,0 +1,132 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SecretStore is a source of certificates and keys other than the
+// filesystem, for example environment variables or an external secret
+// manager. This lets containerized deployments inject keys at runtime
+// instead of baking them into images or volumes.
+//
+// Secrets are named after the file they stand in for, e.g. "node.key" or
+// "client.root.crt", so the usual naming scheme of the certs directory
+// applies.
+type SecretStore interface {
+	// ListSecrets returns the names of all the secrets in the store.
+	ListSecrets() ([]string, error)
+	// GetSecret returns the contents of the named secret, or an error
+	// satisfying os.IsNotExist if the store does not hold it.
+	GetSecret(name string) ([]byte, error)
+}
+
+// EnvSecretStore is a SecretStore backed by environment variables. The
+// secret "node.key" is read from the variable <Prefix>node.key.
+type EnvSecretStore struct {
+	Prefix string
+}
+
+var _ SecretStore = EnvSecretStore{}
+
+// ListSecrets is part of the SecretStore interface.
+func (s EnvSecretStore) ListSecrets() ([]string, error) {
+	var names []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, s.Prefix) {
+			continue
+		}
+		if i := strings.IndexByte(kv, '='); i > len(s.Prefix) {
+			names = append(names, kv[len(s.Prefix):i])
+		}
+	}
+	return names, nil
+}
+
+// GetSecret is part of the SecretStore interface.
+func (s EnvSecretStore) GetSecret(name string) ([]byte, error) {
+	v, ok := os.LookupEnv(s.Prefix + name)
+	if !ok {
+		return nil, &os.PathError{Op: "getenv", Path: s.Prefix + name, Err: os.ErrNotExist}
+	}
+	return []byte(v), nil
+}
+
+// SecretStoreAssetLoader returns an AssetLoader which serves the secrets of
+// the store as files of any directory, and defers to the fallback loader for
+// everything else. Secrets take precedence over files with the same name.
+func SecretStoreAssetLoader(store SecretStore, fallback AssetLoader) AssetLoader {
+	return AssetLoader{
+		ReadDir: func(dirname string) ([]os.FileInfo, error) {
+			infos, err := fallback.ReadDir(dirname)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			names, err := store.ListSecrets()
+			if err != nil {
+				return nil, err
+			}
+			byName := make(map[string]os.FileInfo, len(infos)+len(names))
+			for _, info := range infos {
+				byName[info.Name()] = info
+			}
+			for _, name := range names {
+				contents, err := store.GetSecret(name)
+				if err != nil {
+					return nil, err
+				}
+				byName[name] = secretFileInfo{name: name, size: int64(len(contents))}
+			}
+			infos = infos[:0]
+			for _, info := range byName {
+				infos = append(infos, info)
+			}
+			sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+			return infos, nil
+		},
+		ReadFile: func(filename string) ([]byte, error) {
+			contents, err := store.GetSecret(filepath.Base(filename))
+			if os.IsNotExist(err) {
+				return fallback.ReadFile(filename)
+			}
+			return contents, err
+		},
+		Stat: func(name string) (os.FileInfo, error) {
+			base := filepath.Base(name)
+			contents, err := store.GetSecret(base)
+			if os.IsNotExist(err) {
+				return fallback.Stat(name)
+			} else if err != nil {
+				return nil, err
+			}
+			return secretFileInfo{name: base, size: int64(len(contents))}, nil
+		},
+	}
+}
+
+// secretFileInfo describes a secret as a regular file readable only by its
+// owner, which satisfies the permission checks on key files.
+type secretFileInfo struct {
+	name string
+	size int64
+}
+
+func (fi secretFileInfo) Name() string       { return fi.name }
+func (fi secretFileInfo) Size() int64        { return fi.size }
+func (fi secretFileInfo) Mode() os.FileMode  { return 0400 }
+func (fi secretFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi secretFileInfo) IsDir() bool        { return false }
+func (fi secretFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,73 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSecretStore(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	security.ResetAssetLoader()
+	defer ResetTest()
+
+	certsDir, err := ioutil.TempDir("", "secret_store_test")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(certsDir))
+	}()
+	require.NoError(t, generateBaseCerts(certsDir))
+
+	// Move the node certificate and key from the certs directory into the
+	// environment.
+	const prefix = "COCKROACH_TEST_SECRET_"
+	for _, name := range []string{security.EmbeddedNodeCert, security.EmbeddedNodeKey} {
+		path := filepath.Join(certsDir, name)
+		contents, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(path))
+		require.NoError(t, os.Setenv(prefix+name, string(contents)))
+		defer func(name string) {
+			require.NoError(t, os.Unsetenv(prefix+name))
+		}(name)
+	}
+
+	store := security.EnvSecretStore{Prefix: prefix}
+	names, err := store.ListSecrets()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{security.EmbeddedNodeCert, security.EmbeddedNodeKey}, names)
+	if _, err := store.GetSecret("missing"); !os.IsNotExist(err) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+
+	// Without the secret store, there is no node certificate.
+	cm, err := security.NewCertificateManager(certsDir)
+	require.NoError(t, err)
+	_, err = cm.GetServerTLSConfig()
+	require.Error(t, err)
+
+	// The CA certificate still comes from the certs directory.
+	cm, err = security.NewCertificateManager(certsDir,
+		security.WithAssetLoader(security.SecretStoreAssetLoader(store, security.GetAssetLoader())))
+	require.NoError(t, err)
+	_, err = cm.GetServerTLSConfig()
+	require.NoError(t, err)
+	_, err = cm.GetClientTLSConfig(security.RootUser)
+	require.NoError(t, err)
+}
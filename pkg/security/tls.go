@@ -167,6 +167,91 @@ func newBaseTLSConfigWithCertificate(certPEM, keyPEM, caPEM []byte) (*tls.Config
 	return cfg, nil
 }
 
+// defaultCipherSuites is the list of cipher suites used when none are
+// configured. See newBaseTLSConfig for how it was chosen. Only suites in this
+// list may be configured.
+var defaultCipherSuites = []uint16{
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+}
+
+// TLSSettings overrides the protocol version and cipher suites of the TLS
+// configs built by a CertificateManager. The zero value keeps the defaults.
+type TLSSettings struct {
+	// MinVersion is the minimum TLS version to negotiate. It can only be
+	// raised above the default of TLS 1.2.
+	MinVersion uint16
+	// CipherSuites restricts the cipher suites used below TLS 1.3, which does
+	// not allow configuring them.
+	CipherSuites []uint16
+}
+
+// apply overrides the defaults set by newBaseTLSConfig.
+func (s TLSSettings) apply(cfg *tls.Config) {
+	if s.MinVersion != 0 {
+		cfg.MinVersion = s.MinVersion
+	}
+	if len(s.CipherSuites) > 0 {
+		cfg.CipherSuites = s.CipherSuites
+	}
+}
+
+// ParseTLSVersion parses a minimum TLS version such as "1.3".
+func ParseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, errors.Errorf("unsupported TLS version %q, expected 1.2 or 1.3", s)
+	}
+}
+
+// ParseCipherSuites parses a list of cipher suite names, e.g.
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Only the suites enabled by default
+// are accepted: the list can restrict, but not weaken, the default.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	suites := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := cipherSuitesByName[name]
+		if !ok {
+			return nil, errors.Errorf("unsupported cipher suite %q", name)
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
+
+var cipherSuitesByName = map[string]uint16{
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":   tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":   tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256": tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384": tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":    tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":  tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+}
+
 // newBaseTLSConfig returns a tls.Config. If caPEM != nil, it is set in RootCAs.
 func newBaseTLSConfig(caPEM []byte) (*tls.Config, error) {
 	var certPool *x509.CertPool
@@ -204,22 +289,7 @@ func newBaseTLSConfig(caPEM []byte) (*tls.Config, error) {
 		//
 		// [1]: https://wiki.mozilla.org/Security/Server_Side_TLS#Modern_compatibility
 		// [2]: https://github.com/golang/go/commit/48d8edb5b21db190f717e035b4d9ab61a077f9d7
-		CipherSuites: []uint16{
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
-			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
-			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
-			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
-		},
+		CipherSuites: defaultCipherSuites,
 
 		MinVersion: tls.VersionTLS12,
 	}, nil
@@ -11,6 +11,7 @@
 package security_test
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"path/filepath"
 	"testing"
@@ -54,6 +55,53 @@ func TestLoadTLSConfig(t *testing.T) {
 	}
 }
 
+func TestTLSSettings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	if _, err := security.ParseTLSVersion("1.1"); err == nil {
+		t.Error("expected TLS 1.1 to be rejected")
+	}
+	if _, err := security.ParseCipherSuites([]string{"TLS_RSA_WITH_3DES_EDE_CBC_SHA"}); err == nil {
+		t.Error("expected 3DES cipher suite to be rejected")
+	}
+
+	minVersion, err := security.ParseTLSVersion("1.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	suites, err := security.ParseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := security.NewCertificateManager(security.EmbeddedCertsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm.SetTLSSettings(security.TLSSettings{MinVersion: minVersion, CipherSuites: suites})
+
+	serverCfg, err := cm.GetServerTLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	embeddedCfg, err := serverCfg.GetConfigForClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCfg, err := cm.GetClientTLSConfig(security.NodeUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cfg := range []*tls.Config{embeddedCfg, clientCfg} {
+		if cfg.MinVersion != tls.VersionTLS13 {
+			t.Errorf("expected TLS 1.3 minimum, got %x", cfg.MinVersion)
+		}
+		if len(cfg.CipherSuites) != 1 || cfg.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 {
+			t.Errorf("unexpected cipher suites %v", cfg.CipherSuites)
+		}
+	}
+}
+
 func verifyX509Cert(cert *x509.Certificate, dnsName string, roots *x509.CertPool) error {
 	verifyOptions := x509.VerifyOptions{
 		DNSName: dnsName,
@@ -48,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
@@ -1324,13 +1325,13 @@ func (s *adminServer) Health(
 		return resp, nil
 	}
 
-	if err := s.checkReadinessForHealthCheck(); err != nil {
+	if err := s.checkReadinessForHealthCheck(ctx); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
-func (s *adminServer) checkReadinessForHealthCheck() error {
+func (s *adminServer) checkReadinessForHealthCheck(ctx context.Context) error {
 	serveMode := s.server.grpc.mode.get()
 	switch serveMode {
 	case modeInitializing:
@@ -1345,6 +1346,22 @@ func (s *adminServer) checkReadinessForHealthCheck() error {
 		return s.serverError(errors.Newf("unknown mode: %v", serveMode))
 	}
 
+	// Connected is only closed once, when the node first connects to the
+	// gossip network, so this doesn't notice that the node lost its gossip
+	// connections later on. The liveness check below does, since a node
+	// that is cut off from the cluster can't heartbeat its liveness record.
+	select {
+	case <-s.server.gossip.Connected:
+	default:
+		return status.Errorf(codes.Unavailable, "node is not connected to gossip")
+	}
+
+	// A node whose clock is too far off crashes at its next RPC heartbeat,
+	// but it shouldn't receive traffic in the meantime.
+	if err := s.server.rpcContext.RemoteClocks.VerifyClockOffset(ctx); err != nil {
+		return status.Errorf(codes.Unavailable, "node has an unhealthy clock offset: %v", err)
+	}
+
 	// TODO(knz): update this code when progress is made on
 	// https://github.com/cockroachdb/cockroach/issues/45123
 	l, err := s.server.nodeLiveness.GetLiveness(s.server.NodeID())
@@ -2028,6 +2045,150 @@ func (s *adminServer) enqueueRangeLocal(
 	return response, nil
 }
 
+// CreateCheckpoint is an endpoint that creates a checkpoint of the stores of
+// the requested node, forwarding the request to that node if necessary.
+func (s *adminServer) CreateCheckpoint(
+	ctx context.Context, req *serverpb.CreateCheckpointRequest,
+) (*serverpb.CreateCheckpointResponse, error) {
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	if !debug.GatewayRemoteAllowed(ctx, s.server.ClusterSettings()) {
+		return nil, remoteDebuggingErr
+	}
+
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.server.AnnotateCtx(ctx)
+
+	if req.NodeID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "node_id must be non-negative; got %d", req.NodeID)
+	}
+	if req.StoreID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "store_id must be non-negative; got %d", req.StoreID)
+	}
+
+	if req.NodeID != 0 && req.NodeID != s.server.NodeID() {
+		admin, err := s.dialNode(ctx, req.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		return admin.CreateCheckpoint(ctx, req)
+	}
+
+	response := &serverpb.CreateCheckpointResponse{NodeID: s.server.NodeID()}
+	// All of the checkpoints created by a request share the same tag, which
+	// orders them by creation time.
+	tag := fmt.Sprintf("admin_%d", timeutil.Now().UnixNano())
+	if err := s.server.node.stores.VisitStores(func(store *kvserver.Store) error {
+		if req.StoreID != 0 && store.StoreID() != req.StoreID {
+			return nil
+		}
+		dir, err := store.Checkpoint(ctx, tag)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create checkpoint of s%d", store.StoreID())
+		}
+		log.Infof(ctx, "created checkpoint %s", dir)
+		response.Checkpoints = append(response.Checkpoints, serverpb.CreateCheckpointResponse_Checkpoint{
+			StoreID: store.StoreID(),
+			Dir:     dir,
+		})
+		return nil
+	}); err != nil {
+		return nil, s.serverError(err)
+	}
+
+	if req.StoreID != 0 && len(response.Checkpoints) == 0 {
+		return nil, status.Errorf(codes.NotFound, "n%d has no store s%d", s.server.NodeID(), req.StoreID)
+	}
+	return response, nil
+}
+
+// CompactStore is an endpoint that compacts the stores of the requested node,
+// forwarding the request to that node if necessary.
+func (s *adminServer) CompactStore(
+	ctx context.Context, req *serverpb.CompactStoreRequest,
+) (*serverpb.CompactStoreResponse, error) {
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	if !debug.GatewayRemoteAllowed(ctx, s.server.ClusterSettings()) {
+		return nil, remoteDebuggingErr
+	}
+
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.server.AnnotateCtx(ctx)
+
+	if req.NodeID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "node_id must be non-negative; got %d", req.NodeID)
+	}
+	if req.StoreID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "store_id must be non-negative; got %d", req.StoreID)
+	}
+	wholeStore := len(req.StartKey) == 0 && len(req.EndKey) == 0
+	if !wholeStore && req.StartKey.Compare(req.EndKey) >= 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"start_key %s must be less than end_key %s", req.StartKey, req.EndKey)
+	}
+
+	if req.NodeID != 0 && req.NodeID != s.server.NodeID() {
+		admin, err := s.dialNode(ctx, req.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		return admin.CompactStore(ctx, req)
+	}
+
+	response := &serverpb.CompactStoreResponse{NodeID: s.server.NodeID()}
+	if err := s.server.node.stores.VisitStores(func(store *kvserver.Store) error {
+		if req.StoreID != 0 && store.StoreID() != req.StoreID {
+			return nil
+		}
+		start := timeutil.Now()
+		var err error
+		if wholeStore {
+			err = store.Engine().Compact()
+		} else {
+			err = store.Engine().CompactRange(req.StartKey, req.EndKey, true /* forceBottommost */)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to compact s%d", store.StoreID())
+		}
+		log.Infof(ctx, "compacted s%d in %s", store.StoreID(), timeutil.Since(start))
+		response.StoreIDs = append(response.StoreIDs, store.StoreID())
+		return nil
+	}); err != nil {
+		return nil, s.serverError(err)
+	}
+
+	if req.StoreID != 0 && len(response.StoreIDs) == 0 {
+		return nil, status.Errorf(codes.NotFound, "n%d has no store s%d", s.server.NodeID(), req.StoreID)
+	}
+	return response, nil
+}
+
+// ReloadConfig reloads the config file of the node, see
+// Config.ReloadConfigFn.
+func (s *adminServer) ReloadConfig(
+	ctx context.Context, req *serverpb.ReloadConfigRequest,
+) (*serverpb.ReloadConfigResponse, error) {
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+	ctx = s.server.AnnotateCtx(ctx)
+
+	if s.server.cfg.ReloadConfigFn == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "the node was not started with --config-file")
+	}
+	log.Infof(ctx, "config reload request received")
+	changed, err := s.server.cfg.ReloadConfigFn(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+	}
+	return &serverpb.ReloadConfigResponse{Changed: changed}, nil
+}
+
 // sqlQuery allows you to incrementally build a SQL query that uses
 // placeholders. Instead of specific placeholders like $1, you instead use the
 // temporary placeholder $.
@@ -19,6 +19,8 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
@@ -28,12 +30,14 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/debug"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
@@ -48,7 +52,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/gogo/protobuf/proto"
@@ -1301,6 +1307,49 @@ func TestHealthAPI(t *testing.T) {
 	}
 }
 
+func TestCheckReadinessForHealthCheck(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	st := cluster.MakeTestingClusterSettings()
+	clock := hlc.NewClock(hlc.UnixNano, 500*time.Millisecond)
+	rpcContext := rpc.NewContext(
+		log.AmbientContext{Tracer: st.Tracer},
+		&base.Config{Insecure: true, RPCHeartbeatInterval: time.Minute},
+		clock,
+		stopper,
+		st,
+	)
+	grpcServer := newGRPCServer(rpcContext)
+	grpcServer.setMode(modeOperational)
+	g := gossip.NewTest(1, rpcContext, grpcServer.Server, stopper, metric.NewRegistry(), zonepb.DefaultZoneConfigRef())
+	// The conditions are checked in order. The liveness condition, which
+	// comes last and needs a full server, is covered by TestHealthAPI.
+	s := &adminServer{server: &Server{rpcContext: rpcContext, grpc: grpcServer, gossip: g}}
+
+	if err := s.checkReadinessForHealthCheck(ctx); !testutils.IsError(err, "node is not connected to gossip") {
+		t.Fatalf("expected gossip error, got %v", err)
+	}
+
+	// Gossiping the cluster ID marks the node as connected.
+	if err := g.AddClusterID(uuid.MakeV4()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The only clock offset measured is more than the maximum offset.
+	rpcContext.RemoteClocks.UpdateOffset(ctx, "other:26257", rpc.RemoteOffset{
+		Offset:     time.Second.Nanoseconds(),
+		MeasuredAt: clock.PhysicalNow(),
+	}, 0 /* roundTripLatency */)
+	if err := s.checkReadinessForHealthCheck(ctx); !testutils.IsError(err,
+		"node has an unhealthy clock offset: clock synchronization error") {
+		t.Fatalf("expected clock offset error, got %v", err)
+	}
+}
+
 // getSystemJobIDs queries the jobs table for all jobs IDs. Sorted by decreasing creation time.
 func getSystemJobIDs(t testing.TB, db *sqlutils.SQLRunner) []int64 {
 	rows := db.Query(t, `SELECT job_id FROM crdb_internal.jobs ORDER BY created DESC;`)
@@ -1755,6 +1804,141 @@ func BenchmarkAdminAPIDataDistribution(b *testing.B) {
 	b.StopTimer()
 }
 
+func TestAdminAPICreateCheckpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Checkpoints are hard links to the files of the store, so the store must
+	// be on disk.
+	dir, cleanupFn := testutils.TempDir(t)
+	defer cleanupFn()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{
+		StoreSpecs: []base.StoreSpec{{
+			Path: dir,
+		}},
+	})
+	defer s.Stopper().Stop(context.Background())
+
+	var resp serverpb.CreateCheckpointResponse
+	require.NoError(t, postAdminJSONProto(s, "checkpoint", &serverpb.CreateCheckpointRequest{}, &resp))
+	require.Equal(t, s.NodeID(), resp.NodeID)
+	require.Len(t, resp.Checkpoints, 1)
+	require.Equal(t, roachpb.StoreID(1), resp.Checkpoints[0].StoreID)
+	checkpointDir := resp.Checkpoints[0].Dir
+	require.True(t, strings.HasPrefix(checkpointDir, dir), "checkpoint %s not in %s", checkpointDir, dir)
+	_, err := os.Stat(filepath.Join(checkpointDir, "CURRENT"))
+	require.NoError(t, err)
+
+	// A second checkpoint is created in a new directory.
+	require.NoError(t, postAdminJSONProto(s, "checkpoint", &serverpb.CreateCheckpointRequest{
+		NodeID:  s.NodeID(),
+		StoreID: 1,
+	}, &resp))
+	require.Len(t, resp.Checkpoints, 1)
+	require.NotEqual(t, checkpointDir, resp.Checkpoints[0].Dir)
+
+	// Unknown stores are rejected.
+	require.Error(t, postAdminJSONProto(s, "checkpoint", &serverpb.CreateCheckpointRequest{
+		StoreID: 2,
+	}, &resp))
+}
+
+func TestAdminAPICompactStore(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	require.NoError(t, kvDB.Put(context.Background(), "a", "b"))
+
+	var resp serverpb.CompactStoreResponse
+	require.NoError(t, postAdminJSONProto(s, "compact", &serverpb.CompactStoreRequest{}, &resp))
+	require.Equal(t, s.NodeID(), resp.NodeID)
+	require.Equal(t, []roachpb.StoreID{1}, resp.StoreIDs)
+
+	require.NoError(t, postAdminJSONProto(s, "compact", &serverpb.CompactStoreRequest{
+		NodeID:   s.NodeID(),
+		StoreID:  1,
+		StartKey: roachpb.Key("a"),
+		EndKey:   roachpb.Key("b"),
+	}, &resp))
+	require.Equal(t, []roachpb.StoreID{1}, resp.StoreIDs)
+
+	for _, req := range []serverpb.CompactStoreRequest{
+		// Unknown store.
+		{StoreID: 2},
+		// Empty span.
+		{StartKey: roachpb.Key("b"), EndKey: roachpb.Key("a")},
+		// Missing end key.
+		{StartKey: roachpb.Key("a")},
+	} {
+		require.Error(t, postAdminJSONProto(s, "compact", &req, &resp), "%+v", req)
+	}
+}
+
+func TestAdminAPIQuit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	var resp serverpb.QuitResponse
+	err := postAdminJSONProtoWithAdminOption(s, "quit", &serverpb.QuitRequest{}, &resp, false /* isAdmin */)
+	if !testutils.IsError(err, "requires admin privilege") {
+		t.Fatalf("expected privilege error, got %v", err)
+	}
+	select {
+	case <-s.Stopper().ShouldQuiesce():
+		t.Fatal("server stopped after unauthorized quit request")
+	default:
+	}
+
+	// The server may close the connection before the response is read, so
+	// the error is not checked.
+	_ = postAdminJSONProto(s, "quit", &serverpb.QuitRequest{}, &resp)
+	select {
+	case <-s.Stopper().IsStopped():
+	case <-time.After(testutils.DefaultSucceedsSoonDuration):
+		t.Fatal("server did not stop after quit request")
+	}
+}
+
+func TestAdminAPIReloadConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+	ts := s.(*TestServer)
+
+	var resp serverpb.ReloadConfigResponse
+	err := postAdminJSONProtoWithAdminOption(s, "reload_config", &serverpb.ReloadConfigRequest{}, &resp, false /* isAdmin */)
+	if !testutils.IsError(err, "requires admin privilege") {
+		t.Fatalf("expected privilege error, got %v", err)
+	}
+	err = postAdminJSONProto(s, "reload_config", &serverpb.ReloadConfigRequest{}, &resp)
+	if !testutils.IsError(err, "not started with --config-file") {
+		t.Fatalf("expected missing config file error, got %v", err)
+	}
+
+	// The hook is normally set by the start command. It is set before any
+	// reload request is sent.
+	var reloadErr error
+	ts.Server.cfg.ReloadConfigFn = func(context.Context) ([]string, error) {
+		if reloadErr != nil {
+			return nil, reloadErr
+		}
+		return []string{"cache", "vmodule"}, nil
+	}
+	require.NoError(t, postAdminJSONProto(s, "reload_config", &serverpb.ReloadConfigRequest{}, &resp))
+	require.Equal(t, []string{"cache", "vmodule"}, resp.Changed)
+
+	reloadErr = errors.New("the new value of --cache takes effect at the next restart")
+	err = postAdminJSONProto(s, "reload_config", &serverpb.ReloadConfigRequest{}, &resp)
+	if !testutils.IsError(err, "takes effect at the next restart") {
+		t.Fatalf("expected reload error, got %v", err)
+	}
+}
+
 func TestEnqueueRange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testCluster := serverutils.StartTestCluster(t, 3, base.TestClusterArgs{
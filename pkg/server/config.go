@@ -271,6 +271,11 @@ type Config struct {
 	// in a timely fashion, typically 30s after the server starts listening.
 	DelayedBootstrapFn func()
 
+	// ReloadConfigFn, if set, is called by the ReloadConfig admin RPC to
+	// reload the node's config file. It returns the names of the flags
+	// whose value changed.
+	ReloadConfigFn func(ctx context.Context) ([]string, error)
+
 	// EnableWebSessionAuthentication enables session-based authentication for
 	// the Admin API's HTTP endpoints.
 	EnableWebSessionAuthentication bool
@@ -507,6 +512,9 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 
 			details = append(details, fmt.Sprintf("store %d: RocksDB, max size %s, max open file limit %d",
 				i, humanizeutil.IBytes(sizeInBytes), openFileLimitPerStore))
+			if !spec.RocksDBTuning.IsEmpty() {
+				details = append(details, fmt.Sprintf("store %d: RocksDB tuning %+v", i, spec.RocksDBTuning))
+			}
 
 			var eng storage.Engine
 			var err error
@@ -517,6 +525,11 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 				Settings:        cfg.Settings,
 				UseFileRegistry: spec.UseFileRegistry,
 				ExtraOptions:    spec.ExtraOptions,
+				WALSync:         spec.WALSync,
+			}
+			if spec.WALSync != base.WALSyncAlways {
+				log.Warningf(ctx, "store %d: WAL sync mode is %s; "+
+					"acknowledged writes may be lost if the machine crashes", i, spec.WALSync)
 			}
 			if cfg.StorageEngine == enginepb.EngineTypePebble {
 				// TODO(itsbilal): Tune these options, and allow them to be overridden
@@ -534,6 +547,7 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 					MaxOpenFiles:            openFileLimitPerStore,
 					WarnLargeBatchThreshold: 500 * time.Millisecond,
 					RocksDBOptions:          spec.RocksDBOptions,
+					Tuning:                  spec.RocksDBTuning,
 				}
 
 				eng, err = storage.NewRocksDB(rocksDBConfig, cache)
@@ -556,6 +570,7 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 					MaxOpenFiles:            openFileLimitPerStore,
 					WarnLargeBatchThreshold: 500 * time.Millisecond,
 					RocksDBOptions:          spec.RocksDBOptions,
+					Tuning:                  spec.RocksDBTuning,
 				}
 				rocksDBConfig.Dir = filepath.Join(rocksDBConfig.Dir, "rocksdb")
 
@@ -650,6 +665,16 @@ func (cfg *Config) readEnvironmentVariables() {
 func (cfg *Config) parseGossipBootstrapResolvers() ([]resolver.Resolver, error) {
 	var bootstrapResolvers []resolver.Resolver
 	for _, address := range cfg.JoinList {
+		if resolver.IsDiscoveryAddress(address) {
+			// Peer discovery resolvers enumerate the addresses themselves.
+			resolver, err := resolver.NewResolver(address)
+			if err != nil {
+				return nil, err
+			}
+			bootstrapResolvers = append(bootstrapResolvers, resolver)
+			continue
+		}
+
 		srvAddrs, err := resolver.SRV(address)
 		if err != nil {
 			return nil, err
@@ -85,7 +85,9 @@ type Server struct {
 }
 
 // NewServer sets up a debug server.
-func NewServer(st *cluster.Settings, hbaConfDebugFn http.HandlerFunc) *Server {
+func NewServer(
+	st *cluster.Settings, hbaConfDebugFn, gossipConnectivityDebugFn http.HandlerFunc,
+) *Server {
 	mux := http.NewServeMux()
 
 	// Install a redirect to the UI's collection of debug tools.
@@ -118,6 +120,12 @@ func NewServer(st *cluster.Settings, hbaConfDebugFn http.HandlerFunc) *Server {
 		mux.HandleFunc("/debug/hba_conf", hbaConfDebugFn)
 	}
 
+	if gossipConnectivityDebugFn != nil {
+		// Expose the gossip connectivity graph for debugging partial
+		// connectivity and partitions.
+		mux.HandleFunc("/debug/gossip/connectivity", gossipConnectivityDebugFn)
+	}
+
 	// Register the stopper endpoint, which lists all active tasks.
 	mux.HandleFunc("/debug/stopper", stop.HandleDebug)
 
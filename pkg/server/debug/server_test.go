@@ -0,0 +1,68 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package debug
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestDebugRemoteMode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const (
+		local  = "127.0.0.1:26258"
+		local6 = "[::1]:26258"
+		remote = "10.0.0.1:26258"
+	)
+	testCases := []struct {
+		mode    RemoteMode
+		addr    string
+		allowed bool
+	}{
+		{RemoteOff, local, false},
+		{RemoteOff, remote, false},
+		{RemoteLocal, local, true},
+		{RemoteLocal, local6, true},
+		{RemoteLocal, "localhost", true},
+		{RemoteLocal, remote, false},
+		{RemoteAny, local, true},
+		{RemoteAny, remote, true},
+	}
+
+	st := cluster.MakeTestingClusterSettings()
+	ds := NewServer(st, nil /* hbaConfDebugFn */, nil /* gossipConnectivityDebugFn */)
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%s", tc.mode, tc.addr), func(t *testing.T) {
+			if err := st.MakeUpdater().Set("server.remote_debugging.mode", string(tc.mode), "s"); err != nil {
+				t.Fatal(err)
+			}
+			if a, e := authRequest(tc.addr, st), tc.allowed; a != e {
+				t.Fatalf("expected allowed=%t, got %t", e, a)
+			}
+
+			req := httptest.NewRequest("GET", "/debug/stopper", nil)
+			req.RemoteAddr = tc.addr
+			w := httptest.NewRecorder()
+			ds.ServeHTTP(w, req)
+			if tc.allowed && w.Code == http.StatusForbidden {
+				t.Fatalf("expected request to be allowed, got %d", w.Code)
+			} else if !tc.allowed && w.Code != http.StatusForbidden {
+				t.Fatalf("expected request to be forbidden, got %d", w.Code)
+			}
+		})
+	}
+}
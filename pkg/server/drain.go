@@ -18,11 +18,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -59,6 +64,9 @@ var (
 // This method is part of the serverpb.AdminClient interface.
 func (s *adminServer) Drain(req *serverpb.DrainRequest, stream serverpb.Admin_DrainServer) error {
 	ctx := stream.Context()
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		return err
+	}
 	ctx = s.server.AnnotateCtx(ctx)
 
 	doDrain := req.DoDrain
@@ -101,6 +109,61 @@ func (s *adminServer) Drain(req *serverpb.DrainRequest, stream serverpb.Admin_Dr
 		return nil
 	}
 
+	return s.shutdown(ctx)
+}
+
+// Quit drains the node and then shuts it down.
+// This method is part of the serverpb.AdminClient interface.
+func (s *adminServer) Quit(
+	ctx context.Context, req *serverpb.QuitRequest,
+) (*serverpb.QuitResponse, error) {
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+	rpcCtx := ctx
+	ctx = s.server.AnnotateCtx(ctx)
+
+	log.Infof(ctx, "quit request received")
+	// Leases and clients which couldn't be drained in one pass are usually
+	// waiting on something else, so back off between passes.
+	retryOpts := retry.Options{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Closer:         s.server.stopper.ShouldQuiesce(),
+	}
+	drained := false
+	for r := retry.StartWithCtx(ctx, retryOpts); r.Next(); {
+		remaining, info, err := s.server.Drain(ctx)
+		if err != nil {
+			log.Errorf(ctx, "drain failed: %v", err)
+			return nil, err
+		}
+		if remaining == 0 {
+			drained = true
+			break
+		}
+		log.Infof(ctx, "drain incomplete, %d remaining (%s); retrying", remaining, info)
+	}
+	if !drained {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("server is shutting down")
+	}
+
+	// Shut down once the response has been sent, which gRPC signals by
+	// canceling the context of the RPC.
+	go func() {
+		<-rpcCtx.Done()
+		_ = s.shutdown(s.server.AnnotateCtx(context.Background()))
+	}()
+	return &serverpb.QuitResponse{}, nil
+}
+
+// shutdown stops the server and waits until it is stopped, or exits the
+// process if that takes too long.
+func (s *adminServer) shutdown(ctx context.Context) error {
 	go func() {
 		// TODO(tbg): why don't we stop the stopper first? Stopping the stopper
 		// first seems more reasonable since grpc.Stop closes the listener right
@@ -184,6 +247,11 @@ func (s *Server) Drain(ctx context.Context) (remaining uint64, info string, err
 }
 
 func (s *Server) doDrain(ctx context.Context, reporter func(int, string)) error {
+	// Record the start of the drain the first time around, while SQL
+	// is still available to write the event.
+	if !s.isDraining() {
+		s.recordDrainEvent(ctx)
+	}
 	// First drain all clients and SQL leases.
 	if err := s.drainClients(ctx, reporter); err != nil {
 		return err
@@ -193,6 +261,38 @@ func (s *Server) doDrain(ctx context.Context, reporter func(int, string)) error
 	return s.drainNode(ctx, reporter)
 }
 
+// recordDrainEvent begins an asynchronous task which attempts to log a "node
+// drain" event, so that the drain doesn't wait for it. This is best effort:
+// the node is about to shut down, so the write is not retried, and is
+// abandoned when the server quiesces.
+func (s *Server) recordDrainEvent(ctx context.Context) {
+	// Gate on the same switch as the node's other lifecycle events.
+	if !s.node.storeCfg.LogRangeEvents {
+		return
+	}
+	eventLogger := sql.MakeEventLogger(s.sqlServer.execCfg)
+	nodeID := int32(s.NodeID())
+	// The drain request may well be done before the event is written, so
+	// don't inherit its cancellation.
+	ctx = logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	if err := s.stopper.RunAsyncTask(ctx, "record-drain-event", func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		if err := contextutil.RunWithTimeout(ctx, "record drain event", 5*time.Second,
+			func(ctx context.Context) error {
+				return s.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+					return eventLogger.InsertEventRecord(
+						ctx, txn, sql.EventLogNodeDrain, nodeID, nodeID, struct{}{},
+					)
+				})
+			}); err != nil {
+			log.Warningf(ctx, "unable to record %s event: %s", sql.EventLogNodeDrain, err)
+		}
+	}); err != nil {
+		log.Warningf(ctx, "unable to record %s event: %s", sql.EventLogNodeDrain, err)
+	}
+}
+
 // isDraining returns true if either clients are being drained
 // or one of the stores on the node is not accepting replicas.
 func (s *Server) isDraining() bool {
@@ -204,8 +304,8 @@ func (s *Server) drainClients(ctx context.Context, reporter func(int, string)) e
 	// Mark the server as draining in a way that probes to
 	// /health?ready=1 will notice.
 	s.grpc.setMode(modeDraining)
-	// Wait for drainUnreadyWait. This will fail load balancer checks and
-	// delay draining so that client traffic can move off this node.
+	// Wait for server.shutdown.drain_wait. This will fail load balancer
+	// checks and delay draining so that client traffic can move off this node.
 	time.Sleep(drainWait.Get(&s.st.SV))
 
 	// Disable incoming SQL clients up to the queryWait timeout.
@@ -219,8 +319,6 @@ func (s *Server) drainClients(ctx context.Context, reporter func(int, string)) e
 	// Drain the SQL leases. This must be done after the pgServer has
 	// given sessions a chance to finish ongoing work.
 	s.sqlServer.leaseMgr.SetDraining(true /* drain */, reporter)
-
-	// Done. This executes the defers set above to drain SQL leases.
 	return nil
 }
 
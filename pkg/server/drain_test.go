@@ -77,6 +77,23 @@ func doTestDrain(tt *testing.T, newInterface bool) {
 	t.assertDraining(resp, true)
 	t.assertRemaining(resp, false)
 
+	// The start of the drain was recorded in the event log, once. Read it
+	// through another node, since the drained node refuses SQL clients. The
+	// event is written asynchronously.
+	testutils.SucceedsSoon(t, func() error {
+		var count int
+		if err := t.tc.ServerConn(1).QueryRow(
+			`SELECT count(*) FROM system.eventlog WHERE "eventType" = $1 AND "targetID" = $2`,
+			sql.EventLogNodeDrain, t.tc.Server(0).NodeID(),
+		).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			return errors.Errorf("expected 1 %s event, got %d", sql.EventLogNodeDrain, count)
+		}
+		return nil
+	})
+
 	// Now issue a drain request without drain but with shutdown.
 	// We're expecting the node to be shut down after that.
 	resp = t.sendShutdown()
@@ -0,0 +1,60 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"crypto"
+
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/pkg/errors"
+)
+
+// gossipInfoSigningEnabled enables the signing of the gossip infos
+// originated by this node, and the verification of those received from
+// other nodes. Infos are signed with the key of the node certificate, which
+// each node gossips along with its descriptor, and verified against the
+// certificate gossiped by their originating node once it is verified to be
+// issued by the cluster CA. Every node of the cluster must enable signing at
+// the same time: a node which doesn't sign its infos has them dropped by the
+// others. This is why it is opt-in.
+var gossipInfoSigningEnabled = envutil.EnvOrDefaultBool("COCKROACH_GOSSIP_SIGN_INFOS", false)
+
+// maybeEnableGossipInfoSigning sets up the signing of gossip infos if it is
+// enabled. It must be called before gossip is started.
+func (s *Server) maybeEnableGossipInfoSigning() error {
+	if !gossipInfoSigningEnabled {
+		return nil
+	}
+	if s.cfg.Insecure {
+		return errors.New("COCKROACH_GOSSIP_SIGN_INFOS requires a secure cluster")
+	}
+	tlsConfig, err := s.cfg.GetServerTLSConfig()
+	if err != nil {
+		return err
+	}
+	if len(tlsConfig.Certificates) == 0 || len(tlsConfig.Certificates[0].Certificate) == 0 {
+		return errors.New("no node certificate to sign gossip infos with")
+	}
+	if tlsConfig.RootCAs == nil {
+		return errors.New("no CA certificate to verify gossip infos with")
+	}
+	key, ok := tlsConfig.Certificates[0].PrivateKey.(crypto.Signer)
+	if !ok {
+		return errors.Errorf("node key of type %T cannot sign gossip infos",
+			tlsConfig.Certificates[0].PrivateKey)
+	}
+	s.gossip.SetInfoSigning(
+		gossip.NewInfoSigner(tlsConfig.Certificates[0].Certificate[0], key),
+		gossip.NewInfoVerifier(tlsConfig.RootCAs),
+	)
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// kvUserPermissions grants users other than root and node access to parts of
+// the key space through the KV service.
+var kvUserPermissions = settings.RegisterValidatedStringSetting(
+	"server.kv_api.user_permissions",
+	"comma-separated list of <user>:<r|rw>:<key prefix> entries granting users "+
+		"read or read-write access to the keys with the given prefix through the KV API",
+	"",
+	func(_ *settings.Values, v string) error {
+		_, err := parseKVPermissions(v)
+		return err
+	},
+)
+
+// kvServer implements serverpb.KVServer. Unlike roachpb.Internal, which
+// only serves requests addressed to replicas on the local node, it routes
+// each batch through the node's kv.DB so that clients outside the cluster,
+// like the `cockroach kv` commands, can address arbitrary keys. Callers with
+// a root or node certificate may address any key; other users are limited to
+// the prefixes granted to them by server.kv_api.user_permissions.
+type kvServer struct {
+	log.AmbientContext
+	db *kv.DB
+	st *cluster.Settings
+}
+
+var _ serverpb.KVServer = &kvServer{}
+
+func newKVServer(ambient log.AmbientContext, db *kv.DB, st *cluster.Settings) *kvServer {
+	ambient.AddLogTag("kv", nil)
+	return &kvServer{AmbientContext: ambient, db: db, st: st}
+}
+
+// Batch implements the serverpb.KVServer interface.
+func (s *kvServer) Batch(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) (*roachpb.BatchResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+	if err := validateKVBatch(ba); err != nil {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, "%s", err)
+	}
+	users, err := kvRequestUsers(ctx)
+	if err != nil {
+		return nil, grpcstatus.Errorf(codes.Unauthenticated, "%s", err)
+	}
+	// The setting is validated when it is changed, so this cannot fail.
+	perms, _ := parseKVPermissions(kvUserPermissions.Get(&s.st.SV))
+	if err := authorizeKVBatch(users, perms, ba); err != nil {
+		return nil, grpcstatus.Errorf(codes.PermissionDenied, "%s", err)
+	}
+	br, pErr := s.db.NonTransactionalSender().Send(ctx, *ba)
+	if pErr != nil {
+		log.VEventf(ctx, 2, "batch failed: %s", pErr)
+		br = &roachpb.BatchResponse{}
+		br.Error = pErr
+	}
+	return br, nil
+}
+
+// systemTablesSpan is the key space of the system tables, which the KV
+// service does not address even though it lies after keys.SystemMax.
+var systemTablesSpan = roachpb.Span{Key: keys.TableDataMin, EndKey: keys.UserTableDataMin}
+
+// validateKVBatch restricts the batches accepted by kvServer to
+// non-transactional point and range reads and writes on the keys above the
+// system key space, outside of the system tables. The meta ranges, system
+// keys and system tables are maintained by the cluster itself, and a write to
+// them, such as a DeleteRange spanning them, could render it unusable; this
+// holds for root and node too. Conditional puts let clients commit optimistic
+// transactions.
+func validateKVBatch(ba *roachpb.BatchRequest) error {
+	if ba.Txn != nil {
+		return errors.New("transactional batches are not supported")
+	}
+	if len(ba.Requests) == 0 {
+		return errors.New("empty batch")
+	}
+	for _, ru := range ba.Requests {
+		req := ru.GetInner()
+		switch req.(type) {
+		case *roachpb.GetRequest, *roachpb.PutRequest, *roachpb.ConditionalPutRequest,
+			*roachpb.DeleteRequest, *roachpb.ScanRequest, *roachpb.ReverseScanRequest,
+			*roachpb.DeleteRangeRequest:
+		default:
+			return errors.Errorf("unsupported request: %s", req.Method())
+		}
+		h := req.Header()
+		if h.Key.Compare(keys.SystemMax) < 0 || (len(h.EndKey) > 0 && h.EndKey.Compare(h.Key) <= 0) {
+			return errors.Errorf("only the keys from %s onwards are addressable: %s", keys.SystemMax, h.Span())
+		}
+		if systemTablesSpan.Overlaps(h.Span()) {
+			return errors.Errorf("the system tables in %s are not addressable: %s", systemTablesSpan, h.Span())
+		}
+	}
+	return nil
+}
+
+// kvRequestUsers returns the users named by the client certificate of the
+// request. In-process requests and requests to insecure servers act as root.
+func kvRequestUsers(ctx context.Context) ([]string, error) {
+	if grpcutil.IsLocalRequestContext(ctx) {
+		return []string{security.RootUser}, nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("internal authentication error: peer is not available in request context")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return []string{security.RootUser}, nil
+	}
+	return security.GetCertificateUsers(&tlsInfo.State)
+}
+
+// kvPermission grants a user access to the keys with a given prefix.
+type kvPermission struct {
+	user   string
+	prefix roachpb.Key
+	write  bool
+}
+
+// parseKVPermissions parses the value of server.kv_api.user_permissions.
+func parseKVPermissions(s string) ([]kvPermission, error) {
+	var perms []kvPermission
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, errors.Errorf("invalid permission %q: expected <user>:<r|rw>:<key prefix>", entry)
+		}
+		p := kvPermission{user: parts[0], prefix: roachpb.Key(parts[2])}
+		switch parts[1] {
+		case "r":
+		case "rw":
+			p.write = true
+		default:
+			return nil, errors.Errorf("invalid permission %q: access must be r or rw", entry)
+		}
+		perms = append(perms, p)
+	}
+	return perms, nil
+}
+
+// authorizeKVBatch checks that one of the users may perform every request in
+// the batch. The root and node users may address any key.
+func authorizeKVBatch(users []string, perms []kvPermission, ba *roachpb.BatchRequest) error {
+	if security.ContainsUser(security.RootUser, users) || security.ContainsUser(security.NodeUser, users) {
+		return nil
+	}
+	for _, ru := range ba.Requests {
+		req := ru.GetInner()
+		write := !roachpb.IsReadOnly(req)
+		if span := req.Header().Span(); !kvSpanPermitted(users, perms, span, write) {
+			access := "read"
+			if write {
+				access = "write"
+			}
+			return errors.Errorf("user %s does not have %s access to %s", users, access, span)
+		}
+	}
+	return nil
+}
+
+// kvSpanPermitted returns true if one of the permissions granted to the
+// users covers the whole span.
+func kvSpanPermitted(users []string, perms []kvPermission, span roachpb.Span, write bool) bool {
+	for _, p := range perms {
+		if !security.ContainsUser(p.user, users) || (write && !p.write) {
+			continue
+		}
+		if !bytes.HasPrefix(span.Key, p.prefix) {
+			continue
+		}
+		if len(span.EndKey) == 0 || span.EndKey.Compare(p.prefix.PrefixEnd()) <= 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// TestKVServerBatch verifies that the KV service evaluates batches which
+// address arbitrary keys and rejects the requests it does not support.
+func TestKVServerBatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, _, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	ts := s.(*TestServer)
+
+	rpcContext := newRPCTestContext(ts, testutils.NewTestBaseContext(security.RootUser))
+	conn, err := rpcContext.GRPCDialNode(ts.ServingRPCAddr(), ts.NodeID(), rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err)
+	client := serverpb.NewKVClient(conn)
+
+	send := func(reqs ...roachpb.Request) *roachpb.BatchResponse {
+		var ba roachpb.BatchRequest
+		ba.Add(reqs...)
+		br, err := client.Batch(ctx, &ba)
+		require.NoError(t, err)
+		require.Nil(t, br.Error)
+		return br
+	}
+
+	keyA, keyB := roachpb.Key("a"), roachpb.Key("b")
+	send(
+		roachpb.NewPut(keyA, roachpb.MakeValueFromString("1")),
+		roachpb.NewPut(keyB, roachpb.MakeValueFromString("2")),
+	)
+	v, err := kvDB.Get(ctx, keyB)
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), v.ValueBytes())
+
+	br := send(roachpb.NewScan(keyA, roachpb.Key("c"), false /* forUpdate */))
+	rows := br.Responses[0].GetScan().Rows
+	require.Len(t, rows, 2)
+	require.Equal(t, keyA, rows[0].Key)
+	require.Equal(t, keyB, rows[1].Key)
+
+	send(roachpb.NewDelete(keyA))
+	br = send(roachpb.NewGet(keyA))
+	require.Nil(t, br.Responses[0].GetGet().Value)
+
+	for _, req := range []roachpb.Request{
+		&roachpb.AdminSplitRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}, SplitKey: keyA},
+		roachpb.NewGet(keys.RangeDescriptorKey(roachpb.RKeyMin)),
+		roachpb.NewGet(keys.RangeMetaKey(roachpb.RKey(keyA)).AsRawKey()),
+		roachpb.NewPut(keys.NodeLivenessKey(1), roachpb.MakeValueFromString("1")),
+		roachpb.NewDeleteRange(roachpb.KeyMin, keyB, false /* returnKeys */),
+		roachpb.NewScan(keyB, keyA, false /* forUpdate */),
+		// The system tables are off limits, even to root.
+		roachpb.NewPut(roachpb.Key(keys.MakeTablePrefix(keys.UsersTableID)), roachpb.MakeValueFromString("1")),
+		roachpb.NewDeleteRange(
+			roachpb.Key(keys.MakeTablePrefix(keys.ZonesTableID)),
+			roachpb.Key(keys.MakeTablePrefix(keys.ZonesTableID+1)),
+			false, /* returnKeys */
+		),
+		roachpb.NewDeleteRange(keyB, roachpb.KeyMax, false /* returnKeys */),
+	} {
+		var ba roachpb.BatchRequest
+		ba.Add(req)
+		_, err := client.Batch(ctx, &ba)
+		require.Equal(t, codes.InvalidArgument, grpcstatus.Code(err), "%v", err)
+	}
+}
+
+func TestKVServerUserPermissions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	ts := s.(*TestServer)
+
+	rpcContext := newRPCTestContext(ts, testutils.NewTestBaseContext(security.TestUser))
+	conn, err := rpcContext.GRPCDialNode(ts.ServingRPCAddr(), ts.NodeID(), rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err)
+	client := serverpb.NewKVClient(conn)
+
+	send := func(req roachpb.Request) error {
+		var ba roachpb.BatchRequest
+		ba.Add(req)
+		br, err := client.Batch(ctx, &ba)
+		if err == nil && br.Error != nil {
+			err = br.Error.GoError()
+		}
+		return err
+	}
+	put := func(key string) error {
+		return send(roachpb.NewPut(roachpb.Key(key), roachpb.MakeValueFromString("v")))
+	}
+
+	// Without any permissions, users other than root and node are rejected.
+	require.Equal(t, codes.PermissionDenied, grpcstatus.Code(put("app/a")))
+
+	_, err = sqlDB.Exec(`SET CLUSTER SETTING server.kv_api.user_permissions = $1`,
+		security.TestUser+":rw:app/,"+security.TestUser+":r:shared/")
+	require.NoError(t, err)
+	testutils.SucceedsSoon(t, func() error {
+		return put("app/a")
+	})
+
+	require.NoError(t, send(roachpb.NewGet(roachpb.Key("shared/a"))))
+	require.NoError(t, send(roachpb.NewScan(roachpb.Key("app/"), roachpb.Key("app0"), false /* forUpdate */)))
+	for _, err := range []error{
+		put("shared/a"),
+		put("other"),
+		send(roachpb.NewScan(roachpb.Key("app/"), roachpb.Key("b"), false /* forUpdate */)),
+	} {
+		require.Equal(t, codes.PermissionDenied, grpcstatus.Code(err), "%v", err)
+	}
+}
+
+func TestParseKVPermissions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	perms, err := parseKVPermissions(" alice:rw:app/alice/, bob:r:key:with:colons")
+	require.NoError(t, err)
+	require.Equal(t, []kvPermission{
+		{user: "alice", prefix: roachpb.Key("app/alice/"), write: true},
+		{user: "bob", prefix: roachpb.Key("key:with:colons")},
+	}, perms)
+
+	for _, s := range []string{"alice", "alice:rw", ":r:a", "alice:r:", "alice:w:a"} {
+		if _, err := parseKVPermissions(s); !testutils.IsError(err, "invalid permission") {
+			t.Errorf("%q: expected invalid permission error, got %v", s, err)
+		}
+	}
+}
@@ -156,7 +156,8 @@ type Node struct {
 	recorder    *status.MetricsRecorder
 	startedAt   int64
 	lastUp      int64
-	initialBoot bool // True if this is the first time this node has started.
+	initialBoot bool              // True if this is the first time this node has started.
+	addedStores []roachpb.StoreID // Stores bootstrapped when the node started.
 	txnMetrics  kvcoord.TxnMetrics
 
 	perReplicaServer kvserver.Server
@@ -569,6 +570,7 @@ func (n *Node) bootstrapStores(
 				return err
 			}
 			n.addStore(s)
+			n.addedStores = append(n.addedStores, s.StoreID())
 			log.Infof(ctx, "bootstrapped store %s", s)
 			// Done regularly in Node.startGossip, but this cuts down the time
 			// until this store is used for range allocations.
@@ -769,8 +771,9 @@ func (n *Node) writeNodeStatus(ctx context.Context, alertTTL time.Duration) erro
 }
 
 // recordJoinEvent begins an asynchronous task which attempts to log a "node
-// join" or "node restart" event. This query will retry until it succeeds or the
-// server stops.
+// join" or "node restart" event, followed by a "node store added" event for
+// each store bootstrapped by the node. These queries will retry until they
+// succeed or the server stops.
 func (n *Node) recordJoinEvent() {
 	if !n.storeCfg.LogRangeEvents {
 		return
@@ -786,32 +789,59 @@ func (n *Node) recordJoinEvent() {
 	n.stopper.RunWorker(context.Background(), func(bgCtx context.Context) {
 		ctx, span := n.AnnotateCtxWithSpan(bgCtx, "record-join-event")
 		defer span.Finish()
-		retryOpts := base.DefaultRetryOptions()
-		retryOpts.Closer = n.stopper.ShouldStop()
-		for r := retry.Start(retryOpts); r.Next(); {
-			if err := n.storeCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-				return n.eventLogger.InsertEventRecord(
-					ctx,
-					txn,
-					logEventType,
-					int32(n.Descriptor.NodeID),
-					int32(n.Descriptor.NodeID),
-					struct {
-						Descriptor roachpb.NodeDescriptor
-						ClusterID  uuid.UUID
-						StartedAt  int64
-						LastUp     int64
-					}{n.Descriptor, n.clusterID.Get(), n.startedAt, lastUp},
-				)
-			}); err != nil {
-				log.Warningf(ctx, "%s: unable to log %s event: %s", n, logEventType, err)
-			} else {
-				return
-			}
+		n.recordEventWithRetry(ctx, logEventType, struct {
+			Descriptor roachpb.NodeDescriptor
+			ClusterID  uuid.UUID
+			StartedAt  int64
+			LastUp     int64
+		}{n.Descriptor, n.clusterID.Get(), n.startedAt, lastUp})
+		for _, storeID := range n.addedStores {
+			n.recordEventWithRetry(ctx, sql.EventLogNodeStoreAdded, struct {
+				StoreID roachpb.StoreID
+			}{storeID})
 		}
 	})
 }
 
+// recordCertificatesReloadedEvent begins an asynchronous task which attempts
+// to log a "node certificates reloaded" event. This query will retry until it
+// succeeds or the server stops.
+func (n *Node) recordCertificatesReloadedEvent() {
+	if !n.storeCfg.LogRangeEvents {
+		return
+	}
+	n.stopper.RunWorker(context.Background(), func(bgCtx context.Context) {
+		ctx, span := n.AnnotateCtxWithSpan(bgCtx, "record-certificates-reloaded-event")
+		defer span.Finish()
+		n.recordEventWithRetry(ctx, sql.EventLogNodeCertificatesReloaded, struct{}{})
+	})
+}
+
+// recordEventWithRetry logs an event about this node, retrying until it
+// succeeds or the server stops.
+func (n *Node) recordEventWithRetry(
+	ctx context.Context, logEventType sql.EventLogType, info interface{},
+) {
+	retryOpts := base.DefaultRetryOptions()
+	retryOpts.Closer = n.stopper.ShouldStop()
+	for r := retry.Start(retryOpts); r.Next(); {
+		if err := n.storeCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			return n.eventLogger.InsertEventRecord(
+				ctx,
+				txn,
+				logEventType,
+				int32(n.Descriptor.NodeID),
+				int32(n.Descriptor.NodeID),
+				info,
+			)
+		}); err != nil {
+			log.Warningf(ctx, "%s: unable to log %s event: %s", n, logEventType, err)
+		} else {
+			return
+		}
+	}
+}
+
 // If we receive a (proto-marshaled) roachpb.BatchRequest whose Requests contain
 // a message type unknown to this node, we will end up with a zero entry in the
 // slice. If we don't error out early, this breaks all sorts of assumptions and
@@ -193,6 +193,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			return nil, errors.Wrap(err, "instantiating clock source")
 		}
 		clock = hlc.NewClock(clockSrc.UnixNano, time.Duration(cfg.MaxOffset))
+	} else if knobs, ok := cfg.TestingKnobs.Server.(*TestingKnobs); ok && knobs.ClockSource != nil {
+		clock = hlc.NewClock(knobs.ClockSource, time.Duration(cfg.MaxOffset))
 	} else {
 		clock = hlc.NewClock(hlc.UnixNano, time.Duration(cfg.MaxOffset))
 	}
@@ -505,6 +507,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		}
 		gw.RegisterService(grpcServer.Server)
 	}
+	serverpb.RegisterKVServer(grpcServer.Server, newKVServer(cfg.AmbientCtx, db, st))
 
 	sqlServer, err := newSQLServer(ctx, sqlServerArgs{
 		sqlServerOptionalArgs: sqlServerOptionalArgs{
@@ -536,7 +539,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	debugServer := debug.NewServer(st, sqlServer.pgServer.HBADebugFn())
+	debugServer := debug.NewServer(st, sqlServer.pgServer.HBADebugFn(), g.ConnectivityDebugFn())
 	node.InitLogger(sqlServer.execCfg)
 
 	*lateBoundServer = Server{
@@ -1214,6 +1217,9 @@ func (s *Server) Start(ctx context.Context) error {
 	advSQLAddrU := util.NewUnresolvedAddr("tcp", s.cfg.SQLAdvertiseAddr)
 	filtered := s.cfg.FilterGossipBootstrapResolvers(ctx, listenAddrU, advAddrU)
 
+	if err := s.maybeEnableGossipInfoSigning(); err != nil {
+		return err
+	}
 	s.gossip.Start(advAddrU, filtered)
 	log.Event(ctx, "started gossip")
 
@@ -1544,7 +1550,15 @@ func (s *Server) Start(ctx context.Context) error {
 
 	// Record that this node joined the cluster in the event log. Since this
 	// executes a SQL query, this must be done after the SQL layer is ready.
+	// The same goes for certificate reloads from now on.
 	s.node.recordJoinEvent()
+	if !s.cfg.Insecure {
+		cm, err := s.cfg.GetCertificateManager()
+		if err != nil {
+			return err
+		}
+		cm.SetOnReload(s.node.recordCertificatesReloadedEvent)
+	}
 
 	if err := s.sqlServer.start(
 		workersCtx,
@@ -1916,6 +1930,32 @@ func (s *Server) startSampleEnvironment(ctx context.Context, frequency time.Dura
 	return nil
 }
 
+// SetCacheSize resizes the block cache shared by the stores of the node.
+// It returns an error if a store doesn't use the shared cache, or uses an
+// engine whose cache can't be resized while it is running; the other
+// stores are still resized.
+func (s *Server) SetCacheSize(size int64) error {
+	var resizeErr error
+	for _, eng := range s.engines {
+		resizer, ok := eng.(storage.CacheResizer)
+		if !ok {
+			resizeErr = errors.Errorf("the block cache of the %s engine cannot be resized while it is running",
+				s.cfg.StorageEngine)
+			continue
+		}
+		if err := resizer.SetSharedCacheCapacity(size); err != nil {
+			resizeErr = err
+		}
+	}
+	return resizeErr
+}
+
+// SetRPCHeartbeatInterval changes the interval between the heartbeats of
+// the node's RPC connections. See rpc.Context.SetHeartbeatInterval.
+func (s *Server) SetRPCHeartbeatInterval(d time.Duration) {
+	s.rpcContext.SetHeartbeatInterval(d)
+}
+
 // Stop stops the server.
 func (s *Server) Stop() {
 	s.stopper.Stop(context.Background())
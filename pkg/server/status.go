@@ -15,6 +15,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/build"
@@ -310,6 +312,13 @@ func (s *statusServer) EngineStats(
 			}
 			engineStatsInfo.TickersAndHistograms = tickersAndHistograms
 		}
+		for _, op := range store.SlowStorageOps() {
+			engineStatsInfo.SlowOps = append(engineStatsInfo.SlowOps, serverpb.SlowStorageOp{
+				Op:       op.Op,
+				Start:    op.Start,
+				Duration: op.Duration,
+			})
+		}
 
 		resp.Stats = append(resp.Stats, engineStatsInfo)
 		return nil
@@ -320,6 +329,50 @@ func (s *statusServer) EngineStats(
 	return resp, nil
 }
 
+// ConnectionStatuses returns the status of every connection in the RPC
+// connection cache of the given node, including the clock offset and latency
+// measured for the remote address.
+func (s *statusServer) ConnectionStatuses(
+	ctx context.Context, req *serverpb.ConnectionStatusesRequest,
+) (*serverpb.ConnectionStatusesResponse, error) {
+	if _, err := s.admin.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+	nodeID, local, err := s.parseNodeID(req.NodeId)
+	if err != nil {
+		return nil, grpcstatus.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	if !local {
+		status, err := s.dialNode(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.ConnectionStatuses(ctx, req)
+	}
+
+	resp := new(serverpb.ConnectionStatusesResponse)
+	for _, cs := range s.rpcCtx.ConnectionStatuses() {
+		resp.Connections = append(resp.Connections, serverpb.ConnectionStatus{
+			TargetAddr:       cs.TargetAddr,
+			NodeID:           cs.NodeID,
+			Class:            int32(cs.Class),
+			Healthy:          cs.Healthy,
+			Error:            cs.Error,
+			Age:              cs.Age,
+			LastHeartbeat:    cs.LastHeartbeat,
+			Latency:          cs.Latency,
+			ClockOffset:      time.Duration(cs.RemoteOffset.Offset),
+			ClockUncertainty: time.Duration(cs.RemoteOffset.Uncertainty),
+			BuildTag:         cs.BuildTag,
+		})
+	}
+	return resp, nil
+}
+
 // Allocator returns simulated allocator info for the ranges on the given node.
 func (s *statusServer) Allocator(
 	ctx context.Context, req *serverpb.AllocatorRequest,
@@ -640,6 +693,7 @@ func extractCertFields(contents []byte, details *serverpb.CertificateDetails) er
 			PublicKey:          pubKeyInfo,
 			KeyUsage:           security.KeyUsageToString(c.KeyUsage),
 			ExtendedKeyUsage:   extKeyUsage,
+			Fingerprint:        fmt.Sprintf("%x", sha256.Sum256(c.Raw)),
 		})
 	}
 	return nil
@@ -1111,8 +1165,12 @@ func (s *statusServer) Node(
 		return nil, grpcstatus.Errorf(codes.Internal, err.Error())
 	}
 
+	row := b.Results[0].Rows[0]
+	if !row.Exists() {
+		return nil, grpcstatus.Errorf(codes.NotFound, "node %d status not found", nodeID)
+	}
 	var nodeStatus statuspb.NodeStatus
-	if err := b.Results[0].Rows[0].ValueProto(&nodeStatus); err != nil {
+	if err := row.ValueProto(&nodeStatus); err != nil {
 		err = errors.Errorf("could not unmarshal NodeStatus from %s: %s", key, err)
 		log.Error(ctx, err)
 		return nil, grpcstatus.Errorf(codes.Internal, err.Error())
@@ -13,6 +13,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -662,6 +663,13 @@ func TestNodeStatusResponse(t *testing.T) {
 			t.Errorf("node status descriptors are not equal\nexpected:%+v\nactual:%+v\n", s.node.Descriptor, nodeStatus.Desc)
 		}
 	}
+
+	// Requesting the status of a node which does not exist returns an error
+	// rather than an empty status.
+	var nodeStatus statuspb.NodeStatus
+	if err := getStatusJSONProto(s, "nodes/99", &nodeStatus); !testutils.IsError(err, "status not found") {
+		t.Fatalf("expected status not found error, got %v", err)
+	}
 }
 
 // TestMetricsRecording verifies that Node statistics are periodically recorded
@@ -940,6 +948,36 @@ func TestRangesResponse(t *testing.T) {
 	}
 }
 
+// TestRangesResponseFilter verifies that the ranges endpoint can be used to
+// inspect individual ranges.
+func TestRangesResponseFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ts := startServer(t)
+	defer ts.Stopper().Stop(context.TODO())
+
+	var response serverpb.RangesResponse
+	if err := getStatusJSONProto(ts, "ranges/local", &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Ranges) < 2 {
+		t.Fatalf("expected at least two ranges, got %d", len(response.Ranges))
+	}
+
+	rangeID := response.Ranges[len(response.Ranges)-1].State.Desc.RangeID
+	reqURI := fmt.Sprintf("ranges/local?range_ids=%d", rangeID)
+	if err := getStatusJSONProto(ts, reqURI, &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Ranges) != 1 {
+		t.Fatalf("request URI was %s, expected exactly one range, got %d", reqURI, len(response.Ranges))
+	}
+	if ri := response.Ranges[0]; ri.State.Desc.RangeID != rangeID {
+		t.Errorf("expected range %d, got %d", rangeID, ri.State.Desc.RangeID)
+	} else if ri.State.LastIndex == 0 {
+		t.Error("expected positive LastIndex")
+	}
+}
+
 func TestRaftDebug(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	s := startServer(t)
@@ -1003,6 +1041,34 @@ func TestStatusVars(t *testing.T) {
 	}
 }
 
+// TestStatusConnections verifies that the /_status/connections endpoint lists
+// the node's connections to its peers, including when the request is
+// forwarded from another node.
+func TestStatusConnections(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := serverutils.StartTestCluster(t, 2, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(context.Background())
+
+	for _, s := range []serverutils.TestServerInterface{tc.Server(0), tc.Server(1)} {
+		nodeID := tc.Server(0).NodeID().String()
+		testutils.SucceedsSoon(t, func() error {
+			var resp serverpb.ConnectionStatusesResponse
+			if err := getStatusJSONProto(s, "connections/"+nodeID, &resp); err != nil {
+				return err
+			}
+			for _, status := range resp.Connections {
+				if status.NodeID == tc.Server(1).NodeID() && status.Healthy {
+					if status.LastHeartbeat.IsZero() {
+						return errors.Errorf("healthy connection without heartbeat: %+v", status)
+					}
+					return nil
+				}
+			}
+			return errors.Errorf("no healthy connection to n2 in %+v", resp.Connections)
+		})
+	}
+}
+
 func TestSpanStatsResponse(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ts := startServer(t)
@@ -1143,6 +1209,16 @@ func TestCertificatesResponse(t *testing.T) {
 	} else if a, e := cert.Data, nodeFile; !bytes.Equal(a, e) {
 		t.Errorf("mismatched contents: %s vs %s", a, e)
 	}
+
+	// The fingerprint lets operators check which certificate a node has
+	// loaded while certificates are being rotated.
+	nodeCerts, err := security.PEMContentsToX509(nodeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, e := cert.Fields[0].Fingerprint, fmt.Sprintf("%x", sha256.Sum256(nodeCerts[0].Raw)); a != e {
+		t.Errorf("wrong fingerprint %s, expected %s", a, e)
+	}
 }
 
 func TestDiagnosticsResponse(t *testing.T) {
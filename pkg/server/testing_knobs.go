@@ -36,6 +36,9 @@ type TestingKnobs struct {
 	SignalAfterGettingRPCAddress chan struct{}
 	// ContextTestingKnobs allows customization of the RPC context testing knobs.
 	ContextTestingKnobs rpc.ContextTestingKnobs
+	// ClockSource, if set, replaces the system clock as the source of the
+	// physical time of the server's HLC, e.g. (*hlc.ManualClock).UnixNano.
+	ClockSource func() int64
 	// DiagnosticsTestingKnobs allows customization of diagnostics testing knobs.
 	DiagnosticsTestingKnobs diagnosticspb.TestingKnobs
 
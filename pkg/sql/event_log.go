@@ -87,6 +87,14 @@ const (
 	// EventLogNodeRecommissioned is recorded when a decommissioned node is
 	// recommissioned.
 	EventLogNodeRecommissioned EventLogType = "node_recommissioned"
+	// EventLogNodeDrain is recorded when a node starts draining, before it
+	// is shut down.
+	EventLogNodeDrain EventLogType = "node_drain"
+	// EventLogNodeStoreAdded is recorded when a node bootstraps a new store.
+	EventLogNodeStoreAdded EventLogType = "node_store_added"
+	// EventLogNodeCertificatesReloaded is recorded when a node reloads its
+	// certificates, e.g. after they were rotated.
+	EventLogNodeCertificatesReloaded EventLogType = "node_certificates_reloaded"
 
 	// EventLogSetClusterSetting is recorded when a cluster setting is changed.
 	EventLogSetClusterSetting EventLogType = "set_cluster_setting"
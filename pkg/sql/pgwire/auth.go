@@ -160,7 +160,11 @@ func (c *conn) findAuthenticationMethod(
 	// If the client is using SSL, retrieve the TLS state to provide as
 	// input to the method.
 	if authOpt.connType == hba.ConnHostSSL {
-		tlsConn, ok := c.conn.(*readTimeoutConn).Conn.(*tls.Conn)
+		// The connection is a *tls.Conn, usually wrapped by the certificate
+		// manager (see maybeUpgradeToSecureConn).
+		tlsConn, ok := c.conn.(*readTimeoutConn).Conn.(interface {
+			ConnectionState() tls.ConnectionState
+		})
 		if !ok {
 			err = errors.AssertionFailedf("server reports hostssl conn without TLS state")
 			return
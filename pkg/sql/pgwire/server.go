@@ -750,6 +750,20 @@ func (s *Server) maybeUpgradeToSecureConn(
 
 	// Finally, re-read the version/command from the client.
 	newVersion, *buf, serverErr = s.readVersion(newConn)
+	if serverErr != nil {
+		return
+	}
+
+	// The TLS handshake completed while reading. Make sure the connection is
+	// closed if the client certificate it presented gets revoked.
+	if tlsConn, ok := newConn.(*tls.Conn); ok {
+		cm, err := s.cfg.GetCertificateManager()
+		if err != nil {
+			serverErr = err
+			return
+		}
+		newConn = cm.TrackConn(tlsConn, tlsConn.ConnectionState().VerifiedChains)
+	}
 	return
 }
 
@@ -14,15 +14,18 @@ backends (e.g. LevelDB, RocksDB, etc.) via the Engine interface. At
 one level higher, MVCC provides multi-version concurrency control
 capability on top of an Engine instance.
 
-The Engine interface provides an API for key-value stores. InMem
-implements an in-memory engine using a sorted map. RocksDB implements
-an engine for data stored to local disk using RocksDB, a variant of
-LevelDB.
+The Engine interface provides an API for key-value stores. RocksDB
+implements an engine for data stored to local disk using RocksDB, a
+variant of LevelDB. Pebble implements the same interface in pure Go.
+NewInMem returns an in-memory engine of either type; the Pebble variant
+keeps its files on an in-memory filesystem (vfs.NewMem) and so needs no
+C++ code or disk, making it suitable for unit tests, simulations and
+ephemeral nodes (see COCKROACH_STORAGE_ENGINE and --storage-engine).
 
 MVCC provides a multi-version concurrency control system on top of an
 engine. MVCC is the basis for Cockroach's support for distributed
-transactions. It is intended for direct use from storage.Range
-objects.
+transactions. It is intended for direct use from kvserver.Replica
+objects, through the evaluation of the KV commands.
 
 Notes on MVCC architecture
 
@@ -68,6 +71,28 @@ The following is an example of the sort order for MVCC key/value pairs:
 		keyA_Timestamp_0: value of version_0
 		keyB: MVCCMetadata of keyB
 
+Writes go through MVCCPut and its variants (MVCCConditionalPut,
+MVCCIncrement, MVCCDelete, MVCCDeleteRange, ...). A write outside of a
+transaction directly adds a version at the supplied timestamp. A
+transactional write adds a provisional version at the transaction's
+write timestamp along with an MVCCMetadata referencing the transaction:
+the intent. The intent is later resolved by MVCCResolveWriteIntent,
+which either removes the metadata when the transaction commits, moving
+the provisional version to the commit timestamp if needed, or removes
+both when it aborts. A write below the timestamp of the most recent
+version returns a WriteTooOldError.
+
+Reads go through MVCCGet and MVCCScan, which return the most recent
+version of each key at or below the read timestamp, skipping deletion
+tombstones unless asked otherwise. The scans merge the versions of each
+key on the fly and are implemented by pebbleMVCCScanner for Pebble and
+by the C++ mvccScanner in libroach for RocksDB. A read which encounters
+the intent of another transaction at or below the read timestamp
+returns a WriteIntentError so that the caller can push the transaction,
+unless it is inconsistent, in which case the intent is returned
+separately and the read sees the version below it. A transaction sees
+its own intents, as of the sequence number of the read.
+
 The binary encoding used on the MVCC keys allows arbitrary keys to be
 stored in the map (no restrictions on intermediate nil-bytes, for
 example), while still sorting lexicographically and guaranteeing that
@@ -304,8 +304,8 @@ type Writer interface {
 	// (stored as byte slices with a special tag on the roachpb.Value) are
 	// combined with specialized logic beyond that of simple byte slices.
 	//
-	// The logic for merges is written in db.cc in order to be compatible with
-	// RocksDB.
+	// The logic for merges is written in merge.cc in order to be compatible
+	// with RocksDB, and mirrored by MVCCValueMerger for Pebble.
 	//
 	// It is safe to modify the contents of the arguments after Merge returns.
 	Merge(key MVCCKey, value []byte) error
@@ -362,7 +362,9 @@ type Engine interface {
 	GetAuxiliaryDir() string
 	// NewBatch returns a new instance of a batched engine which wraps
 	// this engine. Batched engines accumulate all mutations and apply
-	// them atomically on a call to Commit().
+	// them atomically on a call to Commit(). Reads from the batch see the
+	// mutations buffered in it layered on top of the engine, so that a
+	// command evaluated against a batch reads its own writes.
 	NewBatch() Batch
 	// NewReadOnly returns a new instance of a ReadWriter that wraps this
 	// engine. This wrapper panics when unexpected operations (e.g., write
@@ -397,8 +399,11 @@ type Engine interface {
 	// NewSnapshot returns a new instance of a read-only snapshot
 	// engine. Snapshots are instantaneous and, as long as they're
 	// released relatively quickly, inexpensive. Snapshots are released
-	// by invoking Close(). Note that snapshots must not be used after the
-	// original engine has been stopped.
+	// by invoking Close(). Iterators created from a snapshot observe the
+	// same point-in-time view, regardless of concurrent writes to the
+	// engine, which makes snapshots suitable for long scans such as the
+	// generation of Raft snapshots. Note that snapshots must not be used
+	// after the original engine has been stopped.
 	NewSnapshot() Reader
 	// Type returns engine type.
 	Type() enginepb.EngineType
@@ -436,6 +441,15 @@ type Engine interface {
 	CreateCheckpoint(dir string) error
 }
 
+// CacheResizer is implemented by the engines whose block cache can be
+// resized while they are open.
+type CacheResizer interface {
+	// SetSharedCacheCapacity sets the capacity of the block cache shared by
+	// the engines of a node, as sized by the --cache flag. It returns an
+	// error if the engine doesn't use the shared cache.
+	SetSharedCacheCapacity(size int64) error
+}
+
 // Batch is the interface for batch specific operations.
 type Batch interface {
 	ReadWriter
@@ -503,6 +517,7 @@ type Stats struct {
 	TableReadersMemEstimate        int64
 	PendingCompactionBytesEstimate int64
 	L0FileCount                    int64
+	WriteStalls                    int64
 }
 
 // EnvStats is a set of RocksDB env stats, including encryption status.
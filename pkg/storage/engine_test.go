@@ -17,6 +17,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -456,6 +457,16 @@ func TestEngineMerge(t *testing.T) {
 					},
 					appender("xyz"),
 				},
+				{
+					// Test case with integers, which are summed.
+					mvccKey("counter"),
+					[][]byte{
+						counter(1),
+						counter(-5),
+						counter(100),
+					},
+					counter(96),
+				},
 				{
 					// Test case with RawBytes and MergeTimestamp.
 					mvccKey("timeseriesmerged"),
@@ -523,6 +534,38 @@ func TestEngineMerge(t *testing.T) {
 	}
 }
 
+// TestEngineMergeMixedOperands verifies that the engines agree on refusing to
+// merge integers with the other types of values, whichever comes first.
+func TestEngineMergeMixedOperands(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testcases := [][][]byte{
+		{counter(1), appender("a")},
+		{appender("a"), counter(1)},
+		{counter(1), counter(2), appender("a")},
+		{counter(1), timeSeriesRow(testtime, 1000, []tsSample{{1, 1, 5, 5, 5}}...)},
+		{timeSeriesRow(testtime, 1000, []tsSample{{1, 1, 5, 5, 5}}...), counter(1)},
+	}
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			for i, merges := range testcases {
+				key := mvccKey(fmt.Sprintf("mixed%d", i))
+				for _, update := range merges {
+					if err := engine.Merge(key, update); err != nil {
+						t.Fatalf("%d: %+v", i, err)
+					}
+				}
+				if _, err := engine.Get(key); err == nil {
+					t.Errorf("%d: expected the merge of mixed operands to fail", i)
+				}
+			}
+		})
+	}
+}
+
 func TestEngineMustExist(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -548,6 +591,93 @@ func TestEngineMustExist(t *testing.T) {
 	test(enginepb.EngineTypePebble, "no such file or directory")
 }
 
+// TestEngineWALSyncModes verifies that synced commits and writes succeed
+// and are readable with each WAL sync mode, and that the engines close
+// cleanly.
+func TestEngineWALSyncModes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(saved time.Duration) { periodicWALSyncInterval = saved }(periodicWALSyncInterval)
+	periodicWALSyncInterval = time.Millisecond
+
+	for _, engineType := range []enginepb.EngineType{enginepb.EngineTypeRocksDB, enginepb.EngineTypePebble} {
+		for _, mode := range []base.WALSyncMode{base.WALSyncAlways, base.WALSyncPeriodic, base.WALSyncNever} {
+			t.Run(fmt.Sprintf("%s/%s", engineType, mode), func(t *testing.T) {
+				tempDir, dirCleanupFn := testutils.TempDir(t)
+				defer dirCleanupFn()
+
+				engine, err := NewEngine(engineType, 1<<20, base.StorageConfig{
+					Dir:     tempDir,
+					WALSync: mode,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer engine.Close()
+
+				// Record whether the commits asked to sync waited for the WAL
+				// to be synced, which only the WALSyncAlways mode does.
+				var synced []bool
+				testingSyncWALHook = func(s bool) { synced = append(synced, s) }
+				defer func() { testingSyncWALHook = nil }()
+				expectSynced := func(op string, expected bool) {
+					t.Helper()
+					if len(synced) == 0 {
+						t.Fatalf("%s: the WAL sync mode was not consulted", op)
+					}
+					for _, s := range synced {
+						if s != expected {
+							t.Fatalf("%s: expected synced=%t, got %t", op, expected, s)
+						}
+					}
+					synced = nil
+				}
+
+				batch := engine.NewBatch()
+				if err := batch.Put(mvccKey("a"), []byte("1")); err != nil {
+					t.Fatal(err)
+				}
+				repr := batch.Repr()
+				if err := batch.Commit(true /* sync */); err != nil {
+					t.Fatal(err)
+				}
+				batch.Close()
+				expectSynced("commit", mode == base.WALSyncAlways)
+
+				// Batches applied by repr, as on the raft apply path, follow the
+				// mode as well.
+				batch = engine.NewBatch()
+				if err := batch.Put(mvccKey("c"), []byte("3")); err != nil {
+					t.Fatal(err)
+				}
+				if err := engine.ApplyBatchRepr(batch.Repr(), true /* sync */); err != nil {
+					t.Fatal(err)
+				}
+				batch.Close()
+				expectSynced("sync apply", mode == base.WALSyncAlways)
+				if err := engine.ApplyBatchRepr(repr, false /* sync */); err != nil {
+					t.Fatal(err)
+				}
+				expectSynced("non-sync apply", false)
+
+				if err := engine.Put(mvccKey("b"), []byte("2")); err != nil {
+					t.Fatal(err)
+				}
+				// Let the periodic syncer run.
+				time.Sleep(10 * time.Millisecond)
+
+				for key, expected := range map[string]string{"a": "1", "b": "2", "c": "3"} {
+					if val, err := engine.Get(mvccKey(key)); err != nil {
+						t.Fatal(err)
+					} else if string(val) != expected {
+						t.Fatalf("%s: expected %q, but got %q", key, expected, val)
+					}
+				}
+			})
+		}
+	}
+}
+
 func TestEngineTimeBound(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -989,6 +1119,52 @@ func TestSnapshot(t *testing.T) {
 	}
 }
 
+// TestSnapshotIterator verifies that an iterator over a snapshot observes
+// the point-in-time view of the snapshot while the engine is concurrently
+// written to.
+func TestSnapshotIterator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			for _, k := range []string{"a", "c", "e"} {
+				if err := engine.Put(mvccKey(k), []byte(k)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			snap := engine.NewSnapshot()
+			defer snap.Close()
+			iter := snap.NewIterator(IterOptions{UpperBound: roachpb.KeyMax})
+			defer iter.Close()
+
+			var keys []string
+			for iter.SeekGE(NilKey); ; iter.Next() {
+				if ok, err := iter.Valid(); err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					break
+				}
+				keys = append(keys, string(iter.UnsafeKey().Key))
+				// Writes performed during the scan, including to keys which the
+				// iterator has yet to reach, are not visible.
+				if err := engine.Put(mvccKey(string(iter.UnsafeKey().Key)+"b"), []byte("x")); err != nil {
+					t.Fatal(err)
+				}
+				if err := engine.Clear(mvccKey("e")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if expected := []string{"a", "c", "e"}; !reflect.DeepEqual(expected, keys) {
+				t.Fatalf("expected %v, but got %v", expected, keys)
+			}
+		})
+	}
+}
+
 // TestSnapshotMethods verifies that snapshots allow only read-only
 // engine operations.
 func TestSnapshotMethods(t *testing.T) {
@@ -1122,6 +1298,43 @@ func TestCreateCheckpoint(t *testing.T) {
 	}
 }
 
+func TestComputeCapacity(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// In-memory stores report their maximum size as entirely available.
+	capacity, err := computeCapacity("", 100<<20)
+	require.NoError(t, err)
+	require.Equal(t, roachpb.StoreCapacity{Capacity: 100 << 20, Available: 100 << 20}, capacity)
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	// The used bytes are those of all of the files in the store directory,
+	// including its subdirectories.
+	const fileSize = 1 << 20
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "auxiliary"), 0755))
+	for _, name := range []string{"a", filepath.Join("auxiliary", "b")} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), make([]byte, fileSize), 0644))
+	}
+
+	capacity, err = computeCapacity(dir, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2*fileSize), capacity.Used)
+	require.True(t, capacity.Capacity > 0)
+	require.True(t, capacity.Available <= capacity.Capacity)
+
+	// A maximum size smaller than the disk bounds the capacity, and the used
+	// bytes count against it.
+	capacity, err = computeCapacity(dir, 10*fileSize)
+	require.NoError(t, err)
+	require.Equal(t, int64(10*fileSize), capacity.Capacity)
+	require.True(t, capacity.Available <= 8*fileSize, "available %d", capacity.Available)
+
+	capacity, err = computeCapacity(dir, fileSize)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), capacity.Available)
+}
+
 func TestIngestDelayLimit(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	s := cluster.MakeTestingClusterSettings()
@@ -101,3 +101,44 @@ func TestTxnSeqIsIgnored(t *testing.T) {
 		}
 	}
 }
+
+func TestMVCCStatsAging(t *testing.T) {
+	const second = int64(1e9)
+	ms := enginepb.MVCCStats{
+		LastUpdateNanos: 10 * second,
+		LiveBytes:       10,
+		KeyBytes:        20,
+		ValBytes:        30,
+		IntentCount:     2,
+	}
+	assert.Equal(t, int64(50), ms.Total())
+	assert.Equal(t, int64(40), ms.GCBytes())
+
+	// The ages are computed on a copy, and increase by one for every whole
+	// second boundary crossed.
+	assert.Equal(t, int64(40*3), ms.GCByteAge(13*second+second/2))
+	assert.Equal(t, float64(3), ms.AvgIntentAge(13*second+second/2))
+	assert.Equal(t, int64(0), ms.GCBytesAge)
+	assert.Equal(t, int64(0), ms.GCByteAge(10*second+second/2))
+
+	// Forward doesn't move the stats back in time.
+	ms.Forward(9 * second)
+	assert.Equal(t, 10*second, ms.LastUpdateNanos)
+
+	// Add and Subtract age both stats to the newest of the two.
+	oms := enginepb.MVCCStats{LastUpdateNanos: 12 * second, KeyBytes: 5, ValBytes: 5}
+	sum := ms
+	sum.Add(oms)
+	assert.Equal(t, enginepb.MVCCStats{
+		LastUpdateNanos: 12 * second,
+		LiveBytes:       10,
+		KeyBytes:        25,
+		ValBytes:        35,
+		IntentCount:     2,
+		IntentAge:       2 * 2,
+		GCBytesAge:      40 * 2,
+	}, sum)
+	sum.Subtract(oms)
+	ms.AgeTo(12 * second)
+	assert.Equal(t, ms, sum)
+}
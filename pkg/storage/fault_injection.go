@@ -0,0 +1,284 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+)
+
+// FaultOp identifies the class of operation a fault is being considered for.
+type FaultOp int
+
+const (
+	// FaultOpWrite is a write applied directly to the engine, or the commit of
+	// a batch which does not request a sync.
+	FaultOpWrite FaultOp = iota
+	// FaultOpSync is a write which must be durable before it returns: a synced
+	// batch commit or ApplyBatchRepr, or a Flush.
+	FaultOpSync
+	// FaultOpWriteFile is a call to WriteFile.
+	FaultOpWriteFile
+)
+
+// String implements fmt.Stringer.
+func (op FaultOp) String() string {
+	switch op {
+	case FaultOpWrite:
+		return "write"
+	case FaultOpSync:
+		return "sync"
+	case FaultOpWriteFile:
+		return "write-file"
+	default:
+		return "unknown"
+	}
+}
+
+// Fault describes what happens to a single operation. Delay is always
+// observed first. If Torn is set, only a prefix of the operation is applied:
+// the first half of the entries of a batch, or the first half of the data
+// passed to WriteFile. Operations which cannot be partially applied are not
+// applied at all. Err, if set, is returned to the caller; it is returned
+// without applying the operation unless Torn is also set.
+type Fault struct {
+	Err   error
+	Delay time.Duration
+	Torn  bool
+}
+
+// FaultFn is consulted before every operation which FaultInjectingEngine
+// intercepts. Returning nil lets the operation proceed normally.
+type FaultFn func(op FaultOp) *Fault
+
+// FaultInjectingEngine wraps an Engine and injects errors, latency and torn
+// writes into its write path according to a schedule installed by the test.
+// All read operations are passed through to the wrapped engine untouched.
+//
+// This engine is only meant to be used in testing.
+type FaultInjectingEngine struct {
+	Engine
+
+	mu struct {
+		syncutil.Mutex
+		fn FaultFn
+	}
+}
+
+var _ Engine = &FaultInjectingEngine{}
+
+// NewFaultInjectingEngine wraps the supplied engine. No faults are injected
+// until SetFaultFn is called.
+func NewFaultInjectingEngine(eng Engine) *FaultInjectingEngine {
+	return &FaultInjectingEngine{Engine: eng}
+}
+
+// SetFaultFn installs the schedule used to decide which operations fail.
+// Passing nil disables fault injection.
+func (f *FaultInjectingEngine) SetFaultFn(fn FaultFn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mu.fn = fn
+}
+
+// maybeInject consults the fault schedule for the given operation and sleeps
+// for any requested delay. The returned fault, if non-nil, must be honored by
+// the caller.
+func (f *FaultInjectingEngine) maybeInject(op FaultOp) *Fault {
+	f.mu.Lock()
+	fn := f.mu.fn
+	f.mu.Unlock()
+	if fn == nil {
+		return nil
+	}
+	fault := fn(op)
+	if fault == nil {
+		return nil
+	}
+	if fault.Delay > 0 {
+		time.Sleep(fault.Delay)
+	}
+	if fault.Err == nil && !fault.Torn {
+		return nil
+	}
+	return fault
+}
+
+// intercept runs a write which cannot be partially applied.
+func (f *FaultInjectingEngine) intercept(op FaultOp, write func() error) error {
+	if fault := f.maybeInject(op); fault != nil {
+		return fault.Err
+	}
+	return write()
+}
+
+func syncOp(sync bool) FaultOp {
+	if sync {
+		return FaultOpSync
+	}
+	return FaultOpWrite
+}
+
+// applyTornBatchRepr applies the first half of the entries in the given
+// batch representation to the wrapped engine.
+func (f *FaultInjectingEngine) applyTornBatchRepr(repr []byte, sync bool) error {
+	r, err := NewRocksDBBatchReader(repr)
+	if err != nil {
+		return err
+	}
+	b := f.Engine.NewWriteOnlyBatch()
+	defer b.Close()
+	for i := 0; i < r.Count()/2 && r.Next(); i++ {
+		if r.BatchType() == BatchTypeLogData {
+			if err := b.LogData(r.Value()); err != nil {
+				return err
+			}
+			continue
+		}
+		key, err := r.MVCCKey()
+		if err != nil {
+			return err
+		}
+		switch r.BatchType() {
+		case BatchTypeValue:
+			err = b.Put(key, r.Value())
+		case BatchTypeDeletion:
+			err = b.Clear(key)
+		case BatchTypeSingleDeletion:
+			err = b.SingleClear(key)
+		case BatchTypeMerge:
+			err = b.Merge(key, r.Value())
+		case BatchTypeRangeDeletion:
+			var endKey MVCCKey
+			if endKey, err = r.MVCCEndKey(); err == nil {
+				err = b.ClearRange(key, endKey)
+			}
+		default:
+			err = errors.Errorf("unexpected batch entry type %d", r.BatchType())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := r.Error(); err != nil {
+		return err
+	}
+	return b.Commit(sync)
+}
+
+// commitRepr applies a batch representation to the wrapped engine, honoring
+// any fault scheduled for it.
+func (f *FaultInjectingEngine) commitRepr(repr []byte, sync bool, commit func() error) error {
+	fault := f.maybeInject(syncOp(sync))
+	if fault == nil {
+		return commit()
+	}
+	if fault.Torn {
+		if err := f.applyTornBatchRepr(repr, sync); err != nil {
+			return err
+		}
+	}
+	return fault.Err
+}
+
+// ApplyBatchRepr implements the Writer interface.
+func (f *FaultInjectingEngine) ApplyBatchRepr(repr []byte, sync bool) error {
+	return f.commitRepr(repr, sync, func() error {
+		return f.Engine.ApplyBatchRepr(repr, sync)
+	})
+}
+
+// Clear implements the Writer interface.
+func (f *FaultInjectingEngine) Clear(key MVCCKey) error {
+	return f.intercept(FaultOpWrite, func() error { return f.Engine.Clear(key) })
+}
+
+// SingleClear implements the Writer interface.
+func (f *FaultInjectingEngine) SingleClear(key MVCCKey) error {
+	return f.intercept(FaultOpWrite, func() error { return f.Engine.SingleClear(key) })
+}
+
+// ClearRange implements the Writer interface.
+func (f *FaultInjectingEngine) ClearRange(start, end MVCCKey) error {
+	return f.intercept(FaultOpWrite, func() error { return f.Engine.ClearRange(start, end) })
+}
+
+// ClearIterRange implements the Writer interface.
+func (f *FaultInjectingEngine) ClearIterRange(iter Iterator, start, end roachpb.Key) error {
+	return f.intercept(FaultOpWrite, func() error {
+		return f.Engine.ClearIterRange(iter, start, end)
+	})
+}
+
+// Merge implements the Writer interface.
+func (f *FaultInjectingEngine) Merge(key MVCCKey, value []byte) error {
+	return f.intercept(FaultOpWrite, func() error { return f.Engine.Merge(key, value) })
+}
+
+// Put implements the Writer interface.
+func (f *FaultInjectingEngine) Put(key MVCCKey, value []byte) error {
+	return f.intercept(FaultOpWrite, func() error { return f.Engine.Put(key, value) })
+}
+
+// LogData implements the Writer interface.
+func (f *FaultInjectingEngine) LogData(data []byte) error {
+	return f.intercept(FaultOpWrite, func() error { return f.Engine.LogData(data) })
+}
+
+// Flush implements the Engine interface.
+func (f *FaultInjectingEngine) Flush() error {
+	return f.intercept(FaultOpSync, f.Engine.Flush)
+}
+
+// WriteFile implements the Engine interface.
+func (f *FaultInjectingEngine) WriteFile(filename string, data []byte) error {
+	fault := f.maybeInject(FaultOpWriteFile)
+	if fault == nil {
+		return f.Engine.WriteFile(filename, data)
+	}
+	if fault.Torn {
+		if err := f.Engine.WriteFile(filename, data[:len(data)/2]); err != nil {
+			return err
+		}
+	}
+	return fault.Err
+}
+
+// NewBatch implements the Engine interface.
+func (f *FaultInjectingEngine) NewBatch() Batch {
+	return &faultInjectingBatch{Batch: f.Engine.NewBatch(), eng: f}
+}
+
+// NewWriteOnlyBatch implements the Engine interface.
+func (f *FaultInjectingEngine) NewWriteOnlyBatch() Batch {
+	return &faultInjectingBatch{Batch: f.Engine.NewWriteOnlyBatch(), eng: f}
+}
+
+// faultInjectingBatch wraps a batch of the underlying engine so that faults
+// are injected when it is committed. Writes to the batch itself are buffered
+// in memory and are never failed.
+type faultInjectingBatch struct {
+	Batch
+	eng *FaultInjectingEngine
+}
+
+// Commit implements the Batch interface.
+func (b *faultInjectingBatch) Commit(sync bool) error {
+	if b.Batch.Empty() {
+		return b.Batch.Commit(sync)
+	}
+	return b.eng.commitRepr(b.Batch.Repr(), sync, func() error {
+		return b.Batch.Commit(sync)
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectingEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := NewFaultInjectingEngine(engineImpl.create())
+			defer engine.Close()
+
+			key := func(i int) MVCCKey {
+				return MakeMVCCMetadataKey(roachpb.Key(fmt.Sprintf("key%d", i)))
+			}
+			injected := errors.New("injected")
+
+			// Fail every other direct write.
+			var calls int
+			engine.SetFaultFn(func(op FaultOp) *Fault {
+				calls++
+				if op == FaultOpWrite && calls%2 == 0 {
+					return &Fault{Err: injected}
+				}
+				return nil
+			})
+			require.NoError(t, engine.Put(key(0), []byte("a")))
+			require.Equal(t, injected, engine.Put(key(1), []byte("b")))
+			value, err := engine.Get(key(1))
+			require.NoError(t, err)
+			require.Nil(t, value)
+
+			// A torn synced commit applies only the first half of the batch.
+			engine.SetFaultFn(func(op FaultOp) *Fault {
+				if op == FaultOpSync {
+					return &Fault{Err: injected, Torn: true}
+				}
+				return nil
+			})
+			b := engine.NewBatch()
+			for i := 10; i < 14; i++ {
+				require.NoError(t, b.Put(key(i), []byte("c")))
+			}
+			require.Equal(t, injected, b.Commit(true /* sync */))
+			b.Close()
+			for i := 10; i < 14; i++ {
+				value, err := engine.Get(key(i))
+				require.NoError(t, err)
+				if i < 12 {
+					require.Equal(t, []byte("c"), value, "key %d", i)
+				} else {
+					require.Nil(t, value, "key %d", i)
+				}
+			}
+
+			// Commits which do not sync are unaffected by sync faults.
+			b = engine.NewBatch()
+			require.NoError(t, b.Put(key(20), []byte("d")))
+			require.NoError(t, b.Commit(false /* sync */))
+			b.Close()
+			value, err = engine.Get(key(20))
+			require.NoError(t, err)
+			require.Equal(t, []byte("d"), value)
+
+			// A torn WriteFile leaves a truncated file behind.
+			engine.SetFaultFn(func(op FaultOp) *Fault {
+				if op == FaultOpWriteFile {
+					return &Fault{Torn: true}
+				}
+				return nil
+			})
+			require.NoError(t, engine.WriteFile("torn", []byte("0123456789")))
+			data, err := engine.ReadFile("torn")
+			require.NoError(t, err)
+			require.Equal(t, []byte("01234"), data)
+
+			// With no schedule installed, everything goes through.
+			engine.SetFaultFn(nil)
+			require.NoError(t, engine.Put(key(1), []byte("b")))
+			require.NoError(t, engine.Flush())
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// CompactionGCEnabled controls whether the storage engine compactions drop
+// the MVCC versions which are shadowed below the GC threshold of their
+// range, ahead of the GC queue.
+var CompactionGCEnabled = settings.RegisterBoolSetting(
+	"kv.gc.compaction_filter.enabled",
+	"if set, storage engine compactions drop the MVCC versions which are no longer"+
+		" visible at or above the GC threshold, without waiting for the GC queue (experimental)",
+	false,
+)
+
+// CompactionGCActive returns whether the storage engine compactions may drop
+// the MVCC versions shadowed below the GC threshold. CompactionGCEnabled has
+// no effect until all of the nodes run a version which excludes them from
+// the replica checksums.
+func CompactionGCActive(ctx context.Context, st *cluster.Settings) bool {
+	return CompactionGCEnabled.Get(&st.SV) &&
+		st.Version.IsActive(ctx, clusterversion.VersionCompactionGCFilter)
+}
+
+// GCThreshold is the GC threshold of the user keys of a range, as of the
+// given generation of its descriptor.
+type GCThreshold struct {
+	RangeID    roachpb.RangeID
+	Generation int64
+	Span       roachpb.Span
+	Threshold  hlc.Timestamp
+}
+
+// CompactionGCer is implemented by the engines whose compactions can drop
+// the MVCC versions which are no longer visible at or above the GC threshold
+// of their key, without waiting for the GC queue to rewrite the data.
+//
+// A compaction drops a version when it also sees a newer committed version
+// of the key at or below the GC threshold. The compactions can't update the
+// MVCC stats of the range, and the GC queue no longer sees the versions once
+// they are dropped, so they are never subtracted from the stats: while
+// CompactionGCActive, the GC requests mark the stats as containing
+// estimates and the stats recomputations leave them marked, so that the
+// consistency checker corrects them rather than reporting them as
+// incorrect. The versions which may be dropped are identified by a
+// GCShadowTracker, and are excluded from the replica checksums requested
+// while the compactions may drop them.
+type CompactionGCer interface {
+	// SetCompactionGCThresholds replaces the GC thresholds used by the
+	// subsequent compactions. The spans must not overlap. Versions outside
+	// the spans are never dropped.
+	SetCompactionGCThresholds(thresholds []GCThreshold) error
+}
+
+// GCShadowTracker identifies, in a forward iteration over MVCC keys, the
+// versions which are shadowed by a newer committed version at or below the
+// GC threshold, and can thus be dropped by the compactions. Whether a version
+// is shadowed doesn't depend on the presence of the other shadowed versions,
+// so that it is the same on replicas on which compactions dropped different
+// subsets of them.
+type GCShadowTracker struct {
+	threshold hlc.Timestamp
+	key       roachpb.Key
+	intentTS  hlc.Timestamp
+	shadowed  bool
+}
+
+// MakeGCShadowTracker returns a GCShadowTracker for the given GC threshold.
+func MakeGCShadowTracker(threshold hlc.Timestamp) GCShadowTracker {
+	return GCShadowTracker{threshold: threshold}
+}
+
+// Shadowed returns whether the given key is a shadowed version. It must be
+// passed all of the keys of the iteration, in order.
+func (t *GCShadowTracker) Shadowed(key MVCCKey, value []byte) (bool, error) {
+	if !key.Key.Equal(t.key) {
+		t.key = append(t.key[:0], key.Key...)
+		t.intentTS = hlc.Timestamp{}
+		t.shadowed = false
+	}
+	if !key.IsValue() {
+		var meta enginepb.MVCCMetadata
+		if err := protoutil.Unmarshal(value, &meta); err != nil {
+			return false, err
+		}
+		if meta.Txn != nil {
+			t.intentTS = hlc.Timestamp(meta.Timestamp)
+		}
+		return false, nil
+	}
+	if t.shadowed {
+		return true, nil
+	}
+	// The provisional value of an intent can still be removed, and doesn't
+	// shadow the older versions.
+	if key.Timestamp.LessEq(t.threshold) && key.Timestamp != t.intentTS {
+		t.shadowed = true
+	}
+	return false, nil
+}
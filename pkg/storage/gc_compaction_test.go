@@ -0,0 +1,109 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGCCompactionTestData writes, with versions at timestamps 1 to 3:
+// - a: three committed versions.
+// - b: a committed version and the provisional value of an intent.
+// - c: three committed versions, outside of the GC threshold span.
+func writeGCCompactionTestData(t *testing.T, engine Engine) {
+	ctx := context.Background()
+	value := roachpb.MakeValueFromString("value")
+	for i := int64(1); i <= 3; i++ {
+		ts := hlc.Timestamp{WallTime: i}
+		require.NoError(t, MVCCPut(ctx, engine, nil, roachpb.Key("a"), ts, value, nil))
+		require.NoError(t, MVCCPut(ctx, engine, nil, roachpb.Key("c"), ts, value, nil))
+	}
+	require.NoError(t, MVCCPut(ctx, engine, nil, roachpb.Key("b"), hlc.Timestamp{WallTime: 1}, value, nil))
+	ts := hlc.Timestamp{WallTime: 2}
+	require.NoError(t, MVCCPut(ctx, engine, nil, roachpb.Key("b"), ts, value, makeTxn(*txn1, ts)))
+}
+
+// gcCompactionTestShadowed are the versions of the test data shadowed below
+// the GC threshold of the span [a, c).
+var gcCompactionTestShadowed = []MVCCKey{
+	{Key: roachpb.Key("a"), Timestamp: hlc.Timestamp{WallTime: 1}},
+}
+
+var gcCompactionTestThreshold = GCThreshold{
+	Span:      roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("c")},
+	Threshold: hlc.Timestamp{WallTime: 2},
+}
+
+func TestGCShadowTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	engine := createTestRocksDBEngine()
+	defer engine.Close()
+	writeGCCompactionTestData(t, engine)
+
+	var shadowed []MVCCKey
+	tracker := MakeGCShadowTracker(gcCompactionTestThreshold.Threshold)
+	iter := engine.NewIterator(IterOptions{UpperBound: roachpb.Key("c")})
+	defer iter.Close()
+	_, err := ComputeStatsGo(iter, roachpb.Key("a"), roachpb.Key("c"), 0, /* nowNanos */
+		func(key MVCCKey, value []byte) error {
+			ok, err := tracker.Shadowed(key, value)
+			if ok {
+				shadowed = append(shadowed, MVCCKey{Key: key.Key.Clone(), Timestamp: key.Timestamp})
+			}
+			return err
+		})
+	require.NoError(t, err)
+	require.Equal(t, gcCompactionTestShadowed, shadowed)
+}
+
+func TestRocksDBCompactionGC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	engine := newRocksDBInMem(roachpb.Attributes{}, 1<<20)
+	defer engine.Close()
+	writeGCCompactionTestData(t, engine)
+
+	keys := func() []MVCCKey {
+		var keys []MVCCKey
+		require.NoError(t, engine.Iterate(roachpb.KeyMin, roachpb.KeyMax, func(kv MVCCKeyValue) (bool, error) {
+			if kv.Key.IsValue() {
+				keys = append(keys, MVCCKey{Key: kv.Key.Key.Clone(), Timestamp: kv.Key.Timestamp})
+			}
+			return false, nil
+		}))
+		return keys
+	}
+	before := keys()
+	require.Len(t, before, 8)
+
+	// Without GC thresholds, compactions don't drop any version.
+	require.NoError(t, engine.Compact())
+	require.Equal(t, before, keys())
+
+	// The shadowed versions are dropped by the compactions once the GC
+	// threshold is set.
+	require.NoError(t, engine.SetCompactionGCThresholds([]GCThreshold{gcCompactionTestThreshold}))
+	require.NoError(t, engine.Compact())
+	var expected []MVCCKey
+	for _, key := range before {
+		if !key.Equal(gcCompactionTestShadowed[0]) {
+			expected = append(expected, key)
+		}
+	}
+	require.Equal(t, expected, keys())
+}
@@ -66,6 +66,13 @@ func appender(s string) []byte {
 	return mustMarshal(v)
 }
 
+func counter(i int64) []byte {
+	var val roachpb.Value
+	val.SetInt(i)
+	v := &enginepb.MVCCMetadataSubsetForMergeSerialization{RawBytes: val.RawBytes}
+	return mustMarshal(v)
+}
+
 // timeSeriesRow generates a simple InternalTimeSeriesData object which starts
 // at the given timestamp and has samples of the given duration. The time series
 // is written using the older sample-row data format. The object is stored in an
@@ -156,6 +163,14 @@ func TestGoMergeCorruption(t *testing.T) {
 				{1, 1, 5, 5, 5},
 			}...),
 		},
+		{counter(1), appender("a")},
+		{appender("a"), counter(1)},
+		{
+			counter(1),
+			timeSeriesRow(testtime, 1000, []tsSample{
+				{1, 1, 5, 5, 5},
+			}...),
+		},
 		{
 			timeSeriesRow(testtime, 1000, []tsSample{
 				{1, 1, 5, 5, 5},
@@ -17,6 +17,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -825,6 +826,59 @@ func mvccGet(
 	return
 }
 
+// MVCCMultiGet is like MVCCGet, but looks up several keys, which need not be
+// distinct, and returns their values in the order of the keys. The lookups
+// share a single iterator and are performed in key order, which amortizes
+// the cost of creating and positioning the iterator over the keys.
+//
+// In consistent mode, the intents encountered for all of the keys are
+// returned together in a single WriteIntentError. In inconsistent mode,
+// they're returned in the dedicated return parameter, in key order.
+func MVCCMultiGet(
+	ctx context.Context,
+	reader Reader,
+	keys []roachpb.Key,
+	timestamp hlc.Timestamp,
+	opts MVCCGetOptions,
+) ([]*roachpb.Value, []roachpb.Intent, error) {
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return keys[order[i]].Compare(keys[order[j]]) < 0
+	})
+
+	iter := reader.NewIterator(IterOptions{Prefix: true})
+	defer iter.Close()
+
+	values := make([]*roachpb.Value, len(keys))
+	var intents []roachpb.Intent
+	var wiErr *roachpb.WriteIntentError
+	for _, i := range order {
+		value, intent, err := mvccGet(ctx, iter, keys[i], timestamp, opts)
+		if err != nil {
+			if e, ok := err.(*roachpb.WriteIntentError); ok {
+				if wiErr == nil {
+					wiErr = e
+				} else {
+					wiErr.Intents = append(wiErr.Intents, e.Intents...)
+				}
+				continue
+			}
+			return nil, nil, err
+		}
+		values[i] = value
+		if intent != nil {
+			intents = append(intents, *intent)
+		}
+	}
+	if wiErr != nil {
+		return nil, nil, wiErr
+	}
+	return values, intents, nil
+}
+
 // MVCCGetAsTxn constructs a temporary transaction from the given transaction
 // metadata and calls MVCCGet as that transaction. This method is required
 // only for reading intents of a transaction when only its metadata is known
@@ -1869,11 +1923,17 @@ const (
 )
 
 // MVCCConditionalPut sets the value for a specified key only if the
-// expected value matches. If not, the return a ConditionFailedError
-// containing the actual value.
+// expected value matches. If not, it returns a ConditionFailedError
+// containing the actual value, or a nil ActualValue if the key doesn't
+// exist. A nil expVal expects the key not to exist. If allowIfDoesNotExist
+// is CPutAllowIfMissing, the put also succeeds when the key doesn't exist,
+// whatever expVal is.
 //
 // The condition check reads a value from the key using the same operational
-// timestamp as we use to write a value.
+// timestamp as we use to write a value. As it is evaluated under the
+// latches of the key, the comparison and the write are atomic with respect
+// to the other writes to the key, which makes it the building block of the
+// KV ConditionalPut command.
 //
 // Note that, when writing transactionally, the txn's timestamps
 // dictate the timestamp of the operation, and the timestamp paramater is
@@ -2262,10 +2322,17 @@ func MVCCClearTimeRange(
 }
 
 // MVCCDeleteRange deletes the range of key/value pairs specified by start and
-// end keys. It returns the range of keys deleted when returnedKeys is set,
+// end keys. It returns the range of keys deleted when returnKeys is set,
 // the next span to resume from, and the number of keys deleted.
 // The returned resume span is nil if max keys aren't processed.
 // The choice max=0 disables the limit.
+//
+// A deletion tombstone is written at the given timestamp for each key which
+// is live at that timestamp, so that the scans at or above it skip the keys
+// while the reads below it still see the older versions. The tombstones are
+// garbage collected along with the older versions once they fall below the
+// GC threshold. Spans which don't need to retain their history, such as those
+// of dropped tables, are instead cleared with ClearRange.
 func MVCCDeleteRange(
 	ctx context.Context,
 	rw ReadWriter,
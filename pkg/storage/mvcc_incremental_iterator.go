@@ -37,11 +37,12 @@ import (
 // CockroachDB uses that as a sentinel for key metadata anyway.
 //
 // Expected usage:
-//    iter := NewMVCCIncrementalIterator(e, IterOptions{
+//    iter, err := NewMVCCIncrementalIterator(e, IterOptions{
 //        StartTime:  startTime,
 //        EndTime:    endTime,
 //        UpperBound: endKey,
 //    })
+//    if err != nil { ... }
 //    defer iter.Close()
 //    for iter.SeekGE(startKey); ; iter.Next() {
 //        ok, err := iter.Valid()
@@ -102,17 +103,23 @@ type MVCCIncrementalIterOptions struct {
 }
 
 // NewMVCCIncrementalIterator creates an MVCCIncrementalIterator with the
-// specified reader and options. The timestamp hint range should not be more
-// restrictive than the start and end time range.
-// TODO(pbardea): Add validation here and in C++ implementation that the
-//  timestamp hints are not more restrictive than incremental iterator's
-//  (startTime, endTime] interval.
+// specified reader and options. An error is returned if the timestamp hint
+// range is more restrictive than the start and end time range, since the
+// time-bound iterator would otherwise skip SSTables containing keys that are
+// visible to the incremental iterator.
+// TODO(pbardea): Add the same validation to the C++ implementation.
 func NewMVCCIncrementalIterator(
 	reader Reader, opts MVCCIncrementalIterOptions,
-) *MVCCIncrementalIterator {
+) (*MVCCIncrementalIterator, error) {
 	var iter Iterator
 	var timeBoundIter Iterator
 	if !opts.IterOptions.MinTimestampHint.IsEmpty() && !opts.IterOptions.MaxTimestampHint.IsEmpty() {
+		if opts.StartTime.Next().Less(opts.IterOptions.MinTimestampHint) ||
+			opts.IterOptions.MaxTimestampHint.Less(opts.EndTime) {
+			return nil, errors.Errorf("timestamp hints [%s, %s] are more restrictive than (%s, %s]",
+				opts.IterOptions.MinTimestampHint, opts.IterOptions.MaxTimestampHint,
+				opts.StartTime, opts.EndTime)
+		}
 		// An iterator without the timestamp hints is created to ensure that the
 		// iterator visits every required version of every key that has changed.
 		iter = reader.NewIterator(IterOptions{
@@ -128,7 +135,7 @@ func NewMVCCIncrementalIterator(
 		startTime:     opts.StartTime,
 		endTime:       opts.EndTime,
 		timeBoundIter: timeBoundIter,
-	}
+	}, nil
 }
 
 // SeekGE advances the iterator to the first key in the engine which is >= the
@@ -42,13 +42,14 @@ func iterateExpectErr(
 ) func(*testing.T) {
 	return func(t *testing.T) {
 		t.Helper()
-		iter := NewMVCCIncrementalIterator(e, MVCCIncrementalIterOptions{
+		iter, err := NewMVCCIncrementalIterator(e, MVCCIncrementalIterOptions{
 			IterOptions: IterOptions{
 				UpperBound: endKey,
 			},
 			StartTime: startTime,
 			EndTime:   endTime,
 		})
+		require.NoError(t, err)
 		defer iter.Close()
 		var iterFn func()
 		if revisions {
@@ -116,11 +117,12 @@ func assertIteratedKVs(
 	io IterOptions,
 	expected []MVCCKeyValue,
 ) {
-	iter := NewMVCCIncrementalIterator(e, MVCCIncrementalIterOptions{
+	iter, err := NewMVCCIncrementalIterator(e, MVCCIncrementalIterOptions{
 		IterOptions: io,
 		StartTime:   startTime,
 		EndTime:     endTime,
 	})
+	require.NoError(t, err)
 	defer iter.Close()
 	var iterFn func()
 	if revisions {
@@ -400,13 +402,16 @@ func slurpKVsInTimeRange(
 	reader Reader, prefix roachpb.Key, startTime, endTime hlc.Timestamp,
 ) ([]MVCCKeyValue, error) {
 	endKey := prefix.PrefixEnd()
-	iter := NewMVCCIncrementalIterator(reader, MVCCIncrementalIterOptions{
+	iter, err := NewMVCCIncrementalIterator(reader, MVCCIncrementalIterOptions{
 		IterOptions: IterOptions{
 			UpperBound: endKey,
 		},
 		StartTime: startTime,
 		EndTime:   endTime,
 	})
+	if err != nil {
+		return nil, err
+	}
 	defer iter.Close()
 	var kvs []MVCCKeyValue
 	for iter.SeekGE(MakeMVCCMetadataKey(prefix)); ; iter.Next() {
@@ -706,11 +711,12 @@ func TestMVCCIncrementalIteratorIntentStraddlesSStables(t *testing.T) {
 		// 2]. Note that incremental iterators are exclusive on the start time and
 		// inclusive on the end time. The expectation is that we'll see a write
 		// intent error.
-		it := NewMVCCIncrementalIterator(db2, MVCCIncrementalIterOptions{
+		it, err := NewMVCCIncrementalIterator(db2, MVCCIncrementalIterOptions{
 			IterOptions: IterOptions{UpperBound: keys.MaxKey},
 			StartTime:   hlc.Timestamp{WallTime: 1},
 			EndTime:     hlc.Timestamp{WallTime: 2},
 		})
+		require.NoError(t, err)
 		defer it.Close()
 		for it.SeekGE(MVCCKey{Key: keys.MinKey}); ; it.Next() {
 			ok, err := it.Valid()
@@ -798,3 +804,36 @@ func TestMVCCIterateTimeBound(t *testing.T) {
 		})
 	}
 }
+
+func TestMVCCIncrementalIteratorRestrictiveHints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			e := engineImpl.create()
+			defer e.Close()
+
+			ts := func(w int64) hlc.Timestamp { return hlc.Timestamp{WallTime: w} }
+			newIter := func(minHint, maxHint hlc.Timestamp) error {
+				iter, err := NewMVCCIncrementalIterator(e, MVCCIncrementalIterOptions{
+					IterOptions: IterOptions{
+						UpperBound:       keys.MaxKey,
+						MinTimestampHint: minHint,
+						MaxTimestampHint: maxHint,
+					},
+					StartTime: ts(2),
+					EndTime:   ts(4),
+				})
+				if err != nil {
+					return err
+				}
+				iter.Close()
+				return nil
+			}
+			require.NoError(t, newIter(ts(2).Next(), ts(4)))
+			require.NoError(t, newIter(ts(1), ts(5)))
+			require.Error(t, newIter(ts(3), ts(4)))
+			require.Error(t, newIter(ts(2).Next(), ts(3)))
+		})
+	}
+}
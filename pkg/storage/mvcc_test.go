@@ -1161,6 +1161,66 @@ func TestMVCCGetInconsistent(t *testing.T) {
 	}
 }
 
+func TestMVCCMultiGet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1, ts2 := hlc.Timestamp{WallTime: 1}, hlc.Timestamp{WallTime: 2}
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, ts1, value1, nil))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, ts1, value2, nil))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey3, ts1, value3, nil))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey3, ts2, value4, nil))
+
+			// The values are returned in the order of the keys, which can be
+			// unsorted, repeated or missing.
+			keys := []roachpb.Key{testKey3, testKey1, testKey4, testKey3}
+			values, intents, err := MVCCMultiGet(ctx, engine, keys, ts2, MVCCGetOptions{})
+			require.NoError(t, err)
+			require.Empty(t, intents)
+			require.Len(t, values, len(keys))
+			require.Equal(t, value4.RawBytes, values[0].RawBytes)
+			require.Equal(t, value1.RawBytes, values[1].RawBytes)
+			require.Nil(t, values[2])
+			require.Equal(t, value4.RawBytes, values[3].RawBytes)
+
+			values, _, err = MVCCMultiGet(ctx, engine, keys, ts1, MVCCGetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, value3.RawBytes, values[0].RawBytes)
+
+			// The intents on all of the keys are reported together.
+			txn := makeTxn(*txn1, hlc.Timestamp{WallTime: 3})
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, txn.ReadTimestamp, value5, txn))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, txn.ReadTimestamp, value6, txn))
+			keys = []roachpb.Key{testKey2, testKey3, testKey1}
+			ts4 := hlc.Timestamp{WallTime: 4}
+			_, _, err = MVCCMultiGet(ctx, engine, keys, ts4, MVCCGetOptions{})
+			wiErr, ok := err.(*roachpb.WriteIntentError)
+			require.True(t, ok, "expected WriteIntentError, got %v", err)
+			require.Len(t, wiErr.Intents, 2)
+			require.Equal(t, testKey1, wiErr.Intents[0].Key)
+			require.Equal(t, testKey2, wiErr.Intents[1].Key)
+
+			values, intents, err = MVCCMultiGet(ctx, engine, keys, ts4, MVCCGetOptions{Inconsistent: true})
+			require.NoError(t, err)
+			require.Len(t, intents, 2)
+			require.Equal(t, value2.RawBytes, values[0].RawBytes)
+			require.Equal(t, value4.RawBytes, values[1].RawBytes)
+			require.Equal(t, value1.RawBytes, values[2].RawBytes)
+
+			// The transaction reads its own writes.
+			values, _, err = MVCCMultiGet(ctx, engine, keys, txn.ReadTimestamp, MVCCGetOptions{Txn: txn})
+			require.NoError(t, err)
+			require.Equal(t, value6.RawBytes, values[0].RawBytes)
+			require.Equal(t, value5.RawBytes, values[2].RawBytes)
+		})
+	}
+}
+
 // TestMVCCGetProtoInconsistent verifies the behavior of GetProto with
 // consistent set to false.
 func TestMVCCGetProtoInconsistent(t *testing.T) {
@@ -3094,6 +3154,57 @@ func TestMVCCReverseScanStopAtSmallestKey(t *testing.T) {
 	}
 }
 
+// TestMVCCIterateReverse verifies that a reverse MVCCIterate visits each
+// key exactly once, in decreasing order, across the batches in which it
+// scans the span.
+func TestMVCCIterateReverse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			// More keys than MVCCIterate scans at once, each with two versions.
+			const numKeys = 2500
+			for i := 0; i < numKeys; i++ {
+				key := roachpb.Key(fmt.Sprintf("key-%05d", i))
+				for ts := int64(1); ts <= 2; ts++ {
+					value := roachpb.MakeValueFromString(fmt.Sprintf("%d@%d", i, ts))
+					if err := MVCCPut(ctx, engine, nil, key, hlc.Timestamp{WallTime: ts}, value, nil); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			next := numKeys - 1
+			if _, err := MVCCIterate(
+				ctx, engine, keyMin, keyMax, hlc.Timestamp{WallTime: 1}, MVCCScanOptions{Reverse: true},
+				func(kv roachpb.KeyValue) (bool, error) {
+					if expected := roachpb.Key(fmt.Sprintf("key-%05d", next)); !kv.Key.Equal(expected) {
+						t.Fatalf("expected key %s, got %s", expected, kv.Key)
+					}
+					value, err := kv.Value.GetBytes()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if expected := fmt.Sprintf("%d@1", next); string(value) != expected {
+						t.Fatalf("expected value %s, got %s", expected, value)
+					}
+					next--
+					return false, nil
+				},
+			); err != nil {
+				t.Fatal(err)
+			}
+			if next != -1 {
+				t.Fatalf("iteration stopped before key %d", next)
+			}
+		})
+	}
+}
+
 func TestMVCCResolveTxn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"os"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -396,6 +397,12 @@ type Pebble struct {
 	// Relevant options copied over from pebble.Options.
 	fs     vfs.FS
 	logger pebble.Logger
+
+	walSync      base.WALSyncMode
+	periodicSync periodicWALSyncer
+
+	// writeStalls is incremented atomically by the event listener.
+	writeStalls *int64
 }
 
 var _ Engine = &Pebble{}
@@ -480,13 +487,20 @@ func NewPebble(ctx context.Context, cfg PebbleConfig) (*Pebble, error) {
 		ctx:   logCtx,
 		depth: 2, // skip over the EventListener stack frame
 	})
+	// Count the write stalls, which Pebble doesn't expose in its metrics.
+	writeStalls := new(int64)
+	logWriteStallBegin := cfg.Opts.EventListener.WriteStallBegin
+	cfg.Opts.EventListener.WriteStallBegin = func(info pebble.WriteStallBeginInfo) {
+		atomic.AddInt64(writeStalls, 1)
+		logWriteStallBegin(info)
+	}
 
 	db, err := pebble.Open(cfg.StorageConfig.Dir, cfg.Opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Pebble{
+	p := &Pebble{
 		db:           db,
 		path:         cfg.Dir,
 		auxDir:       auxDir,
@@ -497,7 +511,24 @@ func NewPebble(ctx context.Context, cfg PebbleConfig) (*Pebble, error) {
 		fileRegistry: fileRegistry,
 		fs:           cfg.Opts.FS,
 		logger:       cfg.Opts.Logger,
-	}, nil
+		walSync:      cfg.WALSync,
+		writeStalls:  writeStalls,
+	}
+	if cfg.WALSync == base.WALSyncPeriodic && !cfg.Opts.ReadOnly {
+		p.periodicSync.start(func() error {
+			return db.LogData(nil, pebble.Sync)
+		})
+	}
+	return p, nil
+}
+
+// pebbleWriteOpts returns the options of a write to a Pebble instance with
+// the given WAL sync mode. See syncWAL.
+func pebbleWriteOpts(sync bool, walSync base.WALSyncMode) *pebble.WriteOptions {
+	if syncWAL(sync, walSync) {
+		return pebble.Sync
+	}
+	return pebble.NoSync
 }
 
 func newTeeInMem(ctx context.Context, attrs roachpb.Attributes, cacheSize int64) *TeeEngine {
@@ -564,6 +595,7 @@ func (p *Pebble) Close() {
 		}
 	}
 
+	p.periodicSync.stop()
 	_ = p.db.Close()
 }
 
@@ -660,11 +692,7 @@ func (p *Pebble) ApplyBatchRepr(repr []byte, sync bool) error {
 		return err
 	}
 
-	opts := pebble.NoSync
-	if sync {
-		opts = pebble.Sync
-	}
-	return batch.Commit(opts)
+	return batch.Commit(pebbleWriteOpts(sync, p.walSync))
 }
 
 // Clear implements the Engine interface.
@@ -672,7 +700,7 @@ func (p *Pebble) Clear(key MVCCKey) error {
 	if len(key.Key) == 0 {
 		return emptyKeyError()
 	}
-	return p.db.Delete(EncodeKey(key), pebble.Sync)
+	return p.db.Delete(EncodeKey(key), pebbleWriteOpts(true, p.walSync))
 }
 
 // SingleClear implements the Engine interface.
@@ -680,14 +708,14 @@ func (p *Pebble) SingleClear(key MVCCKey) error {
 	if len(key.Key) == 0 {
 		return emptyKeyError()
 	}
-	return p.db.SingleDelete(EncodeKey(key), pebble.Sync)
+	return p.db.SingleDelete(EncodeKey(key), pebbleWriteOpts(true, p.walSync))
 }
 
 // ClearRange implements the Engine interface.
 func (p *Pebble) ClearRange(start, end MVCCKey) error {
 	bufStart := EncodeKey(start)
 	bufEnd := EncodeKey(end)
-	return p.db.DeleteRange(bufStart, bufEnd, pebble.Sync)
+	return p.db.DeleteRange(bufStart, bufEnd, pebbleWriteOpts(true, p.walSync))
 }
 
 // ClearIterRange implements the Engine interface.
@@ -707,7 +735,7 @@ func (p *Pebble) Merge(key MVCCKey, value []byte) error {
 	if len(key.Key) == 0 {
 		return emptyKeyError()
 	}
-	return p.db.Merge(EncodeKey(key), value, pebble.Sync)
+	return p.db.Merge(EncodeKey(key), value, pebbleWriteOpts(true, p.walSync))
 }
 
 // Put implements the Engine interface.
@@ -715,12 +743,12 @@ func (p *Pebble) Put(key MVCCKey, value []byte) error {
 	if len(key.Key) == 0 {
 		return emptyKeyError()
 	}
-	return p.db.Set(EncodeKey(key), value, pebble.Sync)
+	return p.db.Set(EncodeKey(key), value, pebbleWriteOpts(true, p.walSync))
 }
 
 // LogData implements the Engine interface.
 func (p *Pebble) LogData(data []byte) error {
-	return p.db.LogData(data, pebble.Sync)
+	return p.db.LogData(data, pebbleWriteOpts(true, p.walSync))
 }
 
 // LogLogicalOp implements the Engine interface.
@@ -772,6 +800,7 @@ func (p *Pebble) GetStats() (*Stats, error) {
 		TableReadersMemEstimate:        m.TableCache.Size,
 		PendingCompactionBytesEstimate: int64(m.Compact.EstimatedDebt),
 		L0FileCount:                    m.Levels[0].NumFiles,
+		WriteStalls:                    atomic.LoadInt64(p.writeStalls),
 	}, nil
 }
 
@@ -835,7 +864,7 @@ func (p *Pebble) GetAuxiliaryDir() string {
 
 // NewBatch implements the Engine interface.
 func (p *Pebble) NewBatch() Batch {
-	return newPebbleBatch(p.db, p.db.NewIndexedBatch())
+	return newPebbleBatch(p.db, p.db.NewIndexedBatch(), p.walSync)
 }
 
 // NewReadOnly implements the Engine interface.
@@ -847,7 +876,7 @@ func (p *Pebble) NewReadOnly() ReadWriter {
 
 // NewWriteOnlyBatch implements the Engine interface.
 func (p *Pebble) NewWriteOnlyBatch() Batch {
-	return newPebbleBatch(p.db, p.db.NewBatch())
+	return newPebbleBatch(p.db, p.db.NewBatch(), p.walSync)
 }
 
 // NewSnapshot implements the Engine interface.
@@ -1271,13 +1300,16 @@ func pebbleExportToSst(
 	defer sstWriter.Close()
 
 	var rows RowCounter
-	iter := NewMVCCIncrementalIterator(
+	iter, err := NewMVCCIncrementalIterator(
 		reader,
 		MVCCIncrementalIterOptions{
 			IterOptions: io,
 			StartTime:   startTS,
 			EndTime:     endTS,
 		})
+	if err != nil {
+		return nil, roachpb.BulkOpSummary{}, nil, err
+	}
 	defer iter.Close()
 	var curKey roachpb.Key // only used if exportAllRevisions
 	var resumeKey roachpb.Key
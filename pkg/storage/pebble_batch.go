@@ -13,6 +13,7 @@ package storage
 import (
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -30,6 +31,7 @@ type pebbleBatch struct {
 	isDistinct   bool
 	distinctOpen bool
 	parentBatch  *pebbleBatch
+	walSync      base.WALSyncMode
 }
 
 var _ Batch = &pebbleBatch{}
@@ -41,12 +43,13 @@ var pebbleBatchPool = sync.Pool{
 }
 
 // Instantiates a new pebbleBatch.
-func newPebbleBatch(db *pebble.DB, batch *pebble.Batch) *pebbleBatch {
+func newPebbleBatch(db *pebble.DB, batch *pebble.Batch, walSync base.WALSyncMode) *pebbleBatch {
 	pb := pebbleBatchPool.Get().(*pebbleBatch)
 	*pb = pebbleBatch{
-		db:    db,
-		batch: batch,
-		buf:   pb.buf,
+		db:      db,
+		batch:   batch,
+		buf:     pb.buf,
+		walSync: walSync,
 		prefixIter: pebbleIterator{
 			lowerBoundBuf: pb.prefixIter.lowerBoundBuf,
 			upperBoundBuf: pb.prefixIter.upperBoundBuf,
@@ -331,14 +334,10 @@ func (p *pebbleBatch) LogLogicalOp(op MVCCLogicalOpType, details MVCCLogicalOpDe
 
 // Commit implements the Batch interface.
 func (p *pebbleBatch) Commit(sync bool) error {
-	opts := pebble.NoSync
-	if sync {
-		opts = pebble.Sync
-	}
 	if p.batch == nil {
 		panic("called with nil batch")
 	}
-	err := p.batch.Commit(opts)
+	err := p.batch.Commit(pebbleWriteOpts(sync, p.walSync))
 	if err != nil {
 		panic(err)
 	}
@@ -361,7 +360,7 @@ func (p *pebbleBatch) Distinct() ReadWriter {
 	// optimization. In Pebble we're still using the same underlying batch and if
 	// it is indexed we'll still be indexing it as we Go.
 	p.distinctOpen = true
-	d := newPebbleBatch(p.db, p.batch)
+	d := newPebbleBatch(p.db, p.batch, p.walSync)
 	d.parentBatch = p
 	d.isDistinct = true
 	return d
@@ -11,6 +11,7 @@
 package storage
 
 import (
+	"encoding/binary"
 	"io"
 	"sort"
 
@@ -165,11 +166,15 @@ func ensureColumnar(ts *roachpb.InternalTimeSeriesData) {
 // to ensure operands are always appended. It merges these deserialized
 // operands when `Finish()` is called.
 //
-// It supports merging either all `roachpb.InternalTimeSeriesData` values
-// or all non-timeseries values. Attempting to merge a mixture of timeseries
-// and non-timeseries values will result in an error.
+// It supports merging either all `roachpb.InternalTimeSeriesData` values,
+// all integer values or all other non-timeseries values. Attempting to merge
+// a mixture of these will result in an error.
+//
+// Integer merges are only issued once all of the nodes sum the integer
+// operands (see clusterversion.VersionIntegerMerge).
 type MVCCValueMerger struct {
 	timeSeriesOps []roachpb.InternalTimeSeriesData
+	intOps        []int64
 	rawByteOps    [][]byte
 	oldestMergeTS hlc.LegacyTimestamp
 	oldToNew      bool
@@ -207,8 +212,9 @@ func (t *MVCCValueMerger) deserializeMVCCValueAndAppend(value []byte) error {
 	if len(t.meta.RawBytes) < mvccHeaderSize {
 		return errors.Errorf("operand value too short")
 	}
-	if t.meta.RawBytes[mvccTagPos] == byte(roachpb.ValueType_TIMESERIES) {
-		if t.rawByteOps != nil {
+	switch t.meta.RawBytes[mvccTagPos] {
+	case byte(roachpb.ValueType_TIMESERIES):
+		if t.rawByteOps != nil || t.intOps != nil {
 			return errors.Errorf("inconsistent value types for timeseries merge")
 		}
 		t.timeSeriesOps = append(t.timeSeriesOps, roachpb.InternalTimeSeriesData{})
@@ -216,8 +222,17 @@ func (t *MVCCValueMerger) deserializeMVCCValueAndAppend(value []byte) error {
 		if err := protoutil.Unmarshal(t.meta.RawBytes[mvccHeaderSize:], ts); err != nil {
 			return errors.Errorf("corrupted timeseries: %v", err)
 		}
-	} else {
-		if t.timeSeriesOps != nil {
+	case byte(roachpb.ValueType_INT):
+		if t.timeSeriesOps != nil || t.rawByteOps != nil {
+			return errors.Errorf("inconsistent value types for integer merge")
+		}
+		i, n := binary.Varint(t.meta.RawBytes[mvccHeaderSize:])
+		if n <= 0 {
+			return errors.Errorf("corrupted integer")
+		}
+		t.intOps = append(t.intOps, i)
+	default:
+		if t.timeSeriesOps != nil || t.intOps != nil {
 			return errors.Errorf("inconsistent value types for non-timeseries merge")
 		}
 		t.rawByteOps = append(t.rawByteOps, t.meta.RawBytes[mvccHeaderSize:])
@@ -253,15 +268,33 @@ func (t *MVCCValueMerger) MergeOlder(value []byte) error {
 }
 
 // Finish combines the buffered values from all `Merge*()` calls and marshals the result.
-// In case of non-timeseries the values are simply concatenated from old to new. In case
-// of timeseries the values are sorted, deduplicated, and potentially migrated to columnar
-// format. When deduplicating, only the latest sample for a given offset is retained.
+// In case of integers the values are summed. In case of other non-timeseries the values
+// are simply concatenated from old to new. In case of timeseries the values are sorted,
+// deduplicated, and potentially migrated to columnar format. When deduplicating, only the
+// latest sample for a given offset is retained.
 func (t *MVCCValueMerger) Finish() ([]byte, io.Closer, error) {
 	isColumnar := false
-	if t.timeSeriesOps == nil && t.rawByteOps == nil {
+	if t.timeSeriesOps == nil && t.intOps == nil && t.rawByteOps == nil {
 		return nil, nil, errors.Errorf("empty merge unsupported")
 	}
 	t.ensureOrder(true /* oldToNew */)
+	if t.intOps != nil {
+		// Overflow wraps around, consistently with the C++ DBMergeOperator.
+		var sum int64
+		for _, i := range t.intOps {
+			sum += i
+		}
+		// See the motivating comment in mvcc.proto.
+		var meta enginepb.MVCCMetadataSubsetForMergeSerialization
+		meta.RawBytes = make([]byte, mvccHeaderSize+binary.MaxVarintLen64)
+		meta.RawBytes[mvccTagPos] = byte(roachpb.ValueType_INT)
+		meta.RawBytes = meta.RawBytes[:mvccHeaderSize+binary.PutVarint(meta.RawBytes[mvccHeaderSize:], sum)]
+		res, err := protoutil.Marshal(&meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		return res, nil, nil
+	}
 	if t.timeSeriesOps == nil {
 		// Concatenate non-timeseries operands from old to new
 		totalLen := 0
@@ -269,6 +269,52 @@ func TestPebbleSeparatorSuccessor(t *testing.T) {
 
 }
 
+func TestPebbleComparerOrderingAndSplit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// The keys in increasing order: versions of a user key sort after its
+	// metadata key, newest first, and before the next user key, including
+	// the ones which have the user key as a prefix.
+	sorted := []MVCCKey{
+		makeMVCCKey(""),
+		makeMVCCKey("a"),
+		{Key: []byte("a"), Timestamp: hlc.Timestamp{WallTime: 2, Logical: 1}},
+		{Key: []byte("a"), Timestamp: hlc.Timestamp{WallTime: 2}},
+		{Key: []byte("a"), Timestamp: hlc.Timestamp{WallTime: 1}},
+		makeMVCCKey("a\x00"),
+		{Key: []byte("a\x00"), Timestamp: hlc.Timestamp{WallTime: 3}},
+		{Key: []byte("aa"), Timestamp: hlc.Timestamp{WallTime: 1}},
+		makeMVCCKey("b"),
+	}
+	for i := range sorted {
+		for j := range sorted {
+			a, b := EncodeKey(sorted[i]), EncodeKey(sorted[j])
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if c := MVCCComparer.Compare(a, b); c != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", sorted[i], sorted[j], c, want)
+			}
+			if i < j && MVCCComparer.AbbreviatedKey(a) > MVCCComparer.AbbreviatedKey(b) {
+				t.Errorf("AbbreviatedKey(%s) > AbbreviatedKey(%s)", sorted[i], sorted[j])
+			}
+		}
+	}
+
+	// The prefix of a key used by the bloom filters is the encoding of its
+	// metadata key, which is shared by all of the versions of a user key.
+	for _, k := range sorted {
+		enc := EncodeKey(k)
+		prefix := enc[:MVCCComparer.Split(enc)]
+		if want := EncodeKey(MakeMVCCMetadataKey(k.Key)); !bytes.Equal(prefix, want) {
+			t.Errorf("Split(%s) = %q, want %q", k, prefix, want)
+		}
+	}
+}
+
 func BenchmarkMVCCKeyCompare(b *testing.B) {
 	rng := rand.New(rand.NewSource(timeutil.Now().Unix()))
 	keys := make([][]byte, 1000)
@@ -459,6 +459,14 @@ func (c RocksDBCache) Release() {
 	}
 }
 
+// SetCapacity sets the capacity of the cache, which is shared by all of the
+// RocksDB engines it is attached to.
+func (c RocksDBCache) SetCapacity(size int64) {
+	if c.cache != nil {
+		C.DBSetCacheCapacity(c.cache, C.uint64_t(size))
+	}
+}
+
 // RocksDBConfig holds all configuration parameters and knobs used in setting
 // up a new RocksDB instance.
 type RocksDBConfig struct {
@@ -476,6 +484,9 @@ type RocksDBConfig struct {
 	// RocksDBOptions contains RocksDB specific options using a semicolon
 	// separated key-value syntax ("key1=value1; key2=value2").
 	RocksDBOptions string
+	// Tuning overrides the default values of the commonly tuned RocksDB
+	// parameters.
+	Tuning base.RocksDBTuning
 }
 
 // RocksDB is a wrapper around a RocksDB database instance.
@@ -500,6 +511,7 @@ type RocksDB struct {
 		closed  bool
 		pending []*rocksDBBatch
 	}
+	periodicSync periodicWALSyncer
 
 	iters struct {
 		syncutil.Mutex
@@ -525,6 +537,11 @@ func NewRocksDB(cfg RocksDBConfig, cache RocksDBCache) (*RocksDB, error) {
 	if cfg.Dir == "" {
 		return nil, errors.New("dir must be non-empty")
 	}
+	if cfg.Tuning.BlockCacheSize > 0 {
+		// The store uses a cache of its own instead of the shared one.
+		cache = NewRocksDBCache(cfg.Tuning.BlockCacheSize)
+		defer cache.Release()
+	}
 
 	r := &RocksDB{
 		cfg:   cfg,
@@ -644,16 +661,32 @@ func (r *RocksDB) open() error {
 		maxOpenFiles = r.cfg.MaxOpenFiles
 	}
 
+	var compression C.int
+	switch r.cfg.Tuning.Compression {
+	case "":
+		compression = C.DB_COMPRESSION_DEFAULT
+	case "none":
+		compression = C.DB_COMPRESSION_NONE
+	case "snappy":
+		compression = C.DB_COMPRESSION_SNAPPY
+	default:
+		return errors.Errorf("unknown compression type %q", r.cfg.Tuning.Compression)
+	}
+
 	status := C.DBOpen(&r.rdb, goToCSlice([]byte(r.cfg.Dir)),
 		C.DBOptions{
-			cache:             r.cache.cache,
-			num_cpu:           C.int(rocksdbConcurrency),
-			max_open_files:    C.int(maxOpenFiles),
-			use_file_registry: C.bool(newVersion == versionCurrent),
-			must_exist:        C.bool(r.cfg.MustExist),
-			read_only:         C.bool(r.cfg.ReadOnly),
-			rocksdb_options:   goToCSlice([]byte(r.cfg.RocksDBOptions)),
-			extra_options:     goToCSlice(r.cfg.ExtraOptions),
+			cache:                   r.cache.cache,
+			num_cpu:                 C.int(rocksdbConcurrency),
+			max_open_files:          C.int(maxOpenFiles),
+			write_buffer_size:       C.uint64_t(r.cfg.Tuning.WriteBufferSize),
+			max_write_buffer_number: C.int(r.cfg.Tuning.MaxWriteBufferNumber),
+			bloom_bits_per_key:      C.int(r.cfg.Tuning.BloomFilterBitsPerKey),
+			compression:             compression,
+			use_file_registry:       C.bool(newVersion == versionCurrent),
+			must_exist:              C.bool(r.cfg.MustExist),
+			read_only:               C.bool(r.cfg.ReadOnly),
+			rocksdb_options:         goToCSlice([]byte(r.cfg.RocksDBOptions)),
+			extra_options:           goToCSlice(r.cfg.ExtraOptions),
 		})
 	if err := statusToError(status); err != nil {
 		return errors.Wrap(err, "could not open rocksdb instance")
@@ -673,9 +706,27 @@ func (r *RocksDB) open() error {
 	// NB: The sync goroutine acts as a check that the RocksDB instance was
 	// properly closed as the goroutine will leak otherwise.
 	go r.syncLoop()
+	if r.cfg.WALSync == base.WALSyncPeriodic && r.cfg.Dir != "" && !r.cfg.ReadOnly {
+		r.periodicSync.start(r.syncWAL)
+	}
 	return nil
 }
 
+// syncWAL syncs the WAL through the sync goroutine, which serializes it
+// with the syncs requested by commits.
+func (r *RocksDB) syncWAL() error {
+	// The batch is only used to wait for the sync.
+	b := &rocksDBBatch{}
+	b.commitWG.Add(1)
+	s := &r.syncer
+	s.Lock()
+	s.pending = append(s.pending, b)
+	s.cond.Signal()
+	s.Unlock()
+	b.commitWG.Wait()
+	return b.commitErr
+}
+
 func (r *RocksDB) syncLoop() {
 	s := &r.syncer
 	s.Lock()
@@ -745,6 +796,7 @@ func (r *RocksDB) Close() {
 	} else {
 		log.Infof(context.TODO(), "closing rocksdb instance at %q", r.cfg.Dir)
 	}
+	r.periodicSync.stop()
 	if r.rdb != nil {
 		if err := statusToError(C.DBClose(r.rdb)); err != nil {
 			if debugIteratorLeak {
@@ -864,7 +916,7 @@ func (r *RocksDB) LogLogicalOp(op MVCCLogicalOpType, details MVCCLogicalOpDetail
 // It is safe to modify the contents of the arguments after ApplyBatchRepr
 // returns.
 func (r *RocksDB) ApplyBatchRepr(repr []byte, sync bool) error {
-	return dbApplyBatchRepr(r.rdb, repr, sync)
+	return dbApplyBatchRepr(r.rdb, repr, syncWAL(sync, r.cfg.WALSync))
 }
 
 // Get returns the value for the given key.
@@ -931,6 +983,48 @@ func (r *RocksDB) CompactRange(start, end roachpb.Key, forceBottommost bool) err
 	return statusToError(C.DBCompactRange(r.rdb, goToCSlice(start), goToCSlice(end), C.bool(forceBottommost)))
 }
 
+var _ CacheResizer = &RocksDB{}
+
+// SetSharedCacheCapacity implements the CacheResizer interface.
+func (r *RocksDB) SetSharedCacheCapacity(size int64) error {
+	if r.cfg.Dir == "" {
+		return errors.New("in-memory stores use a block cache of their own, sized by the store")
+	}
+	if r.cfg.Tuning.BlockCacheSize > 0 {
+		return errors.Errorf("store %s uses a block cache of its own, whose size is set by its "+
+			"rocksdb tuning options", r.cfg.Dir)
+	}
+	r.cache.SetCapacity(size)
+	return nil
+}
+
+var _ CompactionGCer = &RocksDB{}
+
+// SetCompactionGCThresholds implements the CompactionGCer interface.
+func (r *RocksDB) SetCompactionGCThresholds(thresholds []GCThreshold) error {
+	// The keys are copied to C memory, as the thresholds passed to C can't
+	// contain Go pointers.
+	cThresholds := make([]C.DBGCThreshold, len(thresholds))
+	for i, t := range thresholds {
+		cThresholds[i] = C.DBGCThreshold{
+			start:     cBytesToCSlice(t.Span.Key),
+			end:       cBytesToCSlice(t.Span.EndKey),
+			threshold: goToCTimestamp(t.Threshold),
+		}
+	}
+	defer func() {
+		for i := range cThresholds {
+			C.free(unsafe.Pointer(cThresholds[i].start.data))
+			C.free(unsafe.Pointer(cThresholds[i].end.data))
+		}
+	}()
+	var ptr *C.DBGCThreshold
+	if len(cThresholds) > 0 {
+		ptr = &cThresholds[0]
+	}
+	return statusToError(C.DBSetGCThresholds(r.rdb, ptr, C.size_t(len(cThresholds))))
+}
+
 // disableAutoCompaction disables automatic compactions. For testing use only.
 func (r *RocksDB) disableAutoCompaction() error {
 	return statusToError(C.DBDisableAutoCompaction(r.rdb))
@@ -1228,6 +1322,7 @@ func (r *RocksDB) GetStats() (*Stats, error) {
 		TableReadersMemEstimate:        int64(s.table_readers_mem_estimate),
 		PendingCompactionBytesEstimate: int64(s.pending_compaction_bytes_estimate),
 		L0FileCount:                    int64(s.l0_file_count),
+		WriteStalls:                    int64(s.write_stalls),
 	}, nil
 }
 
@@ -1967,7 +2062,9 @@ func (r *rocksDBBatch) Commit(syncCommit bool) error {
 	// 30 concurrent commits.
 	c := &r.parent.commit
 	r.commitWG.Add(1)
-	r.syncCommit = syncCommit
+	// Only the WALSyncAlways mode makes a commit wait for the WAL to be
+	// synced.
+	r.syncCommit = syncWAL(syncCommit, r.parent.cfg.WALSync)
 
 	// The leader for the commit is the first batch to be added to the pending
 	// slice. Every batch has an associated wait group which is signaled when
@@ -2657,6 +2754,14 @@ func goToCIgnoredSeqNums(b []enginepb.IgnoredSeqNumRange) C.DBIgnoredSeqNums {
 	}
 }
 
+// cBytesToCSlice copies b to C memory, which must be freed by the caller.
+func cBytesToCSlice(b []byte) C.DBSlice {
+	return C.DBSlice{
+		data: (*C.char)(C.CBytes(b)),
+		len:  C.size_t(len(b)),
+	}
+}
+
 func goToCKey(key MVCCKey) C.DBKey {
 	return C.DBKey{
 		key:       goToCSlice(key.Key),
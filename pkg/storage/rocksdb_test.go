@@ -1671,3 +1671,42 @@ func TestRocksDBGlobalSeqnumIssue(t *testing.T) {
 		t.Fatalf("expected values to match: %v != %v != 'barfoo'", val, val2)
 	}
 }
+
+func TestRocksDBSetSharedCacheCapacity(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	cache := NewRocksDBCache(1 << 20)
+	defer cache.Release()
+	open := func(dir string, tuning base.RocksDBTuning) *RocksDB {
+		e, err := NewRocksDB(
+			RocksDBConfig{
+				StorageConfig: base.StorageConfig{
+					Settings: cluster.MakeTestingClusterSettings(),
+					Dir:      dir,
+				},
+				Tuning: tuning,
+			},
+			cache,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	shared := open(filepath.Join(dir, "shared"), base.RocksDBTuning{})
+	defer shared.Close()
+	if err := shared.SetSharedCacheCapacity(2 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	// A store with a block cache of its own doesn't follow the shared cache.
+	own := open(filepath.Join(dir, "own"), base.RocksDBTuning{BlockCacheSize: 1 << 20})
+	defer own.Close()
+	if err := own.SetSharedCacheCapacity(2 << 20); !testutils.IsError(err, "uses a block cache of its own") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/pebble/sstable"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/pkg/errors"
+)
+
+// VerifySSTSpan returns an error if the point keys and range deletion
+// tombstones of the given SSTable are not all within [start, end).
+func VerifySSTSpan(data []byte, start, end roachpb.Key) error {
+	if err := verifySSTRangeDelSpan(data, start, end); err != nil {
+		return err
+	}
+	iter, err := NewMemSSTIterator(data, false /* verify */)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	iter.SeekGE(MVCCKey{Key: keys.MinKey})
+	if ok, err := iter.Valid(); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+	if key := iter.UnsafeKey().Key; key.Compare(start) < 0 {
+		return errors.Errorf("first key %s not in span [%s,%s)", key, start, end)
+	}
+	iter.SeekGE(MakeMVCCMetadataKey(end))
+	if ok, err := iter.Valid(); err != nil {
+		return err
+	} else if ok {
+		return errors.Errorf("last key %s not in span [%s,%s)", iter.UnsafeKey().Key, start, end)
+	}
+	return nil
+}
+
+// verifySSTRangeDelSpan returns an error if the range deletion tombstones of
+// the given SSTable don't all cover a subset of [start, end).
+func verifySSTRangeDelSpan(data []byte, start, end roachpb.Key) error {
+	sst, err := sstable.NewReader(vfs.NewMemFile(data), sstable.ReaderOptions{
+		Comparer: MVCCComparer,
+	})
+	if err != nil {
+		return err
+	}
+	defer sst.Close()
+	iter, err := sst.NewRangeDelIter()
+	if err != nil {
+		return err
+	}
+	if iter == nil {
+		// The SSTable has no range deletion tombstones.
+		return nil
+	}
+	defer iter.Close()
+
+	startKey := EncodeKey(MakeMVCCMetadataKey(start))
+	endKey := EncodeKey(MakeMVCCMetadataKey(end))
+	for key, value := iter.First(); key != nil; key, value = iter.Next() {
+		// The value of a range deletion tombstone is its exclusive end key.
+		if MVCCComparer.Compare(key.UserKey, startKey) < 0 ||
+			MVCCComparer.Compare(value, endKey) > 0 {
+			tombStart, err := DecodeMVCCKey(key.UserKey)
+			if err != nil {
+				return err
+			}
+			tombEnd, err := DecodeMVCCKey(value)
+			if err != nil {
+				return err
+			}
+			return errors.Errorf("range deletion [%s,%s) not in span [%s,%s)",
+				tombStart.Key, tombEnd.Key, start, end)
+		}
+	}
+	return iter.Error()
+}
+
+// IngestExternalFilesInSpan verifies that the keys of each of the SSTables
+// at the given paths, read through the engine's file system, are within
+// [start, end), and then atomically ingests them into the engine. Ingested
+// files bypass the write-ahead log and the memtable and are linked into the
+// LSM as is, so this guards bulk loads built outside of the engine against
+// overwriting data outside of the span they are meant for. No file is
+// ingested if any of them fails the check.
+func IngestExternalFilesInSpan(
+	ctx context.Context, eng Engine, paths []string, start, end roachpb.Key,
+) error {
+	for _, path := range paths {
+		data, err := eng.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := VerifySSTSpan(data, start, end); err != nil {
+			return errors.Wrapf(err, "cannot ingest %s", path)
+		}
+	}
+	return eng.IngestExternalFiles(ctx, paths)
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestSST(t *testing.T, keys ...string) []byte {
+	sstFile := &MemFile{}
+	sst := MakeIngestionSSTWriter(sstFile)
+	defer sst.Close()
+	for _, k := range keys {
+		key := MVCCKey{Key: roachpb.Key(k), Timestamp: hlc.Timestamp{WallTime: 1}}
+		require.NoError(t, sst.Put(key, []byte("value")))
+	}
+	require.NoError(t, sst.Finish())
+	return sstFile.Data()
+}
+
+func TestVerifySSTSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	start, end := roachpb.Key("b"), roachpb.Key("d")
+	testCases := []struct {
+		keys    []string
+		success bool
+	}{
+		{nil, true},
+		{[]string{"b"}, true},
+		{[]string{"b", "c", "c\x00"}, true},
+		{[]string{"a", "c"}, false},
+		{[]string{"c", "d"}, false},
+		{[]string{"e"}, false},
+	}
+	for _, tc := range testCases {
+		err := VerifySSTSpan(makeTestSST(t, tc.keys...), start, end)
+		if tc.success {
+			require.NoError(t, err, "%v", tc.keys)
+		} else {
+			require.Error(t, err, "%v", tc.keys)
+		}
+	}
+}
+
+func makeTestRangeDelSST(t *testing.T, start, end string) []byte {
+	sstFile := &MemFile{}
+	sst := MakeIngestionSSTWriter(sstFile)
+	defer sst.Close()
+	require.NoError(t, sst.ClearRange(MakeMVCCMetadataKey(roachpb.Key(start)),
+		MakeMVCCMetadataKey(roachpb.Key(end))))
+	require.NoError(t, sst.Finish())
+	return sstFile.Data()
+}
+
+func TestVerifySSTSpanRangeDel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	start, end := roachpb.Key("b"), roachpb.Key("d")
+	testCases := []struct {
+		start, end string
+		success    bool
+	}{
+		{"b", "d", true},
+		{"b", "c", true},
+		{"c", "d", true},
+		{"a", "c", false},
+		{"c", "e", false},
+		{"a", "e", false},
+	}
+	for _, tc := range testCases {
+		err := VerifySSTSpan(makeTestRangeDelSST(t, tc.start, tc.end), start, end)
+		if tc.success {
+			require.NoError(t, err, "[%s,%s)", tc.start, tc.end)
+		} else {
+			require.Error(t, err, "[%s,%s)", tc.start, tc.end)
+		}
+	}
+}
+
+func TestIngestExternalFilesInSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			start, end := roachpb.Key("b"), roachpb.Key("d")
+			require.NoError(t, engine.WriteFile("in", makeTestSST(t, "b", "c")))
+			require.NoError(t, engine.WriteFile("out", makeTestSST(t, "c", "e")))
+
+			// If any of the files has keys outside of the span, none of them are
+			// ingested.
+			require.Error(t, IngestExternalFilesInSpan(ctx, engine, []string{"in", "out"}, start, end))
+			value, err := engine.Get(MVCCKey{Key: roachpb.Key("b"), Timestamp: hlc.Timestamp{WallTime: 1}})
+			require.NoError(t, err)
+			require.Nil(t, value)
+
+			require.NoError(t, IngestExternalFilesInSpan(ctx, engine, []string{"in"}, start, end))
+			value, err = engine.Get(MVCCKey{Key: roachpb.Key("b"), Timestamp: hlc.Timestamp{WallTime: 1}})
+			require.NoError(t, err)
+			require.Equal(t, []byte("value"), value)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// periodicWALSyncInterval is the interval at which the WAL of an engine is
+// synced in the base.WALSyncPeriodic mode.
+var periodicWALSyncInterval = envutil.EnvOrDefaultDuration(
+	"COCKROACH_PERIODIC_WAL_SYNC_INTERVAL", time.Second)
+
+// testingSyncWALHook, if set, is called with the result of every syncWAL
+// call. Used by tests to check that the commit paths honor the WAL sync
+// mode.
+var testingSyncWALHook func(synced bool)
+
+// syncWAL returns whether a write which requested to be synced waits for
+// the WAL to be synced in the given WAL sync mode. Only WALSyncAlways
+// honors requests to sync. Every commit path must go through it.
+func syncWAL(sync bool, mode base.WALSyncMode) bool {
+	synced := sync && mode == base.WALSyncAlways
+	if fn := testingSyncWALHook; fn != nil {
+		fn(synced)
+	}
+	return synced
+}
+
+// periodicWALSyncer syncs the WAL of an engine in the background at
+// periodicWALSyncInterval.
+type periodicWALSyncer struct {
+	stopC chan struct{}
+	doneC chan struct{}
+}
+
+// start starts a goroutine which calls sync at every interval. The goroutine
+// exits after the first failure, since the WAL must not be synced again
+// after a failed sync (see RocksDB.syncLoop).
+func (s *periodicWALSyncer) start(sync func() error) {
+	s.stopC = make(chan struct{})
+	s.doneC = make(chan struct{})
+	go func() {
+		defer close(s.doneC)
+		ticker := time.NewTicker(periodicWALSyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := sync(); err != nil {
+					log.Errorf(context.TODO(), "periodic WAL sync failed: %v", err)
+					return
+				}
+			case <-s.stopC:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops the goroutine, if it was started, and waits for an in-flight
+// sync to complete.
+func (s *periodicWALSyncer) stop() {
+	if s.stopC == nil {
+		return
+	}
+	close(s.stopC)
+	<-s.doneC
+	s.stopC = nil
+}
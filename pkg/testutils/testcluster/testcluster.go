@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
 
@@ -48,9 +49,20 @@ type TestCluster struct {
 	stopper         *stop.Stopper
 	replicationMode base.TestClusterReplicationMode
 	scratchRangeID  roachpb.RangeID
-	mu              struct {
+	restartable     bool
+	manualClocks    bool
+	// faults is nil unless network fault injection was requested.
+	faults *rpc.FaultInjector
+	mu     struct {
 		syncutil.Mutex
 		serverStoppers []*stop.Stopper
+		// serverArgs are the arguments each server was started with, used
+		// to restart it.
+		serverArgs []base.TestServerArgs
+		clocks     []*hlc.ManualClock
+		// stickyEngineIDs are the sticky in-memory engines of the stores of
+		// restartable servers, closed when the cluster stops.
+		stickyEngineIDs []string
 	}
 }
 
@@ -141,8 +153,18 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 	tc := &TestCluster{
 		stopper:         stop.NewStopper(),
 		replicationMode: args.ReplicationMode,
+		restartable:     args.RestartableServers,
+		manualClocks:    args.ManualClocks,
 	}
 	tc.stopper = stop.NewStopper()
+	if args.InjectNetworkFaults {
+		seed := args.NetworkFaultSeed
+		if seed == 0 {
+			seed = timeutil.Now().UnixNano()
+		}
+		log.Infof(context.Background(), "network fault injection seed: %d", seed)
+		tc.faults = rpc.NewFaultInjector(seed)
+	}
 
 	// Check if any of the args have a locality set.
 	noLocalities := true
@@ -261,6 +283,7 @@ func StartTestCluster(t testing.TB, nodes int, args base.TestClusterArgs) *TestC
 	// Create a closer that will stop the individual server stoppers when the
 	// cluster stopper is stopped.
 	tc.stopper.AddCloser(stop.CloserFn(tc.stopServers))
+	tc.stopper.AddCloser(stop.CloserFn(tc.closeStickyEngines))
 
 	if tc.replicationMode == base.ReplicationAuto {
 		if err := tc.WaitForFullReplication(); err != nil {
@@ -335,7 +358,6 @@ func (tc *TestCluster) doAddServer(t testing.TB, serverArgs base.TestServerArgs)
 	); err != nil {
 		return err
 	}
-	serverArgs.Stopper = stop.NewStopper()
 	if tc.replicationMode == base.ReplicationManual {
 		var stkCopy kvserver.StoreTestingKnobs
 		if stk := serverArgs.Knobs.Store; stk != nil {
@@ -347,6 +369,42 @@ func (tc *TestCluster) doAddServer(t testing.TB, serverArgs base.TestServerArgs)
 		serverArgs.Knobs.Store = &stkCopy
 	}
 
+	if tc.faults != nil {
+		// The fault injector identifies servers by their RPC address, so it
+		// must be known before the server starts.
+		knobs := copyServerKnobs(&serverArgs)
+		if knobs.RPCListener == nil {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return err
+			}
+			knobs.RPCListener = ln
+			serverArgs.Addr = ln.Addr().String()
+		}
+		knobs.ContextTestingKnobs = tc.faults.TestingKnobs(serverArgs.Addr)
+	}
+	var clock *hlc.ManualClock
+	if tc.manualClocks {
+		clock = hlc.NewManualClock(timeutil.Now().UnixNano())
+		copyServerKnobs(&serverArgs).ClockSource = clock.UnixNano
+	}
+	if tc.restartable {
+		if len(serverArgs.StoreSpecs) == 0 {
+			serverArgs.StoreSpecs = []base.StoreSpec{base.DefaultTestStoreSpec}
+		}
+		specs := append([]base.StoreSpec(nil), serverArgs.StoreSpecs...)
+		for i := range specs {
+			if specs[i].InMemory && specs[i].StickyInMemoryEngineID == "" {
+				specs[i].StickyInMemoryEngineID = uuid.MakeV4().String()
+				tc.mu.Lock()
+				tc.mu.stickyEngineIDs = append(tc.mu.stickyEngineIDs, specs[i].StickyInMemoryEngineID)
+				tc.mu.Unlock()
+			}
+		}
+		serverArgs.StoreSpecs = specs
+	}
+
+	serverArgs.Stopper = stop.NewStopper()
 	s, conn, _ := serverutils.StartServer(t, serverArgs)
 
 	tc.mu.Lock()
@@ -355,9 +413,120 @@ func (tc *TestCluster) doAddServer(t testing.TB, serverArgs base.TestServerArgs)
 	tc.Servers = append(tc.Servers, s.(*server.TestServer))
 	tc.Conns = append(tc.Conns, conn)
 	tc.mu.serverStoppers = append(tc.mu.serverStoppers, serverArgs.Stopper)
+	tc.mu.serverArgs = append(tc.mu.serverArgs, serverArgs)
+	tc.mu.clocks = append(tc.mu.clocks, clock)
 	return nil
 }
 
+// copyServerKnobs replaces the server testing knobs of the args with a copy,
+// so that they can be changed for one server only, and returns it.
+func copyServerKnobs(args *base.TestServerArgs) *server.TestingKnobs {
+	knobs := &server.TestingKnobs{}
+	if args.Knobs.Server != nil {
+		*knobs = *args.Knobs.Server.(*server.TestingKnobs)
+	}
+	args.Knobs.Server = knobs
+	return knobs
+}
+
+// RestartServer stops a server, if it is running, and starts it again with
+// the same arguments and stores. The cluster must have been started with
+// RestartableServers.
+func (tc *TestCluster) RestartServer(t testing.TB, idx int) {
+	if !tc.restartable {
+		t.Fatal("cannot restart servers unless the cluster is started with RestartableServers")
+	}
+	tc.StopServer(idx)
+
+	tc.mu.Lock()
+	serverArgs := tc.mu.serverArgs[idx]
+	if serverArgs.JoinAddr == "" {
+		for i, s := range tc.mu.serverStoppers {
+			if s != nil && i != idx {
+				serverArgs.JoinAddr = tc.Servers[i].ServingRPCAddr()
+				break
+			}
+		}
+	}
+	tc.mu.Unlock()
+
+	// Keep the RPC address of the server, which the other servers and the
+	// fault injector know it by. A pre-bound listener was closed with the
+	// server, so bind a new one.
+	addr := tc.Servers[idx].ServingRPCAddr()
+	if knobs, ok := serverArgs.Knobs.Server.(*server.TestingKnobs); ok && knobs.RPCListener != nil {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copyServerKnobs(&serverArgs).RPCListener = ln
+	}
+	serverArgs.Addr = addr
+
+	serverArgs.Stopper = stop.NewStopper()
+	s, conn, _ := serverutils.StartServer(t, serverArgs)
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.Servers[idx] = s.(*server.TestServer)
+	tc.Conns[idx] = conn
+	tc.mu.serverStoppers[idx] = serverArgs.Stopper
+}
+
+// closeStickyEngines frees the stores of restartable servers once all the
+// servers have stopped.
+func (tc *TestCluster) closeStickyEngines() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	for _, id := range tc.mu.stickyEngineIDs {
+		if err := server.CloseStickyInMemEngine(id); err != nil {
+			log.Warningf(context.Background(), "%v", err)
+		}
+	}
+	tc.mu.stickyEngineIDs = nil
+}
+
+// ManualClock returns the clock driving the HLC of a server. The cluster
+// must have been started with ManualClocks.
+func (tc *TestCluster) ManualClock(idx int) *hlc.ManualClock {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.clocks[idx]
+}
+
+// FaultInjector returns the injector of network faults between the servers,
+// or nil unless the cluster was started with InjectNetworkFaults.
+func (tc *TestCluster) FaultInjector() *rpc.FaultInjector {
+	return tc.faults
+}
+
+// Partition cuts the network between the given servers and all the others,
+// until HealPartitions is called. The cluster must have been started with
+// InjectNetworkFaults.
+func (tc *TestCluster) Partition(t testing.TB, idxs ...int) {
+	if tc.faults == nil {
+		t.Fatal("cannot partition servers unless the cluster is started with InjectNetworkFaults")
+	}
+	inGroup := make(map[int]bool, len(idxs))
+	for _, idx := range idxs {
+		inGroup[idx] = true
+	}
+	for i := range tc.Servers {
+		for j := range tc.Servers {
+			if inGroup[i] && !inGroup[j] {
+				tc.faults.Partition(tc.Servers[i].ServingRPCAddr(), tc.Servers[j].ServingRPCAddr())
+			}
+		}
+	}
+}
+
+// HealPartitions removes all the network faults between the servers.
+func (tc *TestCluster) HealPartitions() {
+	if tc.faults != nil {
+		tc.faults.Reset()
+	}
+}
+
 // WaitForStores waits for all of the store descriptors to be gossiped. Servers
 // other than the first "bootstrap" their stores asynchronously, but we'd like
 // to wait for all of the stores to be initialized before returning the
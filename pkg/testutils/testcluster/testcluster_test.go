@@ -26,6 +26,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
 )
 
 func TestManualReplication(t *testing.T) {
@@ -257,3 +259,54 @@ func TestStopServer(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRestartAndPartitionServers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	tc := StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode:     base.ReplicationAuto,
+		RestartableServers:  true,
+		ManualClocks:        true,
+		InjectNetworkFaults: true,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	// The manual clocks drive the HLCs. Advance them together to stay within
+	// the maximum clock offset.
+	before := tc.Server(0).Clock().PhysicalNow()
+	for i := 0; i < tc.NumServers(); i++ {
+		tc.ManualClock(i).Increment(time.Second.Nanoseconds())
+	}
+	require.True(t, tc.Server(0).Clock().PhysicalNow() >= before+time.Second.Nanoseconds())
+
+	// Data written before a restart is still there afterwards.
+	key := roachpb.Key("restart")
+	require.NoError(t, tc.Servers[0].DB().Put(ctx, key, "1"))
+	tc.RestartServer(t, 2)
+	testutils.SucceedsSoon(t, func() error {
+		v, err := tc.Servers[2].DB().Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !v.Exists() {
+			return errors.Errorf("%s not found", key)
+		}
+		return nil
+	})
+
+	// Heartbeats from a partitioned server fail until the partition heals.
+	connHealth := func() error {
+		s, peer := tc.Servers[2], tc.Servers[0]
+		return s.RPCContext().GRPCDialNode(peer.ServingRPCAddr(), peer.NodeID(), rpc.DefaultClass).Health()
+	}
+	tc.Partition(t, 2)
+	testutils.SucceedsSoon(t, func() error {
+		if err := connHealth(); err == nil {
+			return errors.New("connection is still healthy")
+		}
+		return nil
+	})
+	tc.HealPartitions()
+	testutils.SucceedsSoon(t, connHealth)
+}
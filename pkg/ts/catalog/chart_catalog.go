@@ -80,6 +80,42 @@ var charts = []sectionDescription{
 				Aggregator:  DescribeAggregator_MAX,
 				Metrics:     []string{"security.certificate.expiration.ui-ca"},
 			},
+			{
+				Title:       "CA Days Until Expiration",
+				Downsampler: DescribeAggregator_MIN,
+				Aggregator:  DescribeAggregator_MIN,
+				Metrics:     []string{"security.certificate.days_until_expiration.ca"},
+			},
+			{
+				Title:       "Client CA Days Until Expiration",
+				Downsampler: DescribeAggregator_MIN,
+				Aggregator:  DescribeAggregator_MIN,
+				Metrics:     []string{"security.certificate.days_until_expiration.client-ca"},
+			},
+			{
+				Title:       "Node Cert Days Until Expiration",
+				Downsampler: DescribeAggregator_MIN,
+				Aggregator:  DescribeAggregator_MIN,
+				Metrics:     []string{"security.certificate.days_until_expiration.node"},
+			},
+			{
+				Title:       "Node Client Cert Days Until Expiration",
+				Downsampler: DescribeAggregator_MIN,
+				Aggregator:  DescribeAggregator_MIN,
+				Metrics:     []string{"security.certificate.days_until_expiration.node-client"},
+			},
+			{
+				Title:       "UI Cert Days Until Expiration",
+				Downsampler: DescribeAggregator_MIN,
+				Aggregator:  DescribeAggregator_MIN,
+				Metrics:     []string{"security.certificate.days_until_expiration.ui"},
+			},
+			{
+				Title:       "UI CA Cert Days Until Expiration",
+				Downsampler: DescribeAggregator_MIN,
+				Aggregator:  DescribeAggregator_MIN,
+				Metrics:     []string{"security.certificate.days_until_expiration.ui-ca"},
+			},
 		},
 	},
 	{
@@ -321,6 +357,19 @@ var charts = []sectionDescription{
 				Aggregator:  DescribeAggregator_MAX,
 				Metrics:     []string{"gossip.connections.refused"},
 			},
+			{
+				Title:       "Hops",
+				Downsampler: DescribeAggregator_MAX,
+				Aggregator:  DescribeAggregator_MAX,
+				Metrics: []string{
+					"gossip.hops.avg",
+					"gossip.hops.max",
+				},
+			},
+			{
+				Title:   "Anti-Entropy Discrepancies",
+				Metrics: []string{"gossip.antientropy.discrepancies"},
+			},
 		},
 	},
 	{
@@ -540,6 +589,10 @@ var charts = []sectionDescription{
 					"queue.gc.info.resolvetotal",
 				},
 			},
+			{
+				Title:   "Bytes of GC'able Data",
+				Metrics: []string{"queue.gc.info.affectedversionsbytes"},
+			},
 			{
 				Title:   "Keys with GC'able Data",
 				Metrics: []string{"queue.gc.info.numkeysaffected"},
@@ -718,6 +771,7 @@ var charts = []sectionDescription{
 				Title: "Intent Resolver",
 				Metrics: []string{
 					"intentresolver.async.throttled",
+					"intentresolver.pushes",
 				},
 			},
 			{
@@ -934,6 +988,34 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{StorageLayer, "Scrubber Queue"}},
+		Charts: []chartDescription{
+			{
+				Title:   "Pending",
+				Metrics: []string{"queue.scrub.pending"},
+			},
+			{
+				Title: "Successes",
+				Metrics: []string{
+					"queue.scrub.process.failure",
+					"queue.scrub.process.success",
+				},
+			},
+			{
+				Title:   "Time Spent",
+				Metrics: []string{"queue.scrub.processingnanos"},
+			},
+			{
+				Title:   "Keys Verified",
+				Metrics: []string{"queue.scrub.keys"},
+			},
+			{
+				Title:   "Corruptions",
+				Metrics: []string{"queue.scrub.corruptions"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{
 			{ReplicationLayer, "Garbage Collection"},
@@ -1804,6 +1886,10 @@ var charts = []sectionDescription{
 					"rocksdb.block.cache.misses",
 				},
 			},
+			{
+				Title:   "Hit Rate",
+				Metrics: []string{"rocksdb.block.cache.hit-rate"},
+			},
 		},
 	},
 	{
@@ -1849,6 +1935,10 @@ var charts = []sectionDescription{
 				Title:   "Pending Compaction",
 				Metrics: []string{"rocksdb.estimated-pending-compaction"},
 			},
+			{
+				Title:   "Write Stalls",
+				Metrics: []string{"rocksdb.write-stalls"},
+			},
 			{
 				Title:   "Ingestion",
 				Metrics: []string{"rocksdb.ingested-bytes"},
@@ -1871,6 +1961,31 @@ var charts = []sectionDescription{
 				Title:   "Count",
 				Metrics: []string{"rocksdb.num-sstables"},
 			},
+			{
+				Title: "Count by Level",
+				Metrics: []string{
+					"rocksdb.num-sstables.l0",
+					"rocksdb.num-sstables.l1",
+					"rocksdb.num-sstables.l2",
+					"rocksdb.num-sstables.l3",
+					"rocksdb.num-sstables.l4",
+					"rocksdb.num-sstables.l5",
+					"rocksdb.num-sstables.l6",
+				},
+			},
+			{
+				Title: "Size by Level",
+				Metrics: []string{
+					"rocksdb.sstables-size.l0",
+					"rocksdb.sstables-size.l1",
+					"rocksdb.sstables-size.l2",
+					"rocksdb.sstables-size.l3",
+					"rocksdb.sstables-size.l4",
+					"rocksdb.sstables-size.l5",
+					"rocksdb.sstables-size.l6",
+				},
+				AxisLabel: "Bytes",
+			},
 			{
 				Title: "Ingestions",
 				Metrics: []string{
@@ -2005,6 +2120,10 @@ var charts = []sectionDescription{
 				Title:   "Size",
 				Metrics: []string{"timeseries.write.bytes"},
 			},
+			{
+				Title:   "Rollup Size",
+				Metrics: []string{"timeseries.write.rollup.bytes"},
+			},
 		},
 	},
 }
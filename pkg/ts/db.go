@@ -270,6 +270,7 @@ func (db *DB) storeRollup(ctx context.Context, r Resolution, data []rollupData)
 
 func (db *DB) tryStoreRollup(ctx context.Context, r Resolution, data []rollupData) error {
 	var kvs []roachpb.KeyValue
+	var totalSizeOfKvs int64
 
 	for _, d := range data {
 		idatas, err := d.toInternal(r.SlabDuration(), r.SampleDuration())
@@ -286,11 +287,16 @@ func (db *DB) tryStoreRollup(ctx context.Context, r Resolution, data []rollupDat
 				Key:   key,
 				Value: value,
 			})
+			totalSizeOfKvs += int64(len(value.RawBytes)+len(key)) + sizeOfTimestamp
 		}
 	}
 
-	return db.storeKvs(ctx, kvs)
-	// TODO(mrtracy): metrics for rollups stored
+	if err := db.storeKvs(ctx, kvs); err != nil {
+		return err
+	}
+
+	db.metrics.WriteRollupBytes.Inc(totalSizeOfKvs)
+	return nil
 }
 
 func (db *DB) storeKvs(ctx context.Context, kvs []roachpb.KeyValue) error {
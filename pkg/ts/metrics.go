@@ -26,6 +26,12 @@ var (
 		Measurement: "Storage",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaWriteRollupBytes = metric.Metadata{
+		Name:        "timeseries.write.rollup.bytes",
+		Help:        "Total size in bytes of rolled up time series data written to disk",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
 	metaWriteErrors = metric.Metadata{
 		Name:        "timeseries.write.errors",
 		Help:        "Total errors encountered while attempting to write metrics to disk",
@@ -36,16 +42,18 @@ var (
 
 // TimeSeriesMetrics contains metrics relevant to the time series system.
 type TimeSeriesMetrics struct {
-	WriteSamples *metric.Counter
-	WriteBytes   *metric.Counter
-	WriteErrors  *metric.Counter
+	WriteSamples     *metric.Counter
+	WriteBytes       *metric.Counter
+	WriteRollupBytes *metric.Counter
+	WriteErrors      *metric.Counter
 }
 
 // NewTimeSeriesMetrics creates a new instance of TimeSeriesMetrics.
 func NewTimeSeriesMetrics() *TimeSeriesMetrics {
 	return &TimeSeriesMetrics{
-		WriteSamples: metric.NewCounter(metaWriteSamples),
-		WriteBytes:   metric.NewCounter(metaWriteBytes),
-		WriteErrors:  metric.NewCounter(metaWriteErrors),
+		WriteSamples:     metric.NewCounter(metaWriteSamples),
+		WriteBytes:       metric.NewCounter(metaWriteBytes),
+		WriteRollupBytes: metric.NewCounter(metaWriteRollupBytes),
+		WriteErrors:      metric.NewCounter(metaWriteErrors),
 	}
 }
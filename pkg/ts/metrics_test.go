@@ -84,3 +84,28 @@ func TestTimeSeriesWriteMetrics(t *testing.T) {
 		t.Fatalf("write error count was %d, wanted %d", a, e)
 	}
 }
+
+func TestTimeSeriesWriteRollupMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tm := newTestModelRunner(t)
+	tm.Start()
+	defer tm.Stop()
+
+	metrics := tm.DB.Metrics()
+
+	tm.storeTimeSeriesData(resolution50ns, []tspb.TimeSeriesData{
+		tsd("test.metric", "source1",
+			tsdp(1, 100),
+			tsdp(15, 300),
+			tsdp(70, 500),
+		),
+	})
+
+	if a, e := metrics.WriteRollupBytes.Count(), int64(0); a <= e {
+		t.Fatalf("rollup bytes written was %d, wanted more than %d", a, e)
+	}
+	// Rollups are not counted as raw samples.
+	if a, e := metrics.WriteSamples.Count(), int64(0); a != e {
+		t.Fatalf("samples written was %d, wanted %d", a, e)
+	}
+}
@@ -117,6 +117,7 @@ export class Certificates extends React.Component<CertificatesProps, {}> {
       this.renderSimpleRow("Public Key", fields.public_key),
       this.renderMultilineRow("Key Usage", fields.key_usage),
       this.renderMultilineRow("Extended Key Usage", fields.extended_key_usage),
+      this.renderSimpleRow("SHA-256 Fingerprint", fields.fingerprint),
     ];
   }
 
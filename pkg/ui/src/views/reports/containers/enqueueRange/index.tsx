@@ -30,6 +30,7 @@ const QUEUES = [
   "raftlog",
   "raftsnapshot",
   "consistencyChecker",
+  "scrubber",
   "timeSeriesMaintenance",
 ];
 
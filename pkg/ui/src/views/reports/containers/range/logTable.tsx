@@ -35,6 +35,8 @@ function printLogEventType(
       return "Split";
     case protos.cockroach.kv.kvserver.storagepb.RangeLogEventType.merge:
       return "Merge";
+    case protos.cockroach.kv.kvserver.storagepb.RangeLogEventType.scrub_corruption:
+      return "Scrub Corruption";
     default:
       return "Unknown";
   }
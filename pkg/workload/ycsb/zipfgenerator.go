@@ -98,6 +98,28 @@ func NewZipfGenerator(
 	return &z, nil
 }
 
+// WithRand returns a ZipfGenerator drawing from the same distribution as z,
+// using the given RNG instead of the one of z. The hidden parameters are
+// copied rather than recomputed, which is expensive for large values of iMax.
+func (z *ZipfGenerator) WithRand(rng *rand.Rand) *ZipfGenerator {
+	z.zipfGenMu.mu.Lock()
+	defer z.zipfGenMu.mu.Unlock()
+	return &ZipfGenerator{
+		zipfGenMu: ZipfGeneratorMu{
+			r:     rng,
+			iMax:  z.zipfGenMu.iMax,
+			eta:   z.zipfGenMu.eta,
+			zetaN: z.zipfGenMu.zetaN,
+		},
+		theta:        z.theta,
+		iMin:         z.iMin,
+		alpha:        z.alpha,
+		zeta2:        z.zeta2,
+		halfPowTheta: z.halfPowTheta,
+		verbose:      z.verbose,
+	}
+}
+
 // computeZetaIncrementally recomputes zeta(iMax, theta), assuming that
 // sum = zeta(oldIMax, theta). It returns zeta(iMax, theta), computed incrementally.
 func computeZetaIncrementally(oldIMax, iMax uint64, theta float64, sum float64) (float64, error) {
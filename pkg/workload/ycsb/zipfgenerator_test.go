@@ -42,6 +42,28 @@ func TestCreateZipfGenerator(t *testing.T) {
 	}
 }
 
+func TestZipfGeneratorWithRand(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, gen := range gens {
+		z, err := NewZipfGenerator(rand.New(rand.NewSource(1)), gen.iMin, gen.iMax, gen.theta, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A copy seeded like z draws the same values as a generator created
+		// from scratch with that seed.
+		fresh, err := NewZipfGenerator(rand.New(rand.NewSource(2)), gen.iMin, gen.iMax, gen.theta, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := z.WithRand(rand.New(rand.NewSource(2)))
+		for i := 0; i < 1000; i++ {
+			if a, b := c.Uint64(), fresh.Uint64(); a != b {
+				t.Fatalf("%d: expected %d, got %d", i, b, a)
+			}
+		}
+	}
+}
+
 var tests = []struct {
 	n        uint64
 	theta    float64